@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"net/url"
 	"time"
 
 	"booking/internal/domain"
@@ -13,6 +14,8 @@ import (
 	"github.com/medama-io/go-useragent"
 )
 
+const oauthNonceCookie = "oauth_nonce"
+
 type authHandler struct {
 	authUsecase domain.AuthUsecase
 	mw          *middleware.Middleware
@@ -40,6 +43,20 @@ func NewAuthHandler(
 func (h *authHandler) RegisterRoutes(r fiber.Router) {
 	r.Post("/register", h.register)
 	r.Post("/login", h.mw.LoginLimiter(), h.login)
+	r.Post("/phone/otp", h.requestPhoneOtp)
+	r.Post("/phone/login", h.mw.PhoneLoginLimiter(), h.phoneLogin)
+	r.Post("/mfa/verify", h.completeMfaLogin)
+	r.Post("/passkey/login/begin", h.beginPasskeyLogin)
+	r.Post("/passkey/login/finish", h.finishPasskeyLogin)
+	r.Post("/refresh", h.refresh)
+	r.Get("/verify", h.verifyEmail)
+	r.Post("/verify/resend", h.resendVerification)
+	r.Get("/email/confirm", h.confirmEmailChange)
+	r.Get("/oauth/:provider", h.oauthStart)
+	r.Get("/oauth/:provider/callback", h.oauthCallback)
+	r.Get("/oauth/:provider/link", h.mw.Auth(), h.oauthLinkStart)
+	r.Post("/password/forgot", h.forgotPassword)
+	r.Post("/password/reset", h.mw.PasswordResetLimiter(), h.resetPassword)
 	r.Get("/sessions", h.mw.Auth(), h.getAllActiveSessions)
 	r.Delete("/logout", h.mw.Auth(), h.logout)
 }
@@ -78,11 +95,169 @@ func (h *authHandler) login(c fiber.Ctx) error {
 	req.Device = ua.Device().String()
 	req.IpAddress = c.IP()
 
-	res, token, err := h.authUsecase.Login(c.RequestCtx(), &req)
+	res, err := h.authUsecase.Login(c.RequestCtx(), &req)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+
+	return h.loginResponse(c, res)
+}
+
+func (h *authHandler) requestPhoneOtp(c fiber.Ctx) error {
+	var req domain.PhoneOtpRequestDTO
+	if err := c.Bind().Body(&req); err != nil {
+		return err
+	}
+
+	if err := h.authUsecase.RequestPhoneOtp(c.RequestCtx(), &req); err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+	})
+}
+
+func (h *authHandler) phoneLogin(c fiber.Ctx) error {
+	var req domain.PhoneLoginDTO
+	if err := c.Bind().Body(&req); err != nil {
+		return err
+	}
+
+	ua := h.userAgent.Parse(string(c.RequestCtx().UserAgent()))
+	req.UserAgent = string(c.RequestCtx().UserAgent())
+	req.Device = ua.Device().String()
+	req.IpAddress = c.IP()
+
+	res, err := h.authUsecase.LoginWithPhone(c.RequestCtx(), &req)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+
+	return h.loginResponse(c, res)
+}
+
+// completeMfaLogin langkah kedua login untuk user dengan 2fa aktif
+func (h *authHandler) completeMfaLogin(c fiber.Ctx) error {
+	var req domain.MfaLoginDTO
+	if err := c.Bind().Body(&req); err != nil {
+		return err
+	}
+
+	res, err := h.authUsecase.CompleteMfaLogin(c.RequestCtx(), &req)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+
+	return h.loginResponse(c, res)
+}
+
+func (h *authHandler) beginPasskeyLogin(c fiber.Ctx) error {
+	res, err := h.authUsecase.BeginPasskeyLogin(c.RequestCtx())
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
+
+func (h *authHandler) finishPasskeyLogin(c fiber.Ctx) error {
+	var req domain.PasskeyLoginDTO
+	if err := c.Bind().Body(&req); err != nil {
+		return err
+	}
+
+	ua := h.userAgent.Parse(string(c.RequestCtx().UserAgent()))
+	req.UserAgent = string(c.RequestCtx().UserAgent())
+	req.Device = ua.Device().String()
+	req.IpAddress = c.IP()
+
+	res, err := h.authUsecase.FinishPasskeyLogin(c.RequestCtx(), &req)
 	if err != nil {
 		return utils.ErrorResponse(c, err, nil)
 	}
-	// set cookies
+
+	return h.loginResponse(c, res)
+}
+
+func (h *authHandler) loginResponse(c fiber.Ctx, res *domain.LoginResult) error {
+	// mobile: token dikirim di body, gak pakai cookie. 2fa: belum ada session, kirim mfa_token
+	if res.AccessToken != nil || res.MfaRequired {
+		return c.JSON(domain.HttpResponse{
+			Success: true,
+			Data:    res,
+		})
+	}
+
+	h.setSessionCookie(c, res.SessionToken)
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res.User,
+	})
+}
+
+func (h *authHandler) oauthStart(c fiber.Ctx) error {
+	redirectURL, nonce, err := h.authUsecase.OAuthStart(c.RequestCtx(), c.Params("provider"))
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	h.setOAuthNonceCookie(c, nonce, time.Now().Add(h.config.OAuth.StateTtl))
+	return c.Redirect().To(redirectURL)
+}
+
+// oauthLinkStart tautkan provider ke akun yang sedang login, butuh login / re-auth yang masih baru
+func (h *authHandler) oauthLinkStart(c fiber.Ctx) error {
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+	redirectURL, nonce, err := h.authUsecase.OAuthLinkStart(c.RequestCtx(), session.UserID, c.Params("provider"))
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	h.setOAuthNonceCookie(c, nonce, time.Now().Add(h.config.OAuth.StateTtl))
+	return c.Redirect().To(redirectURL)
+}
+
+// oauthCallback login sosial selalu pakai session cookie (browser)
+func (h *authHandler) oauthCallback(c fiber.Ctx) error {
+	var q domain.OAuthCallbackQuery
+	if err := c.Bind().Query(&q); err != nil {
+		return err
+	}
+
+	ua := h.userAgent.Parse(string(c.RequestCtx().UserAgent()))
+	q.UserAgent = string(c.RequestCtx().UserAgent())
+	q.Device = ua.Device().String()
+	q.IpAddress = c.IP()
+	q.Nonce = string(c.Request().Header.Cookie(oauthNonceCookie))
+	q.SessionToken = string(c.Request().Header.Cookie("session"))
+	// nonce cuma untuk satu callback, berhasil atau gagal
+	h.setOAuthNonceCookie(c, "", time.Unix(0, 0))
+
+	res, err := h.authUsecase.OAuthCallback(c.RequestCtx(), c.Params("provider"), &q)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+
+	if res.SessionToken != "" {
+		h.setSessionCookie(c, res.SessionToken)
+	}
+	if h.config.OAuth.SuccessURL != "" {
+		// 2fa aktif: frontend lanjutkan ke POST /auth/mfa/verify dengan token ini
+		if res.MfaRequired {
+			return c.Redirect().To(h.config.OAuth.SuccessURL + "?" + url.Values{"mfa_token": {res.MfaToken}}.Encode())
+		}
+		return c.Redirect().To(h.config.OAuth.SuccessURL)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res.User,
+	})
+}
+
+func (h *authHandler) setSessionCookie(c fiber.Ctx, token string) {
 	c.Cookie(&fiber.Cookie{
 		Name:     "session",
 		Value:    token,
@@ -92,6 +267,109 @@ func (h *authHandler) login(c fiber.Ctx) error {
 		SameSite: "none",
 		Path:     "/",
 	})
+}
+
+// setOAuthNonceCookie SameSite Lax: tetap terkirim saat redirect top-level dari provider ke callback,
+// tapi gak ikut di request lintas situs lain
+func (h *authHandler) setOAuthNonceCookie(c fiber.Ctx, nonce string, expires time.Time) {
+	c.Cookie(&fiber.Cookie{
+		Name:     oauthNonceCookie,
+		Value:    nonce,
+		Expires:  expires,
+		HTTPOnly: true,
+		Secure:   h.config.App.Env == "prod",
+		SameSite: "Lax",
+		Path:     "/",
+	})
+}
+
+func (h *authHandler) verifyEmail(c fiber.Ctx) error {
+	var q domain.VerifyEmailQuery
+	if err := c.Bind().Query(&q); err != nil {
+		return err
+	}
+
+	res, err := h.authUsecase.VerifyEmail(c.RequestCtx(), q.Token)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
+
+// resendVerification selalu sukses untuk email yang gak terdaftar / sudah terverifikasi,
+// supaya endpoint ini gak bisa dipakai untuk cek email terdaftar
+func (h *authHandler) resendVerification(c fiber.Ctx) error {
+	var req domain.ResendVerificationDTO
+	if err := c.Bind().Body(&req); err != nil {
+		return err
+	}
+
+	if err := h.authUsecase.ResendVerification(c.RequestCtx(), &req); err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+	})
+}
+
+func (h *authHandler) confirmEmailChange(c fiber.Ctx) error {
+	var q domain.VerifyEmailQuery
+	if err := c.Bind().Query(&q); err != nil {
+		return err
+	}
+
+	res, err := h.authUsecase.ConfirmEmailChange(c.RequestCtx(), q.Token)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
+
+// forgotPassword selalu 200, hasilnya gak boleh membedakan email terdaftar atau gak
+func (h *authHandler) forgotPassword(c fiber.Ctx) error {
+	var req domain.ForgotPasswordDTO
+	if err := c.Bind().Body(&req); err != nil {
+		return err
+	}
+
+	if err := h.authUsecase.ForgotPassword(c.RequestCtx(), &req); err != nil {
+		h.log.Error(err, "failed to process forgot password")
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+	})
+}
+
+func (h *authHandler) resetPassword(c fiber.Ctx) error {
+	var req domain.ResetPasswordDTO
+	if err := c.Bind().Body(&req); err != nil {
+		return err
+	}
+
+	if err := h.authUsecase.ResetPassword(c.RequestCtx(), &req); err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+	})
+}
+
+func (h *authHandler) refresh(c fiber.Ctx) error {
+	var req domain.RefreshTokenDTO
+	if err := c.Bind().Body(&req); err != nil {
+		return err
+	}
+
+	res, err := h.authUsecase.RefreshToken(c.RequestCtx(), &req)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
 	return c.JSON(domain.HttpResponse{
 		Success: true,
 		Data:    res,
@@ -99,8 +377,11 @@ func (h *authHandler) login(c fiber.Ctx) error {
 }
 
 func (h *authHandler) getAllActiveSessions(c fiber.Ctx) error {
-	userId := c.Locals(domain.SessionCtxKey).(*domain.Session).UserID
-	sessions, err := h.authUsecase.GetAllActiveSessions(c.RequestCtx(), userId)
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+	sessions, err := h.authUsecase.GetAllActiveSessions(c.RequestCtx(), session.UserID)
 	if err != nil {
 		return utils.ErrorResponse(c, err, nil)
 	}
@@ -111,8 +392,22 @@ func (h *authHandler) getAllActiveSessions(c fiber.Ctx) error {
 }
 
 func (h *authHandler) logout(c fiber.Ctx) error {
-	session := c.Locals(domain.SessionCtxKey).(*domain.Session)
-	sessionToken := c.Locals(domain.SessionTokenCtxKey).(string)
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+	sessionToken, ok := c.Locals(domain.SessionTokenCtxKey).(string)
+	if !ok {
+		// login via jwt, revoke refresh token family nya
+		if familyID, _ := c.Locals(domain.TokenFamilyCtxKey).(string); familyID != "" {
+			if err := h.authUsecase.RevokeTokenFamily(c.RequestCtx(), session.UserID, familyID); err != nil {
+				h.log.Error(err, "failed to revoke token family")
+			}
+		}
+		return c.JSON(domain.HttpResponse{
+			Success: true,
+		})
+	}
 
 	err := h.authUsecase.Logout(c.RequestCtx(), session.UserID, sessionToken)
 	if err != nil {
@@ -2,23 +2,32 @@ package usecase
 
 import (
 	"context"
+	"errors"
 
 	"booking/internal/domain"
 	"booking/pkg/logger"
+	"booking/pkg/oauth"
 	"booking/pkg/security"
 )
 
 type authUsecase struct {
-	userUsecase domain.UserUsecase
-	security    *security.Security
-	log         logger.Logger
+	userUsecase    domain.UserUsecase
+	security       *security.Security
+	oauthProviders map[string]domain.OAuthProvider
+	log            logger.Logger
 }
 
-func NewAuthUsecase(userUsecase domain.UserUsecase, security *security.Security, log logger.Logger) domain.AuthUsecase {
+func NewAuthUsecase(
+	userUsecase domain.UserUsecase,
+	security *security.Security,
+	oauthProviders map[string]domain.OAuthProvider,
+	log logger.Logger,
+) domain.AuthUsecase {
 	return &authUsecase{
-		userUsecase: userUsecase,
-		security:    security,
-		log:         log,
+		userUsecase:    userUsecase,
+		security:       security,
+		oauthProviders: oauthProviders,
+		log:            log,
 	}
 }
 
@@ -31,14 +40,134 @@ func (u *authUsecase) RegisterUser(ctx context.Context, req *domain.RegisterDTO)
 	return res, nil
 }
 
-func (u *authUsecase) Login(ctx context.Context, req *domain.LoginDTO) (*domain.UserWithIdentity, string, error) {
+func (u *authUsecase) Login(ctx context.Context, req *domain.LoginDTO) (*domain.LoginResult, error) {
 	// get user by email
-	res, token, err := u.userUsecase.Login(ctx, req)
+	res, err := u.userUsecase.Login(ctx, req)
 	if err != nil {
-		return nil, "", err
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (u *authUsecase) VerifyEmail(ctx context.Context, token string) (*domain.UserWithIdentity, error) {
+	return u.userUsecase.VerifyEmail(ctx, token)
+}
+
+func (u *authUsecase) ResendVerification(ctx context.Context, req *domain.ResendVerificationDTO) error {
+	return u.userUsecase.ResendVerification(ctx, req)
+}
+
+func (u *authUsecase) RequestPhoneOtp(ctx context.Context, req *domain.PhoneOtpRequestDTO) error {
+	return u.userUsecase.RequestPhoneOtp(ctx, req)
+}
+
+func (u *authUsecase) LoginWithPhone(ctx context.Context, req *domain.PhoneLoginDTO) (*domain.LoginResult, error) {
+	return u.userUsecase.LoginWithPhone(ctx, req)
+}
+
+func (u *authUsecase) CompleteMfaLogin(ctx context.Context, req *domain.MfaLoginDTO) (*domain.LoginResult, error) {
+	return u.userUsecase.CompleteMfaLogin(ctx, req)
+}
+
+func (u *authUsecase) BeginPasskeyLogin(ctx context.Context) (*domain.PasskeyCeremony, error) {
+	return u.userUsecase.BeginPasskeyLogin(ctx)
+}
+
+func (u *authUsecase) FinishPasskeyLogin(ctx context.Context, req *domain.PasskeyLoginDTO) (*domain.LoginResult, error) {
+	return u.userUsecase.FinishPasskeyLogin(ctx, req)
+}
+
+func (u *authUsecase) ForgotPassword(ctx context.Context, req *domain.ForgotPasswordDTO) error {
+	return u.userUsecase.ForgotPassword(ctx, req)
+}
+
+func (u *authUsecase) ResetPassword(ctx context.Context, req *domain.ResetPasswordDTO) error {
+	return u.userUsecase.ResetPassword(ctx, req)
+}
+
+func (u *authUsecase) ConfirmEmailChange(ctx context.Context, token string) (*domain.UserWithIdentity, error) {
+	return u.userUsecase.ConfirmEmailChange(ctx, token)
+}
+
+// OAuthStart return url authorize provider dan nonce untuk cookie browser, state & code verifier disimpan di redis
+func (u *authUsecase) OAuthStart(ctx context.Context, provider string) (string, string, error) {
+	p, ok := u.oauthProviders[provider]
+	if !ok {
+		return "", "", domain.ErrOAuthProviderNotFound
+	}
+
+	state, verifier, nonce, err := u.security.CreateOAuthState(ctx, provider, "")
+	if err != nil {
+		return "", "", domain.ErrInternalServerError
 	}
 
-	return res, token, nil
+	return p.AuthCodeURL(state, oauth.CodeChallenge(verifier)), nonce, nil
+}
+
+// OAuthLinkStart sama dengan OAuthStart tapi hasil callback nya ditautkan ke user yang sedang login
+func (u *authUsecase) OAuthLinkStart(ctx context.Context, userID, provider string) (string, string, error) {
+	p, ok := u.oauthProviders[provider]
+	if !ok {
+		return "", "", domain.ErrOAuthProviderNotFound
+	}
+	if err := u.userUsecase.RequireRecentAuth(ctx); err != nil {
+		return "", "", err
+	}
+
+	state, verifier, nonce, err := u.security.CreateOAuthState(ctx, provider, userID)
+	if err != nil {
+		return "", "", domain.ErrInternalServerError
+	}
+
+	return p.AuthCodeURL(state, oauth.CodeChallenge(verifier)), nonce, nil
+}
+
+func (u *authUsecase) OAuthCallback(ctx context.Context, provider string, q *domain.OAuthCallbackQuery) (*domain.LoginResult, error) {
+	p, ok := u.oauthProviders[provider]
+	if !ok {
+		return nil, domain.ErrOAuthProviderNotFound
+	}
+	if q.State == "" {
+		return nil, domain.ErrInvalidOAuthState
+	}
+
+	// state tetap dihapus walaupun user menolak akses
+	verifier, linkUserID, err := u.security.ConsumeOAuthState(ctx, q.State, provider, q.Nonce)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidOAuthState) {
+			return nil, err
+		}
+		return nil, domain.ErrInternalServerError
+	}
+	// state tautkan akun sudah terikat ke browser lewat nonce. kalau browser nya login pakai
+	// session user lain (ganti akun di tengah flow), tautan ditolak sebelum code ditukar
+	if linkUserID != "" && q.SessionToken != "" {
+		session, _, err := u.security.GetSession(ctx, q.SessionToken)
+		if err != nil || session.UserID != linkUserID {
+			return nil, domain.ErrInvalidOAuthState
+		}
+	}
+	if q.Error != "" || q.Code == "" {
+		return nil, domain.ErrOAuthFailed
+	}
+
+	info, err := p.Exchange(ctx, q.Code, verifier)
+	if err != nil {
+		u.log.Error(err, "failed to exchange oauth code")
+		return nil, domain.ErrOAuthFailed
+	}
+
+	// flow tautkan akun gak bikin session baru
+	if linkUserID != "" {
+		res, err := u.userUsecase.LinkOAuthIdentity(ctx, linkUserID, info)
+		if err != nil {
+			return nil, err
+		}
+		return &domain.LoginResult{User: res}, nil
+	}
+
+	return u.userUsecase.LoginWithOAuth(ctx, info, q.Device, q.UserAgent, q.IpAddress)
 }
 
 func (u *authUsecase) GetAllActiveSessions(ctx context.Context, userId string) ([]domain.SessionWithExpiry, error) {
@@ -48,3 +177,43 @@ func (u *authUsecase) GetAllActiveSessions(ctx context.Context, userId string) (
 func (u *authUsecase) Logout(ctx context.Context, userID string, token string) error {
 	return u.security.LogoutSession(ctx, userID, token)
 }
+
+func (u *authUsecase) RefreshToken(ctx context.Context, req *domain.RefreshTokenDTO) (*domain.AccessToken, error) {
+	rt, newRefreshToken, err := u.security.RotateRefreshToken(ctx, req.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// ambil data user terbaru, supaya perubahan role / profile langsung masuk ke access token baru
+	user, err := u.userUsecase.GetByIdentityID(ctx, rt.IdentityID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			_ = u.security.RevokeRefreshFamily(ctx, rt.UserID, rt.FamilyID)
+			return nil, domain.ErrInvalidToken
+		}
+		return nil, err
+	}
+	if user.User.Disabled() {
+		_ = u.security.RevokeRefreshFamily(ctx, rt.UserID, rt.FamilyID)
+		return nil, domain.ErrAccountDisabled
+	}
+
+	orgID, err := u.security.GetFamilyOrg(ctx, rt.FamilyID)
+	if err != nil {
+		u.log.Error(err, "failed to get refresh token family org")
+		return nil, domain.ErrInternalServerError
+	}
+
+	accessToken, err := u.security.GenerateAccessToken(user, rt.Device, rt.FamilyID, orgID)
+	if err != nil {
+		u.log.Error(err, "failed to generate access token")
+		return nil, domain.ErrInternalServerError
+	}
+	accessToken.RefreshToken = newRefreshToken
+
+	return accessToken, nil
+}
+
+func (u *authUsecase) RevokeTokenFamily(ctx context.Context, userID string, familyID string) error {
+	return u.security.RevokeRefreshFamily(ctx, userID, familyID)
+}
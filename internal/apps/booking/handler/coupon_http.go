@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"booking/internal/domain"
+	"booking/internal/server/middleware"
+	"booking/pkg/logger"
+	"booking/pkg/utils"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type couponHandler struct {
+	couponUsecase domain.CouponUsecase
+	mw            *middleware.Middleware
+	log           logger.Logger
+}
+
+func NewCouponHandler(couponUsecase domain.CouponUsecase, mw *middleware.Middleware, log logger.Logger) *couponHandler {
+	return &couponHandler{couponUsecase: couponUsecase, mw: mw, log: log}
+}
+
+// RegisterRoutes - /coupons
+func (h *couponHandler) RegisterRoutes(r fiber.Router) {
+	r.Use(h.mw.Auth(), h.mw.ResolveOrg(), h.mw.RequirePermission(domain.PermCouponManage))
+	r.Post("/", h.create)
+	r.Get("/", h.list)
+}
+
+func (h *couponHandler) create(c fiber.Ctx) error {
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+
+	var req domain.CreateCouponDTO
+	if err := c.Bind().Body(&req); err != nil {
+		return err
+	}
+	req.CreatedBy = session.UserID
+
+	res, err := h.couponUsecase.Create(c.RequestCtx(), &req)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.Status(fiber.StatusCreated).JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
+
+func (h *couponHandler) list(c fiber.Ctx) error {
+	if _, ok := domain.SessionFromContext(c.RequestCtx()); !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+
+	var q domain.PaginationQuery
+	if err := c.Bind().Query(&q); err != nil {
+		return err
+	}
+
+	res, err := h.couponUsecase.List(c.RequestCtx(), &q)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
@@ -0,0 +1,380 @@
+package handler
+
+import (
+	"booking/internal/domain"
+	"booking/internal/server/middleware"
+	"booking/pkg/logger"
+	"booking/pkg/utils"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type bookingHandler struct {
+	bookingUsecase domain.BookingUsecase
+	mw             *middleware.Middleware
+	log            logger.Logger
+}
+
+func NewBookingHandler(bookingUsecase domain.BookingUsecase, mw *middleware.Middleware, log logger.Logger) *bookingHandler {
+	return &bookingHandler{bookingUsecase: bookingUsecase, mw: mw, log: log}
+}
+
+// RegisterRoutes - /bookings
+func (h *bookingHandler) RegisterRoutes(r fiber.Router) {
+	r.Use(h.mw.Auth(), h.mw.ResolveOrg())
+
+	create := h.mw.RequirePermission(domain.PermBookingCreate)
+	r.Post("/", create, h.create)
+	r.Post("/quote", h.quote)
+	r.Post("/holds", create, h.createHold)
+	r.Post("/holds/:id/confirm", create, h.confirmHold)
+	r.Delete("/holds/:id", h.releaseHold)
+	r.Post("/waitlist", create, h.joinWaitlist)
+	r.Get("/waitlist", h.listWaitlist)
+	r.Delete("/waitlist/:id", h.leaveWaitlist)
+	r.Post("/waitlist/:id/accept", create, h.acceptWaitlistOffer)
+	r.Post("/series", create, h.createSeries)
+	r.Get("/series/:id", h.getSeries)
+	r.Get("/:id", h.getByID)
+	r.Post("/:id/cancel", h.cancel)
+	r.Post("/:id/move", h.move)
+	r.Put("/:id/status", h.updateStatus)
+	r.Get("/:id/events", h.listEvents)
+}
+
+// RegisterMeRoutes - /me, booking user di org aktif
+func (h *bookingHandler) RegisterMeRoutes(r fiber.Router) {
+	r.Use(h.mw.Auth(), h.mw.ResolveOrg())
+	r.Get("/bookings", h.listMine)
+}
+
+func (h *bookingHandler) create(c fiber.Ctx) error {
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+
+	var req domain.CreateBookingDTO
+	if err := c.Bind().Body(&req); err != nil {
+		return err
+	}
+	req.UserID = session.UserID
+
+	res, err := h.bookingUsecase.Create(c.RequestCtx(), &req)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.Status(fiber.StatusCreated).JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
+
+func (h *bookingHandler) quote(c fiber.Ctx) error {
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+
+	var req domain.QuoteDTO
+	if err := c.Bind().Body(&req); err != nil {
+		return err
+	}
+	req.UserID = session.UserID
+
+	res, err := h.bookingUsecase.Quote(c.RequestCtx(), &req)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
+
+func (h *bookingHandler) getByID(c fiber.Ctx) error {
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+
+	res, err := h.bookingUsecase.GetByID(c.RequestCtx(), session.UserID, c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
+
+func (h *bookingHandler) listMine(c fiber.Ctx) error {
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+
+	var q domain.PaginationQuery
+	if err := c.Bind().Query(&q); err != nil {
+		return err
+	}
+
+	res, err := h.bookingUsecase.ListByUser(c.RequestCtx(), session.UserID, &q)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
+
+func (h *bookingHandler) cancel(c fiber.Ctx) error {
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+
+	// body opsional, default scope this
+	req := domain.CancelBookingDTO{Scope: domain.ScopeThis}
+	if len(c.Body()) > 0 {
+		if err := c.Bind().Body(&req); err != nil {
+			return err
+		}
+	}
+
+	res, err := h.bookingUsecase.Cancel(c.RequestCtx(), session.UserID, c.Params("id"), &req)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
+
+func (h *bookingHandler) move(c fiber.Ctx) error {
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+
+	var req domain.MoveBookingDTO
+	if err := c.Bind().Body(&req); err != nil {
+		return err
+	}
+
+	res, err := h.bookingUsecase.Move(c.RequestCtx(), session.UserID, c.Params("id"), &req)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
+
+func (h *bookingHandler) updateStatus(c fiber.Ctx) error {
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+
+	var req domain.UpdateBookingStatusDTO
+	if err := c.Bind().Body(&req); err != nil {
+		return err
+	}
+
+	res, err := h.bookingUsecase.UpdateStatus(c.RequestCtx(), session.UserID, c.Params("id"), &req)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
+
+func (h *bookingHandler) listEvents(c fiber.Ctx) error {
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+
+	res, err := h.bookingUsecase.ListEvents(c.RequestCtx(), session.UserID, c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
+
+func (h *bookingHandler) createSeries(c fiber.Ctx) error {
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+
+	var req domain.CreateSeriesDTO
+	if err := c.Bind().Body(&req); err != nil {
+		return err
+	}
+	req.UserID = session.UserID
+
+	res, err := h.bookingUsecase.CreateSeries(c.RequestCtx(), &req)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.Status(fiber.StatusCreated).JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
+
+func (h *bookingHandler) getSeries(c fiber.Ctx) error {
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+
+	res, err := h.bookingUsecase.GetSeries(c.RequestCtx(), session.UserID, c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
+
+func (h *bookingHandler) createHold(c fiber.Ctx) error {
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+
+	var req domain.CreateHoldDTO
+	if err := c.Bind().Body(&req); err != nil {
+		return err
+	}
+	req.UserID = session.UserID
+
+	res, err := h.bookingUsecase.CreateHold(c.RequestCtx(), &req)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.Status(fiber.StatusCreated).JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
+
+func (h *bookingHandler) confirmHold(c fiber.Ctx) error {
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+
+	var req domain.ConfirmHoldDTO
+	if err := c.Bind().Body(&req); err != nil {
+		return err
+	}
+
+	res, err := h.bookingUsecase.ConfirmHold(c.RequestCtx(), session.UserID, c.Params("id"), &req)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.Status(fiber.StatusCreated).JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
+
+func (h *bookingHandler) releaseHold(c fiber.Ctx) error {
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+
+	if err := h.bookingUsecase.ReleaseHold(c.RequestCtx(), session.UserID, c.Params("id")); err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+	})
+}
+
+func (h *bookingHandler) joinWaitlist(c fiber.Ctx) error {
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+
+	var req domain.JoinWaitlistDTO
+	if err := c.Bind().Body(&req); err != nil {
+		return err
+	}
+	req.UserID = session.UserID
+
+	res, err := h.bookingUsecase.JoinWaitlist(c.RequestCtx(), &req)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.Status(fiber.StatusCreated).JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
+
+func (h *bookingHandler) listWaitlist(c fiber.Ctx) error {
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+
+	var q domain.PaginationQuery
+	if err := c.Bind().Query(&q); err != nil {
+		return err
+	}
+
+	res, err := h.bookingUsecase.ListWaitlist(c.RequestCtx(), session.UserID, &q)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
+
+func (h *bookingHandler) leaveWaitlist(c fiber.Ctx) error {
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+
+	if err := h.bookingUsecase.LeaveWaitlist(c.RequestCtx(), session.UserID, c.Params("id")); err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+	})
+}
+
+func (h *bookingHandler) acceptWaitlistOffer(c fiber.Ctx) error {
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+
+	res, err := h.bookingUsecase.AcceptWaitlistOffer(c.RequestCtx(), session.UserID, c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.Status(fiber.StatusCreated).JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
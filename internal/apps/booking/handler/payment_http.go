@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"booking/internal/domain"
+	"booking/internal/server/middleware"
+	"booking/pkg/logger"
+	"booking/pkg/utils"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type paymentHandler struct {
+	paymentUsecase domain.PaymentUsecase
+	mw             *middleware.Middleware
+	log            logger.Logger
+}
+
+func NewPaymentHandler(paymentUsecase domain.PaymentUsecase, mw *middleware.Middleware, log logger.Logger) *paymentHandler {
+	return &paymentHandler{paymentUsecase: paymentUsecase, mw: mw, log: log}
+}
+
+// RegisterRoutes - /payments
+func (h *paymentHandler) RegisterRoutes(r fiber.Router) {
+	// dipanggil provider, autentikasi nya lewat signature
+	r.Post("/webhook", h.webhook)
+	r.Get("/bookings/:id", h.mw.Auth(), h.mw.ResolveOrg(), h.getByBooking)
+}
+
+func (h *paymentHandler) webhook(c fiber.Ctx) error {
+	if err := h.paymentUsecase.HandleWebhook(c.RequestCtx(), c.Body(), c.Get(domain.PaymentSignatureHeader)); err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+	})
+}
+
+func (h *paymentHandler) getByBooking(c fiber.Ctx) error {
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+
+	res, err := h.paymentUsecase.GetByReservation(c.RequestCtx(), session.UserID, c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
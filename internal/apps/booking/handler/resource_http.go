@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"booking/internal/domain"
+	"booking/internal/server/middleware"
+	"booking/pkg/logger"
+	"booking/pkg/utils"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type resourceHandler struct {
+	resourceUsecase domain.ResourceUsecase
+	mw              *middleware.Middleware
+	log             logger.Logger
+}
+
+func NewResourceHandler(resourceUsecase domain.ResourceUsecase, mw *middleware.Middleware, log logger.Logger) *resourceHandler {
+	return &resourceHandler{resourceUsecase: resourceUsecase, mw: mw, log: log}
+}
+
+// RegisterRoutes - /resources
+func (h *resourceHandler) RegisterRoutes(r fiber.Router) {
+	// ResolveOrg setelah Auth supaya role di org ikut dihitung
+	org := h.mw.ResolveOrg()
+	r.Get("/", org, h.list)
+	r.Get("/:id", org, h.getByID)
+	r.Get("/:id/availability", org, h.getAvailability)
+	r.Get("/:id/rate-plan", org, h.getRatePlan)
+
+	manage := h.mw.RequirePermission(domain.PermResourceManage)
+	r.Post("/", h.mw.Auth(), org, manage, h.create)
+	r.Post("/:id/exceptions", h.mw.Auth(), org, manage, h.addException)
+	r.Put("/:id/rate-plan", h.mw.Auth(), org, manage, h.setRatePlan)
+	r.Put("/:id/cancellation-policy", h.mw.Auth(), org, manage, h.setCancellationPolicy)
+}
+
+func (h *resourceHandler) create(c fiber.Ctx) error {
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+
+	var req domain.CreateResourceDTO
+	if err := c.Bind().Body(&req); err != nil {
+		return err
+	}
+	req.OwnerID = session.UserID
+
+	res, err := h.resourceUsecase.Create(c.RequestCtx(), &req)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.Status(fiber.StatusCreated).JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
+
+func (h *resourceHandler) getByID(c fiber.Ctx) error {
+	res, err := h.resourceUsecase.GetByID(c.RequestCtx(), c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
+
+func (h *resourceHandler) list(c fiber.Ctx) error {
+	var q domain.PaginationQuery
+	if err := c.Bind().Query(&q); err != nil {
+		return err
+	}
+
+	res, err := h.resourceUsecase.List(c.RequestCtx(), &q)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
+
+func (h *resourceHandler) addException(c fiber.Ctx) error {
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+
+	var req domain.CreateAvailabilityExceptionDTO
+	if err := c.Bind().Body(&req); err != nil {
+		return err
+	}
+	req.ResourceID = c.Params("id")
+
+	res, err := h.resourceUsecase.AddException(c.RequestCtx(), session.UserID, &req)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.Status(fiber.StatusCreated).JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
+
+func (h *resourceHandler) getAvailability(c fiber.Ctx) error {
+	var q domain.AvailabilityQuery
+	if err := c.Bind().Query(&q); err != nil {
+		return err
+	}
+
+	res, err := h.resourceUsecase.GetAvailability(c.RequestCtx(), c.Params("id"), &q)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
+
+func (h *resourceHandler) getRatePlan(c fiber.Ctx) error {
+	res, err := h.resourceUsecase.GetRatePlan(c.RequestCtx(), c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
+
+func (h *resourceHandler) setRatePlan(c fiber.Ctx) error {
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+
+	var req domain.UpsertRatePlanDTO
+	if err := c.Bind().Body(&req); err != nil {
+		return err
+	}
+	req.ResourceID = c.Params("id")
+
+	res, err := h.resourceUsecase.SetRatePlan(c.RequestCtx(), session.UserID, &req)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
+
+func (h *resourceHandler) setCancellationPolicy(c fiber.Ctx) error {
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+
+	var req domain.SetCancellationPolicyDTO
+	if err := c.Bind().Body(&req); err != nil {
+		return err
+	}
+	req.ResourceID = c.Params("id")
+
+	res, err := h.resourceUsecase.SetCancellationPolicy(c.RequestCtx(), session.UserID, &req)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"booking/internal/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	availabilityKey        = "availability"
+	availabilityVersionKey = "availability_version"
+	availabilityCacheTtl   = 5 * time.Minute
+)
+
+// Invalidate gak hapus key satu-satu (butuh SCAN), tapi naikin versi resource.
+// Key dengan versi lama otomatis gak kepakai lagi dan hilang sendiri setelah TTL.
+type availabilityCache struct {
+	rdb *redis.Client
+}
+
+func NewAvailabilityCache(rdb *redis.Client) domain.AvailabilityCache {
+	return &availabilityCache{rdb: rdb}
+}
+
+func (c *availabilityCache) Get(ctx context.Context, resourceID string, q *domain.AvailabilityQuery) ([]domain.Slot, bool, error) {
+	key, err := c.key(ctx, resourceID, q)
+	if err != nil {
+		return nil, false, err
+	}
+
+	val, err := c.rdb.Get(ctx, key).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	var slots []domain.Slot
+	if err := json.Unmarshal(val, &slots); err != nil {
+		return nil, false, err
+	}
+	return slots, true, nil
+}
+
+func (c *availabilityCache) Set(ctx context.Context, resourceID string, q *domain.AvailabilityQuery, slots []domain.Slot, expiresAt time.Time) error {
+	ttl := availabilityCacheTtl
+	if !expiresAt.IsZero() {
+		if until := time.Until(expiresAt); until < ttl {
+			ttl = until
+		}
+	}
+	// sudah lewat, hasil nya basi begitu ditulis
+	if ttl <= 0 {
+		return nil
+	}
+
+	key, err := c.key(ctx, resourceID, q)
+	if err != nil {
+		return err
+	}
+
+	val, err := json.Marshal(slots)
+	if err != nil {
+		return err
+	}
+	return c.rdb.Set(ctx, key, val, ttl).Err()
+}
+
+func (c *availabilityCache) Invalidate(ctx context.Context, resourceID string) error {
+	return c.rdb.Incr(ctx, generateAvailabilityVersionKey(resourceID)).Err()
+}
+
+// key ikut org, slot yang di-cache dari org lain gak akan kebaca walau resource id nya ditebak
+func (c *availabilityCache) key(ctx context.Context, resourceID string, q *domain.AvailabilityQuery) (string, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	version, err := c.rdb.Get(ctx, generateAvailabilityVersionKey(resourceID)).Int64()
+	if err != nil && err != redis.Nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s:%s:%s:v%d:%s:%s:%d:%d", availabilityKey, org, resourceID, version, q.From, q.To, q.Duration, q.PartySize), nil
+}
+
+func generateAvailabilityVersionKey(resourceID string) string {
+	return fmt.Sprintf("%s:%s", availabilityVersionKey, resourceID)
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+
+	"booking/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type bookingEventRepository struct {
+	DB *sqlx.DB
+}
+
+func NewBookingEventRepository(db *sqlx.DB) domain.BookingEventRepository {
+	return &bookingEventRepository{
+		DB: db,
+	}
+}
+
+func (r *bookingEventRepository) Create(ctx context.Context, tx *sqlx.Tx, event *domain.BookingEvent) error {
+	org, err := orgID(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO booking_events (id, reservation_id, actor_id, actor_type, from_status, to_status, reason)
+			SELECT $1, id, $3, $4, $5, $6, $7
+			FROM reservations WHERE id = $2 AND org_id = $8
+	`
+
+	result, err := tx.ExecContext(ctx, query,
+		event.ID,
+		event.ReservationID,
+		event.ActorID,
+		event.ActorType,
+		event.FromStatus,
+		event.ToStatus,
+		event.Reason,
+		org,
+	)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrBookingNotFound
+	}
+	return nil
+}
+
+func (r *bookingEventRepository) ListByReservation(ctx context.Context, reservationID string) ([]domain.BookingEvent, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []domain.BookingEvent{}
+
+	query := `
+		SELECT id, reservation_id, actor_id, actor_type, from_status, to_status, reason, created_at
+		FROM booking_events
+		WHERE reservation_id = $1
+			AND EXISTS (SELECT 1 FROM reservations r WHERE r.id = reservation_id AND r.org_id = $2)
+		ORDER BY created_at, id
+	`
+
+	err = r.DB.SelectContext(ctx, &res, query, reservationID, org)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
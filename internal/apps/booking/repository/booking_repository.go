@@ -0,0 +1,399 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"booking/internal/domain"
+	"booking/pkg/constant"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+)
+
+type bookingRepository struct {
+	DB *sqlx.DB
+}
+
+func NewBookingRepository(db *sqlx.DB) domain.BookingRepository {
+	return &bookingRepository{
+		DB: db,
+	}
+}
+
+const reservationColumns = `
+	id, org_id, resource_id, user_id, start_time, end_time, status, party_size, notes, cancelled_at, created_at, updated_at,
+	series_id, occurrence_start, price_snapshot, cancellation_policy, payment_due_at
+`
+
+// releasedStatuses - status reservasi yang sudah gak memakai slot, sama dengan WHERE exclusion constraint
+var releasedStatuses = []string{domain.ReservationStatusCancelled, domain.ReservationStatusExpired}
+
+// reservasi aktif yang overlap dengan [$3, $4), kecuali id di $5. $6 = org
+const selectOverlapQuery = `
+	SELECT ` + reservationColumns + `
+	FROM reservations
+	WHERE resource_id = $1
+		AND org_id = $6
+		AND status <> ALL($2::text[])
+		AND period && tstzrange($3, $4, '[)')
+		AND NOT (id = ANY($5::uuid[]))
+	ORDER BY start_time
+`
+
+func (r *bookingRepository) Create(ctx context.Context, tx *sqlx.Tx, req *domain.CreateBookingDTO) (*domain.Reservation, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res domain.Reservation
+
+	var (
+		priceAmount   int64
+		priceCurrency *string
+	)
+	if req.Price != nil {
+		priceAmount = req.Price.Total
+		priceCurrency = &req.Price.Currency
+	}
+	query := `
+		INSERT INTO reservations (
+			id, org_id, resource_id, user_id, start_time, end_time, status, notes, series_id, occurrence_start, party_size,
+			exclusive, price_amount, price_currency, price_snapshot, cancellation_policy, payment_due_at
+		)
+			VALUES (
+				$1, $15, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, $9, $10,
+				(SELECT capacity = 1 FROM resources WHERE id = $2 AND org_id = $15), $11, $12, $13,
+				(SELECT cancellation_policy FROM resources WHERE id = $2 AND org_id = $15), $14
+			)
+		RETURNING ` + reservationColumns
+
+	err = tx.QueryRowxContext(ctx, query,
+		req.ID,
+		req.ResourceID,
+		req.UserID,
+		req.StartTime,
+		req.EndTime,
+		req.Status,
+		req.Notes,
+		req.SeriesID,
+		req.OccurrenceStart,
+		req.PartySize,
+		priceAmount,
+		priceCurrency,
+		req.Price,
+		req.PaymentDueAt,
+		org,
+	).StructScan(&res)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == constant.PgErrExclusionViolation {
+			return nil, r.slotUnavailable(ctx, org, req.ResourceID, req.StartTime, req.EndTime)
+		}
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (r *bookingRepository) GetByID(ctx context.Context, id string) (*domain.Reservation, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res domain.Reservation
+
+	query := `SELECT ` + reservationColumns + ` FROM reservations WHERE id = $1 AND org_id = $2`
+
+	err = r.DB.GetContext(ctx, &res, query, id, org)
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (r *bookingRepository) GetByIDForUpdate(ctx context.Context, tx *sqlx.Tx, id string) (*domain.Reservation, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res domain.Reservation
+
+	query := `SELECT ` + reservationColumns + ` FROM reservations WHERE id = $1 AND org_id = $2 FOR UPDATE`
+
+	err = tx.GetContext(ctx, &res, query, id, org)
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (r *bookingRepository) ListByUser(ctx context.Context, userID string, limit, offset int) ([]domain.Reservation, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []domain.Reservation{}
+
+	query := `
+		SELECT ` + reservationColumns + `
+		FROM reservations
+		WHERE org_id = $1 AND user_id = $2
+		ORDER BY start_time DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	err = r.DB.SelectContext(ctx, &res, query, org, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ListOverlapping reservasi aktif yang overlap dengan [start, end).
+// excludeIDs dipakai saat memindah reservasi supaya gak bentrok dengan dirinya sendiri
+func (r *bookingRepository) ListOverlapping(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	resourceID string,
+	start, end time.Time,
+	excludeIDs []string,
+) ([]domain.Reservation, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []domain.Reservation{}
+
+	if excludeIDs == nil {
+		excludeIDs = []string{}
+	}
+	err = tx.SelectContext(ctx, &res, selectOverlapQuery, resourceID, releasedStatuses, start, end, excludeIDs, org)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ListActiveByResource reservasi aktif di resource yang overlap dengan [from, to)
+func (r *bookingRepository) ListActiveByResource(ctx context.Context, resourceID string, from, to time.Time) ([]domain.Reservation, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []domain.Reservation{}
+
+	query := `
+		SELECT ` + reservationColumns + `
+		FROM reservations
+		WHERE resource_id = $1
+			AND org_id = $5
+			AND status <> ALL($2::text[])
+			AND period && tstzrange($3, $4, '[)')
+		ORDER BY start_time
+	`
+
+	err = r.DB.SelectContext(ctx, &res, query, resourceID, releasedStatuses, from, to, org)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (r *bookingRepository) UpdateStatus(ctx context.Context, tx *sqlx.Tx, id, status string) (*domain.Reservation, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res domain.Reservation
+
+	query := `
+		UPDATE reservations
+		SET status = $2,
+			cancelled_at = CASE WHEN $2 = 'cancelled' THEN now() ELSE cancelled_at END,
+			updated_at = now()
+		WHERE id = $1 AND org_id = $3
+		RETURNING ` + reservationColumns
+
+	err = tx.QueryRowxContext(ctx, query, id, status, org).StructScan(&res)
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (r *bookingRepository) UpdateTime(ctx context.Context, tx *sqlx.Tx, id string, start, end time.Time) (*domain.Reservation, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res domain.Reservation
+
+	query := `
+		UPDATE reservations
+		SET start_time = $2, end_time = $3, updated_at = now()
+		WHERE id = $1 AND org_id = $4
+		RETURNING ` + reservationColumns
+
+	err = tx.QueryRowxContext(ctx, query, id, start, end, org).StructScan(&res)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == constant.PgErrExclusionViolation {
+			return nil, &domain.SlotUnavailableError{StartTime: start, EndTime: end}
+		}
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// =============================
+// BOOKING SERIES
+// =============================
+
+const seriesColumns = `id, org_id, resource_id, user_id, rrule, start_time, duration_minutes, timezone, notes, created_at, updated_at`
+
+func (r *bookingRepository) CreateSeries(ctx context.Context, tx *sqlx.Tx, series *domain.BookingSeries) (*domain.BookingSeries, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res domain.BookingSeries
+
+	query := `
+		INSERT INTO booking_series (id, resource_id, user_id, rrule, start_time, duration_minutes, timezone, notes, org_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING ` + seriesColumns
+
+	err = tx.QueryRowxContext(ctx, query,
+		series.ID,
+		series.ResourceID,
+		series.UserID,
+		series.RRule,
+		series.StartTime,
+		series.DurationMinutes,
+		series.Timezone,
+		series.Notes,
+		org,
+	).StructScan(&res)
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (r *bookingRepository) GetSeriesByID(ctx context.Context, id string) (*domain.BookingSeries, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res domain.BookingSeries
+
+	query := `SELECT ` + seriesColumns + ` FROM booking_series WHERE id = $1 AND org_id = $2`
+
+	err = r.DB.GetContext(ctx, &res, query, id, org)
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (r *bookingRepository) UpdateSeriesRule(ctx context.Context, tx *sqlx.Tx, id, rrule string) error {
+	org, err := orgID(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE booking_series SET rrule = $2, updated_at = now() WHERE id = $1 AND org_id = $3`
+
+	_, err = tx.ExecContext(ctx, query, id, rrule, org)
+	return err
+}
+
+func (r *bookingRepository) ListBySeries(ctx context.Context, seriesID string) ([]domain.Reservation, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []domain.Reservation{}
+
+	query := `
+		SELECT ` + reservationColumns + `
+		FROM reservations
+		WHERE series_id = $1 AND org_id = $2
+		ORDER BY occurrence_start
+	`
+
+	err = r.DB.SelectContext(ctx, &res, query, seriesID, org)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ListFollowingForUpdate occurrence aktif dengan jadwal asli >= from, row nya di-lock
+func (r *bookingRepository) ListFollowingForUpdate(ctx context.Context, tx *sqlx.Tx, seriesID string, from time.Time) ([]domain.Reservation, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []domain.Reservation{}
+
+	query := `
+		SELECT ` + reservationColumns + `
+		FROM reservations
+		WHERE series_id = $1
+			AND org_id = $4
+			AND occurrence_start >= $2
+			AND status <> $3
+		ORDER BY occurrence_start
+		FOR UPDATE
+	`
+
+	err = tx.SelectContext(ctx, &res, query, seriesID, from, domain.ReservationStatusCancelled, org)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// slotUnavailable dipanggil setelah exclusion constraint gagal. transaksi nya sudah aborted,
+// jadi cari reservasi yang bentrok pakai koneksi biasa (reservasi pemenang sudah commit)
+func (r *bookingRepository) slotUnavailable(ctx context.Context, org, resourceID string, start, end time.Time) error {
+	slotErr := &domain.SlotUnavailableError{
+		ResourceID: resourceID,
+		StartTime:  start,
+		EndTime:    end,
+	}
+
+	var conflict domain.Reservation
+	if err := r.DB.GetContext(ctx, &conflict, selectOverlapQuery+` LIMIT 1`, resourceID, releasedStatuses, start, end, []string{}, org); err == nil {
+		slotErr.StartTime = conflict.StartTime
+		slotErr.EndTime = conflict.EndTime
+	}
+
+	return slotErr
+}
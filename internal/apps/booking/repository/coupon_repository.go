@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"booking/internal/domain"
+	"booking/pkg/constant"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+)
+
+type couponRepository struct {
+	DB *sqlx.DB
+}
+
+func NewCouponRepository(db *sqlx.DB) domain.CouponRepository {
+	return &couponRepository{
+		DB: db,
+	}
+}
+
+const couponColumns = `
+	id, org_id, code, discount_type, percent_off, amount_off, currency, min_spend, max_redemptions, max_redemptions_per_user,
+	times_redeemed, valid_from, valid_until, is_active, created_by, created_at, updated_at
+`
+
+const selectCouponResources = `SELECT resource_id FROM coupon_resources WHERE coupon_id = $1 ORDER BY resource_id`
+
+func (r *couponRepository) Create(ctx context.Context, tx *sqlx.Tx, req *domain.CreateCouponDTO) (*domain.Coupon, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res domain.Coupon
+	createCouponQuery := `
+		INSERT INTO coupons (
+			id, code, discount_type, percent_off, amount_off, currency, min_spend,
+			max_redemptions, max_redemptions_per_user, valid_from, valid_until, created_by, org_id
+		)
+			VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8, $9, $10, $11, $12, $13)
+		RETURNING ` + couponColumns
+	err = tx.QueryRowxContext(ctx, createCouponQuery,
+		req.ID,
+		req.Code,
+		req.DiscountType,
+		req.PercentOff,
+		req.AmountOff,
+		req.Currency,
+		req.MinSpend,
+		req.MaxRedemptions,
+		req.MaxRedemptionsPerUser,
+		req.ValidFrom,
+		req.ValidUntil,
+		req.CreatedBy,
+		org,
+	).StructScan(&res)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == constant.PgErrUniqueViolation {
+			return nil, domain.ErrCouponCodeExists
+		}
+		return nil, err
+	}
+
+	// FK (resource_id, org_id) menolak resource milik org lain
+	createScopeQuery := `INSERT INTO coupon_resources (coupon_id, resource_id, org_id) VALUES ($1, $2, $3)`
+	for _, resourceID := range req.ResourceIDs {
+		if _, err := tx.ExecContext(ctx, createScopeQuery, res.ID, resourceID, org); err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == constant.PgErrForeignKeyViolation {
+				return nil, domain.ErrResourceNotFound
+			}
+			return nil, err
+		}
+	}
+	res.ResourceIDs = req.ResourceIDs
+
+	return &res, nil
+}
+
+func (r *couponRepository) List(ctx context.Context, limit, offset int) ([]domain.Coupon, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []domain.Coupon{}
+
+	query := `
+		SELECT ` + couponColumns + `
+		FROM coupons
+		WHERE org_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	err = r.DB.SelectContext(ctx, &res, query, org, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range res {
+		res[i].ResourceIDs = []string{}
+		if err := r.DB.SelectContext(ctx, &res[i].ResourceIDs, selectCouponResources, res[i].ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+func (r *couponRepository) GetByCode(ctx context.Context, code string) (*domain.Coupon, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res domain.Coupon
+
+	query := `SELECT ` + couponColumns + ` FROM coupons WHERE code = $1 AND org_id = $2`
+
+	err = r.DB.GetContext(ctx, &res, query, code, org)
+	if err != nil {
+		return nil, err
+	}
+
+	res.ResourceIDs = []string{}
+	if err := r.DB.SelectContext(ctx, &res.ResourceIDs, selectCouponResources, res.ID); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// GetByCodeForUpdate lock row kupon sampai transaksi selesai, redeem kupon yang sama jadi antri
+func (r *couponRepository) GetByCodeForUpdate(ctx context.Context, tx *sqlx.Tx, code string) (*domain.Coupon, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res domain.Coupon
+
+	query := `SELECT ` + couponColumns + ` FROM coupons WHERE code = $1 AND org_id = $2 FOR UPDATE`
+
+	err = tx.GetContext(ctx, &res, query, code, org)
+	if err != nil {
+		return nil, err
+	}
+
+	res.ResourceIDs = []string{}
+	if err := tx.SelectContext(ctx, &res.ResourceIDs, selectCouponResources, res.ID); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// CountUserRedemptions tx boleh nil untuk cek di luar transaksi (quote)
+func (r *couponRepository) CountUserRedemptions(ctx context.Context, tx *sqlx.Tx, couponID, userID string) (int, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+
+	query := `
+		SELECT count(*)
+		FROM coupon_redemptions cr
+		JOIN coupons c ON c.id = cr.coupon_id
+		WHERE cr.coupon_id = $1 AND cr.user_id = $2 AND c.org_id = $3
+	`
+
+	if tx != nil {
+		err = tx.GetContext(ctx, &count, query, couponID, userID, org)
+	} else {
+		err = r.DB.GetContext(ctx, &count, query, couponID, userID, org)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Redeem kupon dan reservasi harus milik org yang sama dengan org aktif
+func (r *couponRepository) Redeem(ctx context.Context, tx *sqlx.Tx, redemption *domain.CouponRedemption) error {
+	org, err := orgID(ctx)
+	if err != nil {
+		return err
+	}
+
+	updateCouponQuery := `UPDATE coupons SET times_redeemed = times_redeemed + 1, updated_at = now() WHERE id = $1 AND org_id = $2`
+	result, err := tx.ExecContext(ctx, updateCouponQuery, redemption.CouponID, org)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return domain.ErrCouponNotFound
+	}
+
+	createRedemptionQuery := `
+		INSERT INTO coupon_redemptions (id, coupon_id, user_id, reservation_id, amount)
+			SELECT $1, $2, $3, id, $5
+			FROM reservations WHERE id = $4 AND org_id = $6
+	`
+	result, err = tx.ExecContext(ctx, createRedemptionQuery,
+		redemption.ID,
+		redemption.CouponID,
+		redemption.UserID,
+		redemption.ReservationID,
+		redemption.Amount,
+		org,
+	)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return domain.ErrBookingNotFound
+	}
+	return nil
+}
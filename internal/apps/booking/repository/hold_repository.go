@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"booking/internal/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	holdKey          = "hold"
+	resourceHoldsKey = "resource_holds"
+)
+
+// hold:<id>                 → hash data hold, expired sendiri sesuai TTL
+// resource_holds:<resource> → zset id hold dengan score waktu expired, untuk cari hold aktif per resource
+type holdRepository struct {
+	rdb *redis.Client
+}
+
+func NewHoldRepository(rdb *redis.Client) domain.HoldRepository {
+	return &holdRepository{rdb: rdb}
+}
+
+func (r *holdRepository) Create(ctx context.Context, hold *domain.Hold, ttl time.Duration) error {
+	org, err := orgID(ctx)
+	if err != nil {
+		return err
+	}
+	hold.OrgID = org
+
+	key := generateHoldKey(hold.ID)
+	resourceKey := generateResourceHoldsKey(hold.ResourceID)
+
+	pipe := r.rdb.Pipeline()
+	pipe.HSet(ctx, key, hold.ToRedisMap())
+	pipe.Expire(ctx, key, ttl)
+	pipe.ZAdd(ctx, resourceKey, redis.Z{
+		Score:  float64(hold.ExpiresAt.Unix()),
+		Member: hold.ID,
+	})
+	pipe.Expire(ctx, resourceKey, ttl)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// GetByID hold milik org lain dianggap gak ada
+func (r *holdRepository) GetByID(ctx context.Context, id string) (*domain.Hold, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := r.rdb.HGetAll(ctx, generateHoldKey(id)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 || data["org_id"] != org {
+		return nil, domain.ErrHoldNotFound
+	}
+	return mapToHold(data)
+}
+
+func (r *holdRepository) Delete(ctx context.Context, hold *domain.Hold) error {
+	pipe := r.rdb.Pipeline()
+	pipe.Del(ctx, generateHoldKey(hold.ID))
+	pipe.ZRem(ctx, generateResourceHoldsKey(hold.ResourceID), hold.ID)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Consume atomic: dari dua confirm yang bersamaan cuma satu yang berhasil menghapus key hold nya
+func (r *holdRepository) Consume(ctx context.Context, hold *domain.Hold) error {
+	pipe := r.rdb.TxPipeline()
+	delCmd := pipe.Del(ctx, generateHoldKey(hold.ID))
+	pipe.ZRem(ctx, generateResourceHoldsKey(hold.ResourceID), hold.ID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+	if delCmd.Val() == 0 {
+		return domain.ErrHoldNotFound
+	}
+	return nil
+}
+
+func (r *holdRepository) ListActiveByResource(ctx context.Context, resourceID string) ([]domain.Hold, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceKey := generateResourceHoldsKey(resourceID)
+	now := time.Now().Unix()
+
+	// Hapus hold yang sudah expired dulu
+	_, _ = r.rdb.ZRemRangeByScore(ctx, resourceKey, "-inf", fmt.Sprintf("%d", now)).Result()
+
+	ids, err := r.rdb.ZRangeByScore(ctx, resourceKey, &redis.ZRangeBy{
+		Min: fmt.Sprintf("(%d", now),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return []domain.Hold{}, nil
+	}
+
+	pipe := r.rdb.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, 0, len(ids))
+	for _, id := range ids {
+		cmds = append(cmds, pipe.HGetAll(ctx, generateHoldKey(id)))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	holds := make([]domain.Hold, 0, len(ids))
+	for _, cmd := range cmds {
+		// key hold sudah expired tapi masih ada di zset
+		if len(cmd.Val()) == 0 {
+			continue
+		}
+		// hold lama (sebelum ada org) ikut org resource nya, jadi tetap dihitung
+		if holdOrg := cmd.Val()["org_id"]; holdOrg != "" && holdOrg != org {
+			continue
+		}
+		hold, err := mapToHold(cmd.Val())
+		if err != nil {
+			return nil, err
+		}
+		holds = append(holds, *hold)
+	}
+	return holds, nil
+}
+
+func mapToHold(data map[string]string) (*domain.Hold, error) {
+	startTime, err := strconv.ParseInt(data["start_time"], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	endTime, err := strconv.ParseInt(data["end_time"], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	expiresAt, err := strconv.ParseInt(data["expires_at"], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	// hold lama (sebelum ada party size) dianggap 1 kursi
+	partySize := 1
+	if v, ok := data["party_size"]; ok {
+		if partySize, err = strconv.Atoi(v); err != nil {
+			return nil, err
+		}
+	}
+
+	return &domain.Hold{
+		ID:         data["id"],
+		OrgID:      data["org_id"],
+		ResourceID: data["resource_id"],
+		UserID:     data["user_id"],
+		StartTime:  time.Unix(startTime, 0),
+		EndTime:    time.Unix(endTime, 0),
+		PartySize:  partySize,
+		ExpiresAt:  time.Unix(expiresAt, 0),
+	}, nil
+}
+
+func generateHoldKey(id string) string {
+	return fmt.Sprintf("%s:%s", holdKey, id)
+}
+
+func generateResourceHoldsKey(resourceID string) string {
+	return fmt.Sprintf("%s:%s", resourceHoldsKey, resourceID)
+}
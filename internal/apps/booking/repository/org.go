@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"booking/internal/domain"
+)
+
+// orgID org aktif dari ctx (middleware ResolveOrg atau domain.WithOrg). semua query di package ini di-scope
+// pakai org ini, query tanpa org ditolak supaya data org lain gak mungkin kebaca / keubah
+func orgID(ctx context.Context) (string, error) {
+	org, ok := domain.OrgFromContext(ctx)
+	if !ok || org.OrgID == "" {
+		return "", domain.ErrOrgRequired
+	}
+	return org.OrgID, nil
+}
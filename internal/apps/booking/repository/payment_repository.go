@@ -0,0 +1,287 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"booking/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type paymentRepository struct {
+	DB *sqlx.DB
+}
+
+func NewPaymentRepository(db *sqlx.DB) domain.PaymentRepository {
+	return &paymentRepository{
+		DB: db,
+	}
+}
+
+const refundColumns = `id, payment_id, reservation_id, amount, retained_amount, refund_bp, reason, status, created_at`
+
+const paymentColumns = `
+	id, org_id, reservation_id, user_id, provider, provider_ref, client_secret, amount, currency, status, refunded_amount,
+	created_at, updated_at
+`
+
+func (r *paymentRepository) Create(ctx context.Context, tx *sqlx.Tx, payment *domain.Payment) (*domain.Payment, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res domain.Payment
+
+	query := `
+		INSERT INTO payments (id, reservation_id, user_id, provider, provider_ref, client_secret, amount, currency, status, org_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING ` + paymentColumns
+
+	err = tx.QueryRowxContext(ctx, query,
+		payment.ID,
+		payment.ReservationID,
+		payment.UserID,
+		payment.Provider,
+		payment.ProviderRef,
+		payment.ClientSecret,
+		payment.Amount,
+		payment.Currency,
+		domain.PaymentStatusPending,
+		org,
+	).StructScan(&res)
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// GetByProviderRef gak di-scope org (sama seperti WaitlistRepository.ListLapsedOffers): webhook provider datang tanpa org,
+// org diambil dari payment ini (provider_ref unik per provider) lalu dipasang ke ctx untuk proses selanjutnya
+func (r *paymentRepository) GetByProviderRef(ctx context.Context, provider, providerRef string) (*domain.Payment, error) {
+	var res domain.Payment
+
+	query := `SELECT ` + paymentColumns + ` FROM payments WHERE provider = $1 AND provider_ref = $2`
+
+	err := r.DB.GetContext(ctx, &res, query, provider, providerRef)
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (r *paymentRepository) GetByIDForUpdate(ctx context.Context, tx *sqlx.Tx, id string) (*domain.Payment, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res domain.Payment
+
+	query := `SELECT ` + paymentColumns + ` FROM payments WHERE id = $1 AND org_id = $2 FOR UPDATE`
+
+	err = tx.GetContext(ctx, &res, query, id, org)
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (r *paymentRepository) GetLatestByReservation(ctx context.Context, reservationID string) (*domain.Payment, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res domain.Payment
+
+	query := `
+		SELECT ` + paymentColumns + `
+		FROM payments
+		WHERE reservation_id = $1 AND org_id = $2
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	err = r.DB.GetContext(ctx, &res, query, reservationID, org)
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// ListAuthorized payment yang sudah authorized lebih lama dari olderThan tapi belum di-capture, dari semua org.
+// dipakai job sweep (sama seperti GetByProviderRef), org tiap payment dipasang ke ctx oleh usecase
+func (r *paymentRepository) ListAuthorized(ctx context.Context, olderThan time.Duration, limit int) ([]domain.Payment, error) {
+	res := []domain.Payment{}
+
+	query := `
+		SELECT ` + paymentColumns + `
+		FROM payments
+		WHERE status = $1 AND updated_at <= now() - make_interval(secs => $2)
+		ORDER BY updated_at
+		LIMIT $3
+	`
+
+	err := r.DB.SelectContext(ctx, &res, query, domain.PaymentStatusAuthorized, olderThan.Seconds(), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ListWithoutIntent payment pending yang intent nya belum berhasil dibuat di provider, dari semua org (sama seperti ListAuthorized).
+// reservasi yang sudah gak pending (batal / expired) gak perlu intent lagi
+func (r *paymentRepository) ListWithoutIntent(ctx context.Context, olderThan time.Duration, limit int) ([]domain.Payment, error) {
+	res := []domain.Payment{}
+
+	query := `
+		SELECT ` + paymentColumns + `
+		FROM payments
+		WHERE provider_ref IS NULL
+			AND status = $1
+			AND created_at <= now() - make_interval(secs => $2)
+			AND EXISTS (SELECT 1 FROM reservations r WHERE r.id = payments.reservation_id AND r.status = $3)
+		ORDER BY created_at
+		LIMIT $4
+	`
+
+	err := r.DB.SelectContext(ctx, &res, query,
+		domain.PaymentStatusPending,
+		olderThan.Seconds(),
+		domain.ReservationStatusPending,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// SetIntent idempotent: intent yang sama boleh ditulis ulang, intent lain untuk payment yang sudah punya intent = sql.ErrNoRows
+func (r *paymentRepository) SetIntent(ctx context.Context, id, providerRef, clientSecret string) (*domain.Payment, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res domain.Payment
+
+	query := `
+		UPDATE payments
+		SET provider_ref = $2,
+			client_secret = $3,
+			updated_at = now()
+		WHERE id = $1 AND org_id = $4 AND (provider_ref IS NULL OR provider_ref = $2)
+		RETURNING ` + paymentColumns
+
+	err = r.DB.QueryRowxContext(ctx, query, id, providerRef, clientSecret, org).StructScan(&res)
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (r *paymentRepository) UpdateStatus(ctx context.Context, tx *sqlx.Tx, id, status string, refundedAmount int64) (*domain.Payment, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res domain.Payment
+
+	query := `
+		UPDATE payments
+		SET status = $2,
+			refunded_amount = $3,
+			updated_at = now()
+		WHERE id = $1 AND org_id = $4
+		RETURNING ` + paymentColumns
+
+	err = tx.QueryRowxContext(ctx, query, id, status, refundedAmount, org).StructScan(&res)
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// CreateRefund payment milik org lain = sql.ErrNoRows (gak ada baris yang di-insert)
+func (r *paymentRepository) CreateRefund(ctx context.Context, tx *sqlx.Tx, refund *domain.Refund) (*domain.Refund, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res domain.Refund
+
+	query := `
+		INSERT INTO refunds (id, payment_id, reservation_id, amount, retained_amount, refund_bp, reason, status)
+			SELECT $1, id, reservation_id, $4, $5, $6, $7, $8
+			FROM payments WHERE id = $2 AND reservation_id = $3 AND org_id = $9
+		RETURNING ` + refundColumns
+
+	err = tx.QueryRowxContext(ctx, query,
+		refund.ID,
+		refund.PaymentID,
+		refund.ReservationID,
+		refund.Amount,
+		refund.RetainedAmount,
+		refund.RefundBp,
+		refund.Reason,
+		refund.Status,
+		org,
+	).StructScan(&res)
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// ListPendingRefunds refund yang sudah pending lebih lama dari olderThan, dari semua org (sama seperti ListAuthorized).
+// org dan provider_ref diambil dari payment nya
+func (r *paymentRepository) ListPendingRefunds(ctx context.Context, olderThan time.Duration, limit int) ([]domain.Refund, error) {
+	res := []domain.Refund{}
+
+	query := `
+		SELECT r.id, r.payment_id, r.reservation_id, r.amount, r.retained_amount, r.refund_bp, r.reason, r.status, r.created_at,
+			p.org_id, p.provider_ref
+		FROM refunds r
+		JOIN payments p ON p.id = r.payment_id
+		WHERE r.status = $1 AND r.created_at <= now() - make_interval(secs => $2)
+		ORDER BY r.created_at
+		LIMIT $3
+	`
+
+	err := r.DB.SelectContext(ctx, &res, query, domain.RefundStatusPending, olderThan.Seconds(), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (r *paymentRepository) UpdateRefundStatus(ctx context.Context, id, status string) error {
+	org, err := orgID(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE refunds SET status = $2
+		WHERE id = $1
+			AND EXISTS (SELECT 1 FROM payments p WHERE p.id = payment_id AND p.org_id = $3)
+	`
+
+	_, err = r.DB.ExecContext(ctx, query, id, status, org)
+	return err
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+
+	"booking/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type ratePlanRepository struct {
+	DB *sqlx.DB
+}
+
+func NewRatePlanRepository(db *sqlx.DB) domain.RatePlanRepository {
+	return &ratePlanRepository{
+		DB: db,
+	}
+}
+
+const ratePlanColumns = `
+	id, resource_id, currency, hourly_rate, daily_rate, per_seat_price, minimum_charge,
+	weekday_multiplier_bp, weekend_multiplier_bp, created_at, updated_at
+`
+
+const ratePlanPeakColumns = `
+	id, rate_plan_id, day_of_week, to_char(start_time, 'HH24:MI') AS start_time, to_char(end_time, 'HH24:MI') AS end_time, multiplier_bp
+`
+
+// Upsert satu resource cuma punya satu rate plan, peak lama diganti semua.
+// resource milik org lain = sql.ErrNoRows (gak ada baris yang di-insert)
+func (r *ratePlanRepository) Upsert(ctx context.Context, tx *sqlx.Tx, req *domain.UpsertRatePlanDTO) (*domain.RatePlan, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res domain.RatePlan
+	upsertQuery := `
+		INSERT INTO rate_plans (
+			id, resource_id, currency, hourly_rate, daily_rate, per_seat_price, minimum_charge,
+			weekday_multiplier_bp, weekend_multiplier_bp
+		)
+			SELECT $1, id, $3, $4, $5, $6, $7, $8, $9
+			FROM resources WHERE id = $2 AND org_id = $10
+		ON CONFLICT (resource_id) DO UPDATE SET
+			currency = EXCLUDED.currency,
+			hourly_rate = EXCLUDED.hourly_rate,
+			daily_rate = EXCLUDED.daily_rate,
+			per_seat_price = EXCLUDED.per_seat_price,
+			minimum_charge = EXCLUDED.minimum_charge,
+			weekday_multiplier_bp = EXCLUDED.weekday_multiplier_bp,
+			weekend_multiplier_bp = EXCLUDED.weekend_multiplier_bp,
+			updated_at = now()
+		RETURNING ` + ratePlanColumns
+	err = tx.QueryRowxContext(ctx, upsertQuery,
+		req.ID,
+		req.ResourceID,
+		req.Currency,
+		req.HourlyRate,
+		req.DailyRate,
+		req.PerSeatPrice,
+		req.MinimumCharge,
+		req.WeekdayMultiplierBp,
+		req.WeekendMultiplierBp,
+		org,
+	).StructScan(&res)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM rate_plan_peaks WHERE rate_plan_id = $1`, res.ID); err != nil {
+		return nil, err
+	}
+
+	createPeakQuery := `
+		INSERT INTO rate_plan_peaks (id, rate_plan_id, day_of_week, start_time, end_time, multiplier_bp)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + ratePlanPeakColumns
+	res.Peaks = make([]domain.RatePlanPeak, 0, len(req.Peaks))
+	for _, peak := range req.Peaks {
+		var p domain.RatePlanPeak
+		err := tx.QueryRowxContext(ctx, createPeakQuery,
+			peak.ID,
+			res.ID,
+			peak.DayOfWeek,
+			peak.StartTime,
+			peak.EndTime,
+			peak.MultiplierBp,
+		).StructScan(&p)
+		if err != nil {
+			return nil, err
+		}
+		res.Peaks = append(res.Peaks, p)
+	}
+
+	return &res, nil
+}
+
+func (r *ratePlanRepository) GetByResource(ctx context.Context, resourceID string) (*domain.RatePlan, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res domain.RatePlan
+
+	query := `
+		SELECT ` + ratePlanColumns + `
+		FROM rate_plans
+		WHERE resource_id = $1
+			AND EXISTS (SELECT 1 FROM resources r WHERE r.id = resource_id AND r.org_id = $2)
+	`
+
+	err = r.DB.GetContext(ctx, &res, query, resourceID, org)
+	if err != nil {
+		return nil, err
+	}
+
+	res.Peaks = []domain.RatePlanPeak{}
+	peakQuery := `
+		SELECT ` + ratePlanPeakColumns + `
+		FROM rate_plan_peaks
+		WHERE rate_plan_id = $1
+		ORDER BY day_of_week NULLS FIRST, start_time
+	`
+	err = r.DB.SelectContext(ctx, &res.Peaks, peakQuery, res.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
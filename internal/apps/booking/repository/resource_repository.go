@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"context"
+
+	"booking/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type resourceRepository struct {
+	DB *sqlx.DB
+}
+
+func NewResourceRepository(db *sqlx.DB) domain.ResourceRepository {
+	return &resourceRepository{
+		DB: db,
+	}
+}
+
+const resourceColumns = `id, org_id, owner_id, name, description, timezone, buffer_minutes, slot_interval_minutes, capacity, waitlist_mode, cancellation_policy, is_active, created_at, updated_at`
+
+const selectResource = `SELECT ` + resourceColumns + ` FROM resources `
+
+func (r *resourceRepository) Create(ctx context.Context, tx *sqlx.Tx, req *domain.CreateResourceDTO) (*domain.Resource, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res domain.Resource
+	createResourceQuery := `
+		INSERT INTO resources (id, org_id, owner_id, name, description, timezone, buffer_minutes, slot_interval_minutes, capacity, waitlist_mode)
+			VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8, $9, $10)
+		RETURNING ` + resourceColumns
+	err = tx.QueryRowxContext(ctx, createResourceQuery,
+		req.ID,
+		org,
+		req.OwnerID,
+		req.Name,
+		req.Description,
+		req.Timezone,
+		req.BufferMinutes,
+		req.SlotIntervalMinutes,
+		req.Capacity,
+		req.WaitlistMode,
+	).StructScan(&res)
+	if err != nil {
+		return nil, err
+	}
+
+	createRuleQuery := `
+		INSERT INTO availability_rules (id, resource_id, day_of_week, start_time, end_time)
+			VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, resource_id, day_of_week, to_char(start_time, 'HH24:MI') AS start_time, to_char(end_time, 'HH24:MI') AS end_time
+	`
+	res.Rules = make([]domain.AvailabilityRule, 0, len(req.Rules))
+	for _, rule := range req.Rules {
+		var ar domain.AvailabilityRule
+		err := tx.QueryRowxContext(ctx, createRuleQuery,
+			rule.ID,
+			res.ID,
+			rule.DayOfWeek,
+			rule.StartTime,
+			rule.EndTime,
+		).StructScan(&ar)
+		if err != nil {
+			return nil, err
+		}
+		res.Rules = append(res.Rules, ar)
+	}
+
+	return &res, nil
+}
+
+func (r *resourceRepository) GetByID(ctx context.Context, id string) (*domain.Resource, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res domain.Resource
+
+	query := selectResource + `WHERE id = $1 AND org_id = $2`
+
+	err = r.DB.GetContext(ctx, &res, query, id, org)
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// GetByIDForUpdate lock row resource sampai transaksi selesai,
+// supaya booking ke resource yang sama diproses satu per satu
+func (r *resourceRepository) GetByIDForUpdate(ctx context.Context, tx *sqlx.Tx, id string) (*domain.Resource, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res domain.Resource
+
+	query := selectResource + `WHERE id = $1 AND org_id = $2 FOR UPDATE`
+
+	err = tx.GetContext(ctx, &res, query, id, org)
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (r *resourceRepository) List(ctx context.Context, limit, offset int) ([]domain.Resource, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []domain.Resource{}
+
+	query := selectResource + `WHERE org_id = $1 AND is_active = TRUE ORDER BY created_at DESC LIMIT $2 OFFSET $3`
+
+	err = r.DB.SelectContext(ctx, &res, query, org, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (r *resourceRepository) GetRules(ctx context.Context, resourceID string) ([]domain.AvailabilityRule, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []domain.AvailabilityRule{}
+
+	query := `
+		SELECT ar.id, ar.resource_id, ar.day_of_week, to_char(ar.start_time, 'HH24:MI') AS start_time, to_char(ar.end_time, 'HH24:MI') AS end_time
+		FROM availability_rules ar
+		JOIN resources r ON r.id = ar.resource_id
+		WHERE ar.resource_id = $1 AND r.org_id = $2
+		ORDER BY ar.day_of_week, ar.start_time
+	`
+
+	err = r.DB.SelectContext(ctx, &res, query, resourceID, org)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+const exceptionColumns = `
+	id, resource_id, to_char(date, 'YYYY-MM-DD') AS date,
+	to_char(start_time, 'HH24:MI') AS start_time, to_char(end_time, 'HH24:MI') AS end_time, reason
+`
+
+// CreateException resource milik org lain = sql.ErrNoRows (gak ada baris yang di-insert)
+func (r *resourceRepository) CreateException(ctx context.Context, req *domain.CreateAvailabilityExceptionDTO) (*domain.AvailabilityException, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res domain.AvailabilityException
+
+	query := `
+		INSERT INTO availability_exceptions (id, resource_id, date, start_time, end_time, reason)
+			SELECT $1, id, $3, NULLIF($4, '')::TIME, NULLIF($5, '')::TIME, NULLIF($6, '')
+			FROM resources WHERE id = $2 AND org_id = $7
+		RETURNING ` + exceptionColumns
+
+	err = r.DB.QueryRowxContext(ctx, query,
+		req.ID,
+		req.ResourceID,
+		req.Date,
+		req.StartTime,
+		req.EndTime,
+		req.Reason,
+		org,
+	).StructScan(&res)
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// GetExceptions exception di antara fromDate dan toDate (inclusive, format YYYY-MM-DD)
+func (r *resourceRepository) GetExceptions(ctx context.Context, resourceID string, fromDate, toDate string) ([]domain.AvailabilityException, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []domain.AvailabilityException{}
+
+	query := `
+		SELECT ` + exceptionColumns + `
+		FROM availability_exceptions
+		WHERE resource_id = $1 AND date BETWEEN $2 AND $3
+			AND EXISTS (SELECT 1 FROM resources r WHERE r.id = resource_id AND r.org_id = $4)
+		ORDER BY date, start_time NULLS FIRST
+	`
+
+	err = r.DB.SelectContext(ctx, &res, query, resourceID, fromDate, toDate, org)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// SetCancellationPolicy policy nil = hapus policy
+func (r *resourceRepository) SetCancellationPolicy(ctx context.Context, resourceID string, policy *domain.CancellationPolicy) (*domain.Resource, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res domain.Resource
+
+	query := `
+		UPDATE resources
+		SET cancellation_policy = $2,
+			updated_at = now()
+		WHERE id = $1 AND org_id = $3
+		RETURNING ` + resourceColumns
+
+	err = r.DB.QueryRowxContext(ctx, query, resourceID, policy, org).StructScan(&res)
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
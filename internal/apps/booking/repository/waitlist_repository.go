@@ -0,0 +1,311 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"booking/internal/domain"
+	"booking/pkg/constant"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+)
+
+type waitlistRepository struct {
+	DB *sqlx.DB
+}
+
+func NewWaitlistRepository(db *sqlx.DB) domain.WaitlistRepository {
+	return &waitlistRepository{
+		DB: db,
+	}
+}
+
+const waitlistColumns = `
+	id, org_id, resource_id, user_id, start_time, end_time, party_size, status, notes, offer_expires_at, reservation_id, promote_after,
+	created_at, updated_at
+`
+
+func (r *waitlistRepository) Create(ctx context.Context, tx *sqlx.Tx, req *domain.JoinWaitlistDTO) (*domain.WaitlistEntry, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res domain.WaitlistEntry
+	query := `
+		INSERT INTO waitlist_entries (id, resource_id, user_id, start_time, end_time, party_size, status, notes, org_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), $9)
+		RETURNING ` + waitlistColumns
+
+	err = tx.QueryRowxContext(ctx, query,
+		req.ID,
+		req.ResourceID,
+		req.UserID,
+		req.StartTime,
+		req.EndTime,
+		req.PartySize,
+		domain.WaitlistStatusWaiting,
+		req.Notes,
+		org,
+	).StructScan(&res)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == constant.PgErrUniqueViolation {
+			return nil, domain.ErrAlreadyWaitlisted
+		}
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (r *waitlistRepository) GetByID(ctx context.Context, id string) (*domain.WaitlistEntry, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res domain.WaitlistEntry
+
+	query := `SELECT ` + waitlistColumns + ` FROM waitlist_entries WHERE id = $1 AND org_id = $2`
+
+	err = r.DB.GetContext(ctx, &res, query, id, org)
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (r *waitlistRepository) GetByIDForUpdate(ctx context.Context, tx *sqlx.Tx, id string) (*domain.WaitlistEntry, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res domain.WaitlistEntry
+
+	query := `SELECT ` + waitlistColumns + ` FROM waitlist_entries WHERE id = $1 AND org_id = $2 FOR UPDATE`
+
+	err = tx.GetContext(ctx, &res, query, id, org)
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// ListByUser posisi dihitung dari antrian waiting di slot yang sama (resource + jam persis sama)
+func (r *waitlistRepository) ListByUser(ctx context.Context, userID string, limit, offset int) ([]domain.WaitlistEntry, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []domain.WaitlistEntry{}
+
+	query := `
+		SELECT ` + waitlistColumns + `,
+			CASE WHEN w.status = $2 THEN (
+				SELECT count(*)
+				FROM waitlist_entries q
+				WHERE q.resource_id = w.resource_id
+					AND q.start_time = w.start_time
+					AND q.end_time = w.end_time
+					AND q.status = $2
+					AND (q.created_at, q.id) <= (w.created_at, w.id)
+			) ELSE 0 END AS position
+		FROM waitlist_entries w
+		WHERE w.user_id = $1 AND w.org_id = $5
+		ORDER BY w.created_at DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	err = r.DB.SelectContext(ctx, &res, query, userID, domain.WaitlistStatusWaiting, limit, offset, org)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ListWaitingForUpdate antrian waiting yang overlap dengan [from, to), urut dari yang paling dulu join
+func (r *waitlistRepository) ListWaitingForUpdate(ctx context.Context, tx *sqlx.Tx, resourceID string, from, to time.Time) ([]domain.WaitlistEntry, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []domain.WaitlistEntry{}
+
+	query := `
+		SELECT ` + waitlistColumns + `
+		FROM waitlist_entries
+		WHERE resource_id = $1
+			AND org_id = $5
+			AND status = $2
+			AND start_time < $4
+			AND end_time > $3
+		ORDER BY created_at, id
+		FOR UPDATE
+	`
+
+	err = tx.SelectContext(ctx, &res, query, resourceID, domain.WaitlistStatusWaiting, from, to, org)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ListOverlappingOffers tawaran waitlist yang belum expired dan overlap dengan [start, end), kecuali excludeID
+func (r *waitlistRepository) ListOverlappingOffers(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	resourceID string,
+	start, end time.Time,
+	excludeID string,
+) ([]domain.WaitlistEntry, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []domain.WaitlistEntry{}
+
+	query := `
+		SELECT ` + waitlistColumns + `
+		FROM waitlist_entries
+		WHERE resource_id = $1
+			AND org_id = $6
+			AND status = $2
+			AND offer_expires_at > now()
+			AND start_time < $4
+			AND end_time > $3
+			AND id::text <> $5
+		ORDER BY start_time
+	`
+
+	err = tx.SelectContext(ctx, &res, query, resourceID, domain.WaitlistStatusOffered, start, end, excludeID, org)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ListActiveOffers tawaran waitlist yang belum expired dan overlap dengan [from, to)
+func (r *waitlistRepository) ListActiveOffers(ctx context.Context, resourceID string, from, to time.Time) ([]domain.WaitlistEntry, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []domain.WaitlistEntry{}
+
+	query := `
+		SELECT ` + waitlistColumns + `
+		FROM waitlist_entries
+		WHERE resource_id = $1
+			AND org_id = $5
+			AND status = $2
+			AND offer_expires_at > now()
+			AND start_time < $4
+			AND end_time > $3
+		ORDER BY start_time
+	`
+
+	err = r.DB.SelectContext(ctx, &res, query, resourceID, domain.WaitlistStatusOffered, from, to, org)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ListLapsedOffers tawaran yang lewat offer_expires_at tapi masih berstatus offered, dari semua org.
+// dipakai job sweep yang jalan tanpa request (gak ada org di ctx), org tiap entry dipasang ke ctx oleh usecase
+func (r *waitlistRepository) ListLapsedOffers(ctx context.Context, limit int) ([]domain.WaitlistEntry, error) {
+	res := []domain.WaitlistEntry{}
+
+	query := `
+		SELECT ` + waitlistColumns + `
+		FROM waitlist_entries
+		WHERE status = $1 AND offer_expires_at <= now()
+		ORDER BY offer_expires_at
+		LIMIT $2
+	`
+
+	err := r.DB.SelectContext(ctx, &res, query, domain.WaitlistStatusOffered, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ListPendingPromotions tawaran expired yang antrian berikutnya belum berhasil dipromosikan, dari semua org (sama seperti ListLapsedOffers)
+func (r *waitlistRepository) ListPendingPromotions(ctx context.Context, limit int) ([]domain.WaitlistEntry, error) {
+	res := []domain.WaitlistEntry{}
+
+	query := `
+		SELECT ` + waitlistColumns + `
+		FROM waitlist_entries
+		WHERE promote_after <= now()
+		ORDER BY promote_after
+		LIMIT $1
+	`
+
+	err := r.DB.SelectContext(ctx, &res, query, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// SetPromoteAfter nil = promosi sudah selesai
+func (r *waitlistRepository) SetPromoteAfter(ctx context.Context, tx *sqlx.Tx, id string, promoteAfter *time.Time) error {
+	org, err := orgID(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE waitlist_entries SET promote_after = $2 WHERE id = $1 AND org_id = $3`
+
+	_, err = tx.ExecContext(ctx, query, id, promoteAfter, org)
+	return err
+}
+
+func (r *waitlistRepository) UpdateStatus(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	id, status string,
+	offerExpiresAt *time.Time,
+	reservationID *string,
+) (*domain.WaitlistEntry, error) {
+	org, err := orgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res domain.WaitlistEntry
+
+	query := `
+		UPDATE waitlist_entries
+		SET status = $2,
+			offer_expires_at = $3,
+			reservation_id = COALESCE($4, reservation_id),
+			updated_at = now()
+		WHERE id = $1 AND org_id = $5
+		RETURNING ` + waitlistColumns
+
+	err = tx.QueryRowxContext(ctx, query, id, status, offerExpiresAt, reservationID, org).StructScan(&res)
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
@@ -0,0 +1,275 @@
+package usecase
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"booking/internal/domain"
+)
+
+const (
+	defaultSlotInterval  = 15 * time.Minute
+	maxAvailabilityRange = 31 // hari
+)
+
+// Semua fungsi di file ini pure (gak akses db / redis), jadi gampang dipakai ulang
+// oleh availability search dan validasi saat create booking.
+
+type timeRange struct {
+	start time.Time
+	end   time.Time
+}
+
+func (r timeRange) overlaps(o timeRange) bool {
+	return r.start.Before(o.end) && o.start.Before(r.end)
+}
+
+func (r timeRange) contains(o timeRange) bool {
+	return !o.start.Before(r.start) && !o.end.After(r.end)
+}
+
+// occupancy - window yang terpakai reservasi / hold / tawaran waitlist beserta jumlah kursinya
+type occupancy struct {
+	timeRange
+	seats int
+}
+
+// openWindows jam buka di satu tanggal (timezone resource) setelah dikurangi exception
+func openWindows(rules []domain.AvailabilityRule, exceptions []domain.AvailabilityException, date time.Time, loc *time.Location) []timeRange {
+	date = date.In(loc)
+	dateStr := date.Format(time.DateOnly)
+
+	var windows []timeRange
+	for _, rule := range rules {
+		if rule.DayOfWeek != int(date.Weekday()) {
+			continue
+		}
+		windows = append(windows, timeRange{
+			start: clockOn(date, rule.StartTime, loc),
+			end:   clockOn(date, rule.EndTime, loc),
+		})
+	}
+	windows = mergeRanges(windows)
+
+	for _, ex := range exceptions {
+		if ex.Date != dateStr {
+			continue
+		}
+		// tutup seharian
+		if ex.StartTime == nil || ex.EndTime == nil {
+			return nil
+		}
+		windows = subtractRange(windows, timeRange{
+			start: clockOn(date, *ex.StartTime, loc),
+			end:   clockOn(date, *ex.EndTime, loc),
+		})
+	}
+	return windows
+}
+
+// withinOpeningHours cek apakah [start, end) masuk penuh ke salah satu jam buka
+func withinOpeningHours(
+	rules []domain.AvailabilityRule,
+	exceptions []domain.AvailabilityException,
+	loc *time.Location,
+	start, end time.Time,
+) bool {
+	booking := timeRange{start: start, end: end}
+	for _, w := range openWindows(rules, exceptions, start, loc) {
+		if w.contains(booking) {
+			return true
+		}
+	}
+	return false
+}
+
+// computeSlots generate slot dengan panjang duration tiap interval resource, dari tanggal from sampai to (inclusive).
+// slot yang sudah lewat dibuang. resource exclusive: slot yang bentrok dengan busy (+ buffer) dibuang,
+// resource shared: slot dibuang kalau sisa kursi nya kurang dari partySize
+func computeSlots(
+	resource *domain.Resource,
+	rules []domain.AvailabilityRule,
+	exceptions []domain.AvailabilityException,
+	busy []occupancy,
+	loc *time.Location,
+	from, to time.Time,
+	duration time.Duration,
+	partySize int,
+	now time.Time,
+) []domain.Slot {
+	buffer := time.Duration(resource.BufferMinutes) * time.Minute
+	interval := time.Duration(resource.SlotIntervalMinutes) * time.Minute
+	if interval <= 0 {
+		interval = defaultSlotInterval
+	}
+
+	slots := []domain.Slot{}
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		for _, w := range openWindows(rules, exceptions, date, loc) {
+			for start := w.start; !start.Add(duration).After(w.end); start = start.Add(interval) {
+				slot := timeRange{start: start, end: start.Add(duration)}
+				if slot.start.Before(now) {
+					continue
+				}
+				if !resource.IsShared() {
+					if isBusy(slot, busy, buffer) {
+						continue
+					}
+					slots = append(slots, domain.Slot{StartTime: slot.start, EndTime: slot.end})
+					continue
+				}
+
+				remaining := resource.Capacity - peakSeats(slot, busy)
+				if remaining < partySize {
+					continue
+				}
+				slots = append(slots, domain.Slot{StartTime: slot.start, EndTime: slot.end, RemainingSeats: &remaining})
+			}
+		}
+	}
+	return slots
+}
+
+func isBusy(slot timeRange, busy []occupancy, buffer time.Duration) bool {
+	return firstBusy(slot, busy, buffer) != nil
+}
+
+// firstBusy occupancy pertama yang bentrok dengan slot (+ buffer), nil kalau gak ada
+func firstBusy(slot timeRange, busy []occupancy, buffer time.Duration) *occupancy {
+	for i, b := range busy {
+		if slot.overlaps(timeRange{start: b.start.Add(-buffer), end: b.end.Add(buffer)}) {
+			return &busy[i]
+		}
+	}
+	return nil
+}
+
+// peakSeats jumlah kursi terpakai paling banyak di satu titik waktu dalam slot.
+// dihitung pakai sweep line, jadi booking yang jam nya beda-beda tetap dihitung benar
+func peakSeats(slot timeRange, busy []occupancy) int {
+	type event struct {
+		at    time.Time
+		delta int
+	}
+	events := []event{}
+	for _, b := range busy {
+		if !slot.overlaps(b.timeRange) {
+			continue
+		}
+		events = append(events, event{at: b.start, delta: b.seats}, event{at: b.end, delta: -b.seats})
+	}
+	// di waktu yang sama, yang selesai dihitung dulu ([start, end) gak overlap dengan yang mulai di end)
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].at.Equal(events[j].at) {
+			return events[i].delta < events[j].delta
+		}
+		return events[i].at.Before(events[j].at)
+	})
+
+	peak, current := 0, 0
+	for _, e := range events {
+		current += e.delta
+		if current > peak {
+			peak = current
+		}
+	}
+	return peak
+}
+
+// toOccupancy gabungin reservasi, hold dan tawaran waitlist yang menahan slot.
+// reservasi pending yang lewat batas bayar dilewati walau status nya belum diubah jadi expired
+func toOccupancy(reservations []domain.Reservation, holds []domain.Hold, offers []domain.WaitlistEntry, now time.Time) []occupancy {
+	res := make([]occupancy, 0, len(reservations)+len(holds)+len(offers))
+	for _, r := range reservations {
+		if r.PaymentOverdue(now) {
+			continue
+		}
+		res = append(res, occupancy{timeRange: timeRange{start: r.StartTime, end: r.EndTime}, seats: r.PartySize})
+	}
+	for _, h := range holds {
+		res = append(res, occupancy{timeRange: timeRange{start: h.StartTime, end: h.EndTime}, seats: h.PartySize})
+	}
+	for _, o := range offers {
+		res = append(res, occupancy{timeRange: timeRange{start: o.StartTime, end: o.EndTime}, seats: o.PartySize})
+	}
+	return res
+}
+
+// occupancyExpiry waktu paling awal hold, tawaran waitlist atau batas bayar reservasi pending lepas dengan sendiri nya
+// (tanpa invalidate cache). zero kalau gak ada
+func occupancyExpiry(reservations []domain.Reservation, holds []domain.Hold, offers []domain.WaitlistEntry, now time.Time) time.Time {
+	var earliest time.Time
+	consider := func(t time.Time) {
+		if t.After(now) && (earliest.IsZero() || t.Before(earliest)) {
+			earliest = t
+		}
+	}
+	for _, r := range reservations {
+		if r.Status == domain.ReservationStatusPending && r.PaymentDueAt != nil {
+			consider(*r.PaymentDueAt)
+		}
+	}
+	for _, h := range holds {
+		consider(h.ExpiresAt)
+	}
+	for _, o := range offers {
+		if o.OfferExpiresAt != nil {
+			consider(*o.OfferExpiresAt)
+		}
+	}
+	return earliest
+}
+
+func mergeRanges(ranges []timeRange) []timeRange {
+	if len(ranges) < 2 {
+		return ranges
+	}
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].start.Before(ranges[j].start) })
+
+	merged := []timeRange{ranges[0]}
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if !r.start.After(last.end) {
+			if r.end.After(last.end) {
+				last.end = r.end
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+func subtractRange(windows []timeRange, cut timeRange) []timeRange {
+	var res []timeRange
+	for _, w := range windows {
+		if !w.overlaps(cut) {
+			res = append(res, w)
+			continue
+		}
+		if w.start.Before(cut.start) {
+			res = append(res, timeRange{start: w.start, end: cut.start})
+		}
+		if cut.end.Before(w.end) {
+			res = append(res, timeRange{start: cut.end, end: w.end})
+		}
+	}
+	return res
+}
+
+// clockOn gabungin tanggal + jam "HH:MM" di timezone resource
+func clockOn(date time.Time, clock string, loc *time.Location) time.Time {
+	y, m, d := date.Date()
+	minutes := clockToMinutes(clock)
+	return time.Date(y, m, d, minutes/60, minutes%60, 0, 0, loc)
+}
+
+// clockToMinutes "09:30" → 570
+func clockToMinutes(clock string) int {
+	h, m, _ := strings.Cut(clock, ":")
+	hour, _ := strconv.Atoi(h)
+	minute, _ := strconv.Atoi(m)
+	return hour*60 + minute
+}
@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"booking/internal/domain"
+)
+
+// senin, semua test availability pakai UTC supaya jam nya gampang dibaca
+var testMonday = time.Date(2030, 1, 7, 0, 0, 0, 0, time.UTC)
+
+func at(clock string) time.Time {
+	return clockOn(testMonday, clock, time.UTC)
+}
+
+func busyAt(start, end string, seats int) occupancy {
+	return occupancy{timeRange: timeRange{start: at(start), end: at(end)}, seats: seats}
+}
+
+func TestPeakSeats(t *testing.T) {
+	slot := timeRange{start: at("10:00"), end: at("11:00")}
+
+	tests := []struct {
+		name string
+		busy []occupancy
+		want int
+	}{
+		{name: "empty", busy: nil, want: 0},
+		{name: "outside slot", busy: []occupancy{busyAt("09:00", "10:00", 3), busyAt("11:00", "12:00", 3)}, want: 0},
+		{name: "back to back is not summed", busy: []occupancy{busyAt("10:00", "10:30", 2), busyAt("10:30", "11:00", 3)}, want: 3},
+		{name: "overlapping parties are summed", busy: []occupancy{busyAt("10:00", "11:00", 2), busyAt("10:30", "11:30", 3)}, want: 5},
+		{name: "staggered without overlap", busy: []occupancy{busyAt("09:00", "10:15", 2), busyAt("10:45", "12:00", 2)}, want: 2},
+		{name: "three way overlap", busy: []occupancy{busyAt("09:30", "10:45", 1), busyAt("10:15", "10:30", 2), busyAt("10:20", "11:00", 1)}, want: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := peakSeats(slot, tt.busy); got != tt.want {
+				t.Fatalf("peakSeats() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeSlots_Shared(t *testing.T) {
+	const capacity = 4
+	resource := &domain.Resource{Capacity: capacity, SlotIntervalMinutes: 60}
+	rules := []domain.AvailabilityRule{{DayOfWeek: int(time.Monday), StartTime: "10:00", EndTime: "12:00"}}
+
+	type slot struct {
+		start     string
+		remaining int
+	}
+	tests := []struct {
+		name      string
+		busy      []occupancy
+		partySize int
+		want      []slot
+	}{
+		{
+			name:      "no bookings",
+			partySize: capacity,
+			want:      []slot{{"10:00", 4}, {"11:00", 4}},
+		},
+		{
+			name:      "capacity-1 taken, one seat left",
+			busy:      []occupancy{busyAt("10:00", "11:00", 1), busyAt("10:30", "11:00", 2)},
+			partySize: 1,
+			want:      []slot{{"10:00", 1}, {"11:00", 4}},
+		},
+		{
+			name:      "capacity-1 taken, party of two does not fit",
+			busy:      []occupancy{busyAt("10:00", "11:00", 1), busyAt("10:30", "11:00", 2)},
+			partySize: 2,
+			want:      []slot{{"11:00", 4}},
+		},
+		{
+			name:      "at capacity",
+			busy:      []occupancy{busyAt("10:00", "11:00", 1), busyAt("10:30", "11:00", 3)},
+			partySize: 1,
+			want:      []slot{{"11:00", 4}},
+		},
+		{
+			name:      "back to back parties are not summed",
+			busy:      []occupancy{busyAt("10:00", "10:30", 3), busyAt("10:30", "11:30", 3)},
+			partySize: 1,
+			want:      []slot{{"10:00", 1}, {"11:00", 1}},
+		},
+		{
+			name:      "booking across both slots",
+			busy:      []occupancy{busyAt("10:30", "11:30", capacity)},
+			partySize: 1,
+			want:      []slot{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := computeSlots(resource, rules, nil, tt.busy, time.UTC, testMonday, testMonday, time.Hour, tt.partySize, testMonday)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d slots, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i, w := range tt.want {
+				if !got[i].StartTime.Equal(at(w.start)) {
+					t.Errorf("slot %d starts at %s, want %s", i, got[i].StartTime.Format("15:04"), w.start)
+				}
+				if got[i].RemainingSeats == nil || *got[i].RemainingSeats != w.remaining {
+					t.Errorf("slot %d remaining seats = %v, want %d", i, got[i].RemainingSeats, w.remaining)
+				}
+			}
+		})
+	}
+}
+
+func TestComputeSlots_ExclusiveBuffer(t *testing.T) {
+	resource := &domain.Resource{Capacity: 1, BufferMinutes: 15, SlotIntervalMinutes: 30}
+	rules := []domain.AvailabilityRule{{DayOfWeek: int(time.Monday), StartTime: "10:00", EndTime: "13:00"}}
+	busy := []occupancy{busyAt("11:00", "11:30", 1)}
+
+	// 10:30 - 11:30 bentrok (booking + buffer 15 menit), 10:00 sudah lewat
+	got := computeSlots(resource, rules, nil, busy, time.UTC, testMonday, testMonday, 30*time.Minute, 1, at("10:05"))
+
+	want := []string{"12:00", "12:30"}
+	if len(got) != len(want) {
+		t.Fatalf("got %d slots, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		if !got[i].StartTime.Equal(at(w)) {
+			t.Errorf("slot %d starts at %s, want %s", i, got[i].StartTime.Format("15:04"), w)
+		}
+		if got[i].RemainingSeats != nil {
+			t.Errorf("slot %d of exclusive resource has remaining seats", i)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"booking/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// UpdateStatus check-in / selesai / no-show, cuma bisa dilakukan pemilik resource
+func (u *bookingUsecase) UpdateStatus(ctx context.Context, userID, id string, req *domain.UpdateBookingStatusDTO) (*domain.Reservation, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, domain.ErrBookingNotFound
+	}
+
+	var res *domain.Reservation
+	err := u.uow.Do(ctx, func(tx *sqlx.Tx) error {
+		reservation, err := u.bookingRepository.GetByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return domain.ErrBookingNotFound
+			}
+			return err
+		}
+		resource, err := u.resourceRepository.GetByIDForUpdate(ctx, tx, reservation.ResourceID)
+		if err != nil {
+			return err
+		}
+		if !resource.ManagedBy(ctx, userID) {
+			if reservation.UserID == userID {
+				return domain.ErrForbiden
+			}
+			return domain.ErrBookingNotFound
+		}
+
+		// no-show baru bisa ditandai setelah jam mulai
+		if req.Status == domain.ReservationStatusNoShow && time.Now().Before(reservation.StartTime) {
+			return domain.ErrBookingNotModifiable
+		}
+
+		res, err = transitionReservation(ctx, tx, u.bookingRepository, u.bookingEventRepository, id, req.Status, req.Reason, domain.UserActor(userID))
+		return err
+	})
+	if err != nil {
+		return nil, u.mapError(err, "error updating booking status")
+	}
+
+	return res, nil
+}
+
+// ListEvents riwayat status reservasi, aturan aksesnya sama dengan GetByID
+func (u *bookingUsecase) ListEvents(ctx context.Context, userID, id string) ([]domain.BookingEvent, error) {
+	if _, err := u.GetByID(ctx, userID, id); err != nil {
+		return nil, err
+	}
+
+	res, err := u.bookingEventRepository.ListByReservation(ctx, id)
+	if err != nil {
+		u.log.Error(err, "failed to list booking events")
+		return nil, domain.ErrInternalServerError
+	}
+
+	return res, nil
+}
+
+// expireOverdue tandai expired reservasi pending yang lewat batas bayar, dipanggil checkSlot
+// dengan row resource sudah di-lock supaya slot nya bisa langsung dipakai booking baru.
+// aktor nya sistem, bukan user yang kebetulan sedang booking
+func (u *bookingUsecase) expireOverdue(ctx context.Context, tx *sqlx.Tx, reservations []domain.Reservation, now time.Time) ([]domain.Reservation, error) {
+	active := make([]domain.Reservation, 0, len(reservations))
+	for _, r := range reservations {
+		if !r.PaymentOverdue(now) {
+			active = append(active, r)
+			continue
+		}
+		res, err := transitionReservation(ctx, tx, u.bookingRepository, u.bookingEventRepository, r.ID, domain.ReservationStatusExpired, "payment not received in time", domain.SystemActor)
+		if err != nil {
+			// webhook pembayaran menang duluan, reservasi nya tetap memakai slot
+			if errors.Is(err, domain.ErrInvalidTransition) {
+				active = append(active, r)
+				continue
+			}
+			return nil, err
+		}
+		u.log.Infof("reservation %s expired, payment due at %s", res.ID, r.PaymentDueAt.Format(time.RFC3339))
+	}
+	return active, nil
+}
+
+// transitionReservation satu-satunya jalan untuk mengubah status reservasi. status dibaca ulang
+// dengan lock supaya validasi gak pakai data basi, lalu perubahan nya dicatat di booking_events
+func transitionReservation(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	bookingRepository domain.BookingRepository,
+	eventRepository domain.BookingEventRepository,
+	id, to, reason string,
+	actor domain.BookingActor,
+) (*domain.Reservation, error) {
+	current, err := bookingRepository.GetByIDForUpdate(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := domain.ValidateTransition(current.Status, to); err != nil {
+		return nil, err
+	}
+
+	res, err := bookingRepository.UpdateStatus(ctx, tx, id, to)
+	if err != nil {
+		return nil, err
+	}
+	if err := recordBookingEvent(ctx, tx, eventRepository, id, current.Status, to, reason, actor); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func recordBookingEvent(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	eventRepository domain.BookingEventRepository,
+	reservationID, from, to, reason string,
+	actor domain.BookingActor,
+) error {
+	eventID, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+
+	event := &domain.BookingEvent{
+		ID:            eventID.String(),
+		ReservationID: reservationID,
+		ActorID:       actor.ID,
+		ActorType:     actor.Type,
+		ToStatus:      to,
+	}
+	if from != "" {
+		event.FromStatus = &from
+	}
+	if reason != "" {
+		event.Reason = &reason
+	}
+
+	return eventRepository.Create(ctx, tx, event)
+}
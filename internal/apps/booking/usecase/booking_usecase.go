@@ -0,0 +1,459 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"booking/internal/domain"
+	"booking/pkg/config"
+	"booking/pkg/logger"
+	uow "booking/pkg/unitOfWork"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+type bookingUsecase struct {
+	bookingRepository      domain.BookingRepository
+	resourceRepository     domain.ResourceRepository
+	holdRepository         domain.HoldRepository
+	waitlistRepository     domain.WaitlistRepository
+	ratePlanRepository     domain.RatePlanRepository
+	couponRepository       domain.CouponRepository
+	bookingEventRepository domain.BookingEventRepository
+	paymentRepository      domain.PaymentRepository
+	paymentProvider        domain.PaymentProvider
+	availabilityCache      domain.AvailabilityCache
+	uow                    uow.UnitOfWork
+	config                 *config.Config
+	log                    logger.Logger
+}
+
+func NewBookingUsecase(
+	bookingRepository domain.BookingRepository,
+	resourceRepository domain.ResourceRepository,
+	holdRepository domain.HoldRepository,
+	waitlistRepository domain.WaitlistRepository,
+	ratePlanRepository domain.RatePlanRepository,
+	couponRepository domain.CouponRepository,
+	bookingEventRepository domain.BookingEventRepository,
+	paymentRepository domain.PaymentRepository,
+	paymentProvider domain.PaymentProvider,
+	availabilityCache domain.AvailabilityCache,
+	uow uow.UnitOfWork,
+	config *config.Config,
+	log logger.Logger,
+) domain.BookingUsecase {
+	return &bookingUsecase{
+		bookingRepository:      bookingRepository,
+		resourceRepository:     resourceRepository,
+		holdRepository:         holdRepository,
+		waitlistRepository:     waitlistRepository,
+		ratePlanRepository:     ratePlanRepository,
+		couponRepository:       couponRepository,
+		bookingEventRepository: bookingEventRepository,
+		paymentRepository:      paymentRepository,
+		paymentProvider:        paymentProvider,
+		availabilityCache:      availabilityCache,
+		uow:                    uow,
+		config:                 config,
+		log:                    log,
+	}
+}
+
+func (u *bookingUsecase) Create(ctx context.Context, req *domain.CreateBookingDTO) (*domain.Reservation, error) {
+	return u.create(ctx, req, nil)
+}
+
+// create dipakai Create dan ConfirmHold. hold = hold milik user sendiri yang diabaikan saat cek bentrok,
+// dipakai habis di akhir transaksi
+func (u *bookingUsecase) create(ctx context.Context, req *domain.CreateBookingDTO, hold *domain.Hold) (*domain.Reservation, error) {
+	if err := validateBookingTime(req.StartTime, req.EndTime); err != nil {
+		return nil, err
+	}
+	if req.PartySize == 0 {
+		req.PartySize = 1
+	}
+
+	bookingID, err := uuid.NewV7()
+	if err != nil {
+		u.log.Error(err, "failed to generate uuidv7 for booking")
+		return nil, domain.ErrInternalServerError
+	}
+	req.ID = bookingID.String()
+	ownHoldID := ""
+	if hold != nil {
+		ownHoldID = hold.ID
+	}
+
+	var res *domain.Reservation
+	err = u.uow.Do(ctx, func(tx *sqlx.Tx) error {
+		resource, err := u.lockResource(ctx, tx, req.ResourceID)
+		if err != nil {
+			return err
+		}
+		if err := u.checkSlot(ctx, tx, resource, req.StartTime, req.EndTime, req.PartySize, ownHoldID, nil); err != nil {
+			return err
+		}
+		req.Price, err = u.quoteFor(ctx, resource, req.StartTime, req.EndTime, req.PartySize)
+		if err != nil {
+			return err
+		}
+		var coupon *domain.Coupon
+		if req.CouponCode != "" {
+			coupon, err = u.applyCouponCode(ctx, tx, req.UserID, resource.ID, req.CouponCode, req.Price)
+			if err != nil {
+				return err
+			}
+		}
+
+		reason := ""
+		if ownHoldID != "" {
+			reason = "hold " + ownHoldID + " confirmed"
+		}
+		res, err = u.insertReservation(ctx, tx, req, domain.UserActor(req.UserID), reason)
+		if err != nil {
+			return err
+		}
+		if coupon != nil {
+			if err := u.redeemCoupon(ctx, tx, coupon, res); err != nil {
+				return err
+			}
+		}
+		// hold dihapus masih di bawah lock resource: confirm kedua untuk hold yang sama antri di lock,
+		// lalu gagal di sini dan reservasi nya ikut di-rollback. commit yang gagal setelah ini = hold hilang, user booking ulang
+		if hold != nil {
+			return u.holdRepository.Consume(ctx, hold)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, u.mapError(err, "error creating booking")
+	}
+	u.invalidateAvailability(ctx, res.ResourceID)
+	u.createPaymentIntents(ctx, res)
+
+	return res, nil
+}
+
+func (u *bookingUsecase) GetByID(ctx context.Context, userID, id string) (*domain.Reservation, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, domain.ErrBookingNotFound
+	}
+
+	res, err := u.bookingRepository.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrBookingNotFound
+		}
+		u.log.Error(err, "failed to get booking")
+		return nil, domain.ErrInternalServerError
+	}
+
+	if res.UserID == userID {
+		return res, nil
+	}
+
+	// pemilik resource juga boleh lihat booking di resource nya
+	resource, err := u.resourceRepository.GetByID(ctx, res.ResourceID)
+	if err != nil {
+		u.log.Error(err, "failed to get resource of booking")
+		return nil, domain.ErrInternalServerError
+	}
+	if !resource.ManagedBy(ctx, userID) {
+		return nil, domain.ErrBookingNotFound
+	}
+
+	return res, nil
+}
+
+func (u *bookingUsecase) ListByUser(ctx context.Context, userID string, q *domain.PaginationQuery) ([]domain.Reservation, error) {
+	limit, offset := normalizePagination(q.Limit, q.Offset)
+
+	res, err := u.bookingRepository.ListByUser(ctx, userID, limit, offset)
+	if err != nil {
+		u.log.Error(err, "failed to list user bookings")
+		return nil, domain.ErrInternalServerError
+	}
+
+	return res, nil
+}
+
+func (u *bookingUsecase) Cancel(ctx context.Context, userID, id string, req *domain.CancelBookingDTO) (*domain.Reservation, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, domain.ErrBookingNotFound
+	}
+
+	// pembatalan & promosi waitlist di-commit bersamaan, slot yang kosong gak sempat direbut booking lain
+	var (
+		res     *domain.Reservation
+		refunds []*domain.Refund
+	)
+	err := u.uow.Do(ctx, func(tx *sqlx.Tx) error {
+		reservation, resource, err := u.lockOwnReservation(ctx, tx, userID, id)
+		if err != nil {
+			return err
+		}
+		if !isModifiable(reservation) {
+			return domain.ErrBookingNotCancellable
+		}
+
+		res, err = transitionReservation(ctx, tx, u.bookingRepository, u.bookingEventRepository, id, domain.ReservationStatusCancelled, req.Reason, domain.UserActor(userID))
+		if err != nil {
+			return err
+		}
+		cancelled := []domain.Reservation{*res}
+
+		if req.Scope == domain.ScopeFollowing && reservation.SeriesID != nil {
+			following, err := u.cancelFollowing(ctx, tx, reservation, req.Reason, domain.UserActor(userID))
+			if err != nil {
+				return err
+			}
+			cancelled = append(cancelled, following...)
+		}
+
+		// refund dihitung dari waktu pembatalan, bukan waktu commit
+		now := time.Now()
+		for i, r := range cancelled {
+			refund, err := u.refundReservation(ctx, tx, &r, now)
+			if err != nil {
+				return err
+			}
+			if refund != nil {
+				refunds = append(refunds, refund)
+			}
+			if i == 0 {
+				res.Refund = refund
+			}
+			if err := u.promoteWaitlist(ctx, tx, resource, r.StartTime, r.EndTime); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, u.mapError(err, "error cancelling booking")
+	}
+	u.invalidateAvailability(ctx, res.ResourceID)
+
+	// pembatalan sudah di-commit, refund yang gagal dikirim tetap pending dan dicoba ulang sweep
+	for _, refund := range refunds {
+		if err := sendRefund(ctx, u.paymentRepository, u.paymentProvider, refund); err != nil {
+			u.log.Errorf(err, "failed to send refund %s to payment provider", refund.ID)
+		}
+	}
+
+	return res, nil
+}
+
+// mapError balikin error domain apa adanya, selain itu di log dan jadi internal server error
+func (u *bookingUsecase) mapError(err error, msg string) error {
+	for _, domainErr := range []error{
+		domain.ErrResourceNotFound,
+		domain.ErrBookingNotFound,
+		domain.ErrOutsideOpeningHours,
+		domain.ErrSlotUnavailable,
+		domain.ErrBookingNotCancellable,
+		domain.ErrBookingNotModifiable,
+		domain.ErrInvalidBookingTime,
+		domain.ErrHoldNotFound,
+		domain.ErrSeriesNotFound,
+		domain.ErrInvalidRecurrence,
+		domain.ErrSeriesConflict,
+		domain.ErrWaitlistNotFound,
+		domain.ErrAlreadyWaitlisted,
+		domain.ErrSlotStillAvailable,
+		domain.ErrWaitlistOfferExpired,
+		domain.ErrPartySizeTooLarge,
+		domain.ErrCouponNotFound,
+		domain.ErrCouponExpired,
+		domain.ErrCouponExhausted,
+		domain.ErrCouponNotApplicable,
+		domain.ErrInvalidTransition,
+		domain.ErrForbiden,
+	} {
+		if errors.Is(err, domainErr) {
+			return err
+		}
+	}
+	u.log.Error(err, msg)
+	return domain.ErrInternalServerError
+}
+
+// =============================
+// HELPERS
+// =============================
+
+// lockResource ambil resource + lock row nya sampai transaksi selesai. booking & hold ke resource
+// yang sama jadi antri, supaya cek bentrok dengan hold (redis) dan reservasi (db) konsisten
+func (u *bookingUsecase) lockResource(ctx context.Context, tx *sqlx.Tx, resourceID string) (*domain.Resource, error) {
+	resource, err := u.resourceRepository.GetByIDForUpdate(ctx, tx, resourceID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrResourceNotFound
+		}
+		return nil, err
+	}
+	if !resource.IsActive {
+		return nil, domain.ErrResourceNotFound
+	}
+	return resource, nil
+}
+
+// lockOwnReservation lock resource dulu baru reservasi nya, urutan lock sama dengan create
+// supaya cancel / move / booking baru di resource yang sama gak saling deadlock
+func (u *bookingUsecase) lockOwnReservation(ctx context.Context, tx *sqlx.Tx, userID, id string) (*domain.Reservation, *domain.Resource, error) {
+	reservation, err := u.bookingRepository.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, domain.ErrBookingNotFound
+		}
+		return nil, nil, err
+	}
+	if reservation.UserID != userID {
+		return nil, nil, domain.ErrBookingNotFound
+	}
+
+	resource, err := u.resourceRepository.GetByIDForUpdate(ctx, tx, reservation.ResourceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	reservation, err = u.bookingRepository.GetByIDForUpdate(ctx, tx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return reservation, resource, nil
+}
+
+// checkSlot cek jam buka, reservasi lain, hold aktif dan tawaran waitlist.
+// resource exclusive: gak boleh ada yang overlap (termasuk buffer), race nya tetap dijaga exclusion constraint di db.
+// resource shared: sisa kursi di titik paling ramai harus cukup untuk partySize, race nya dijaga lock row resource.
+// ownClaimID = hold / tawaran waitlist milik sendiri yang diabaikan (confirm hold, accept offer),
+// excludeIDs = reservasi milik sendiri yang diabaikan (pindah jadwal)
+func (u *bookingUsecase) checkSlot(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	resource *domain.Resource,
+	start, end time.Time,
+	partySize int,
+	ownClaimID string,
+	excludeIDs []string,
+) error {
+	if partySize > resource.Capacity {
+		return domain.ErrPartySizeTooLarge
+	}
+
+	loc, err := time.LoadLocation(resource.Timezone)
+	if err != nil {
+		return err
+	}
+	rules, err := u.resourceRepository.GetRules(ctx, resource.ID)
+	if err != nil {
+		return err
+	}
+	localDate := start.In(loc).Format(time.DateOnly)
+	exceptions, err := u.resourceRepository.GetExceptions(ctx, resource.ID, localDate, localDate)
+	if err != nil {
+		return err
+	}
+	if !withinOpeningHours(rules, exceptions, loc, start, end) {
+		return domain.ErrOutsideOpeningHours
+	}
+
+	buffer := time.Duration(resource.BufferMinutes) * time.Minute
+	from, to := start.Add(-buffer), end.Add(buffer)
+
+	now := time.Now()
+	reservations, err := u.bookingRepository.ListOverlapping(ctx, tx, resource.ID, from, to, excludeIDs)
+	if err != nil {
+		return err
+	}
+	reservations, err = u.expireOverdue(ctx, tx, reservations, now)
+	if err != nil {
+		return err
+	}
+	activeHolds, err := u.holdRepository.ListActiveByResource(ctx, resource.ID)
+	if err != nil {
+		return err
+	}
+	holds := make([]domain.Hold, 0, len(activeHolds))
+	for _, hold := range activeHolds {
+		if hold.ID != ownClaimID {
+			holds = append(holds, hold)
+		}
+	}
+	offers, err := u.waitlistRepository.ListOverlappingOffers(ctx, tx, resource.ID, from, to, ownClaimID)
+	if err != nil {
+		return err
+	}
+
+	slot := timeRange{start: start, end: end}
+	busy := toOccupancy(reservations, holds, offers, now)
+
+	if !resource.IsShared() {
+		if conflict := firstBusy(slot, busy, buffer); conflict != nil {
+			return &domain.SlotUnavailableError{
+				ResourceID: resource.ID,
+				StartTime:  conflict.start,
+				EndTime:    conflict.end,
+			}
+		}
+		return nil
+	}
+
+	remaining := resource.Capacity - peakSeats(slot, busy)
+	if remaining < partySize {
+		return &domain.SlotUnavailableError{
+			ResourceID:     resource.ID,
+			StartTime:      start,
+			EndTime:        end,
+			RemainingSeats: &remaining,
+		}
+	}
+
+	return nil
+}
+
+// invalidateAvailability gagal invalidate cukup di log, cache nya tetap expired sendiri
+func (u *bookingUsecase) invalidateAvailability(ctx context.Context, resourceID string) {
+	if err := u.availabilityCache.Invalidate(ctx, resourceID); err != nil {
+		u.log.Error(err, "failed to invalidate availability cache")
+	}
+}
+
+// isModifiable reservasi masih aktif (termasuk yang belum dibayar) dan belum mulai
+func isModifiable(r *domain.Reservation) bool {
+	active := r.Status == domain.ReservationStatusConfirmed || r.Status == domain.ReservationStatusPending
+	return active && r.StartTime.After(time.Now())
+}
+
+func validateBookingTime(start, end time.Time) error {
+	if !end.After(start) || start.Before(time.Now()) {
+		return domain.ErrInvalidBookingTime
+	}
+	// booking minimal per menit, detik harus 0
+	if !start.Equal(start.Truncate(time.Minute)) || !end.Equal(end.Truncate(time.Minute)) {
+		return domain.ErrInvalidBookingTime
+	}
+	return nil
+}
+
+func normalizePagination(limit, offset int) (int, int) {
+	if limit <= 0 {
+		limit = defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
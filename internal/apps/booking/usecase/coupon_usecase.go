@@ -0,0 +1,206 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"booking/internal/domain"
+	"booking/pkg/logger"
+	uow "booking/pkg/unitOfWork"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type couponUsecase struct {
+	couponRepository domain.CouponRepository
+	uow              uow.UnitOfWork
+	log              logger.Logger
+}
+
+func NewCouponUsecase(couponRepository domain.CouponRepository, uow uow.UnitOfWork, log logger.Logger) domain.CouponUsecase {
+	return &couponUsecase{
+		couponRepository: couponRepository,
+		uow:              uow,
+		log:              log,
+	}
+}
+
+// Create permission coupon:manage sudah dicek middleware RequirePermission di route
+func (u *couponUsecase) Create(ctx context.Context, req *domain.CreateCouponDTO) (*domain.Coupon, error) {
+	if req.ValidFrom != nil && req.ValidUntil != nil && !req.ValidFrom.Before(*req.ValidUntil) {
+		return nil, domain.ErrInvalidRequest
+	}
+
+	req.Code = normalizeCouponCode(req.Code)
+	switch req.DiscountType {
+	case domain.CouponTypePercent:
+		req.AmountOff = nil
+	case domain.CouponTypeFixed:
+		req.PercentOff = nil
+	}
+
+	couponID, err := uuid.NewV7()
+	if err != nil {
+		u.log.Error(err, "failed to generate uuidv7 for coupon")
+		return nil, domain.ErrInternalServerError
+	}
+	req.ID = couponID.String()
+
+	var res *domain.Coupon
+	err = u.uow.Do(ctx, func(tx *sqlx.Tx) error {
+		res, err = u.couponRepository.Create(ctx, tx, req)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrCouponCodeExists) || errors.Is(err, domain.ErrResourceNotFound) {
+			return nil, err
+		}
+		u.log.Error(err, "error creating coupon")
+		return nil, domain.ErrInternalServerError
+	}
+
+	return res, nil
+}
+
+func (u *couponUsecase) List(ctx context.Context, q *domain.PaginationQuery) ([]domain.Coupon, error) {
+	limit, offset := normalizePagination(q.Limit, q.Offset)
+
+	res, err := u.couponRepository.List(ctx, limit, offset)
+	if err != nil {
+		u.log.Error(err, "failed to list coupons")
+		return nil, domain.ErrInternalServerError
+	}
+
+	return res, nil
+}
+
+// =============================
+// APPLY COUPON (dipakai booking usecase)
+// =============================
+
+// applyCouponCode validasi kupon lalu potong quote. tx != nil (create booking) → row kupon di-lock sampai commit,
+// jadi kupon dengan sisa 1 kali pakai gak bisa di-redeem 2 request bersamaan
+func (u *bookingUsecase) applyCouponCode(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	userID, resourceID, code string,
+	quote *domain.Quote,
+) (*domain.Coupon, error) {
+	code = normalizeCouponCode(code)
+
+	var (
+		coupon *domain.Coupon
+		err    error
+	)
+	if tx != nil {
+		coupon, err = u.couponRepository.GetByCodeForUpdate(ctx, tx, code)
+	} else {
+		coupon, err = u.couponRepository.GetByCode(ctx, code)
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrCouponNotFound
+		}
+		return nil, err
+	}
+
+	used, err := u.couponRepository.CountUserRedemptions(ctx, tx, coupon.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCoupon(coupon, used, resourceID, quote, time.Now()); err != nil {
+		return nil, err
+	}
+
+	applyCoupon(quote, coupon)
+	return coupon, nil
+}
+
+// redeemCoupon catat pemakaian kupon, di transaksi yang sama dengan insert reservasi
+func (u *bookingUsecase) redeemCoupon(ctx context.Context, tx *sqlx.Tx, coupon *domain.Coupon, reservation *domain.Reservation) error {
+	redemptionID, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+	return u.couponRepository.Redeem(ctx, tx, &domain.CouponRedemption{
+		ID:            redemptionID.String(),
+		CouponID:      coupon.ID,
+		UserID:        reservation.UserID,
+		ReservationID: reservation.ID,
+		Amount:        reservation.Price.Discount,
+	})
+}
+
+// checkCoupon pure, urutan cek: masa berlaku → kuota → scope resource & minimum belanja
+func checkCoupon(coupon *domain.Coupon, usedByUser int, resourceID string, quote *domain.Quote, now time.Time) error {
+	if !coupon.IsActive {
+		return domain.ErrCouponNotFound
+	}
+	if coupon.ValidFrom != nil && now.Before(*coupon.ValidFrom) {
+		return domain.ErrCouponExpired
+	}
+	if coupon.ValidUntil != nil && !now.Before(*coupon.ValidUntil) {
+		return domain.ErrCouponExpired
+	}
+
+	if coupon.MaxRedemptions != nil && coupon.TimesRedeemed >= *coupon.MaxRedemptions {
+		return domain.ErrCouponExhausted
+	}
+	if coupon.MaxRedemptionsPerUser != nil && usedByUser >= *coupon.MaxRedemptionsPerUser {
+		return domain.ErrCouponExhausted
+	}
+
+	if len(coupon.ResourceIDs) > 0 {
+		scoped := false
+		for _, id := range coupon.ResourceIDs {
+			if id == resourceID {
+				scoped = true
+				break
+			}
+		}
+		if !scoped {
+			return domain.ErrCouponNotApplicable
+		}
+	}
+	if coupon.Currency != nil && *coupon.Currency != quote.Currency {
+		return domain.ErrCouponNotApplicable
+	}
+	if quote.Subtotal <= 0 || quote.Subtotal < coupon.MinSpend {
+		return domain.ErrCouponNotApplicable
+	}
+
+	return nil
+}
+
+// applyCoupon tambah line item diskon, potongan gak pernah lebih besar dari subtotal
+func applyCoupon(quote *domain.Quote, coupon *domain.Coupon) {
+	var discount int64
+	switch coupon.DiscountType {
+	case domain.CouponTypePercent:
+		discount = roundDiv(quote.Subtotal*int64(*coupon.PercentOff), 100)
+	case domain.CouponTypeFixed:
+		discount = *coupon.AmountOff
+	}
+	if discount > quote.Subtotal {
+		discount = quote.Subtotal
+	}
+
+	quote.LineItems = append(quote.LineItems, domain.QuoteLineItem{
+		Type:        domain.LineItemDiscount,
+		Description: "Coupon " + coupon.Code,
+		Quantity:    1,
+		UnitAmount:  -discount,
+		Amount:      -discount,
+	})
+	quote.Discount = discount
+	quote.CouponCode = coupon.Code
+	quote.Total = quote.Subtotal - discount
+}
+
+func normalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"booking/internal/domain"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestCheckCoupon(t *testing.T) {
+	now := at("12:00")
+	quote := &domain.Quote{Currency: "IDR", Subtotal: 100000}
+
+	tests := []struct {
+		name       string
+		coupon     domain.Coupon
+		inactive   bool
+		usedByUser int
+		quote      *domain.Quote
+		want       error
+	}{
+		{name: "valid", coupon: domain.Coupon{}},
+		{name: "inactive", inactive: true, want: domain.ErrCouponNotFound},
+		{name: "not yet valid", coupon: domain.Coupon{ValidFrom: ptr(now.Add(time.Minute))}, want: domain.ErrCouponExpired},
+		{name: "valid from now", coupon: domain.Coupon{ValidFrom: ptr(now)}},
+		{name: "expired at valid until", coupon: domain.Coupon{ValidUntil: ptr(now)}, want: domain.ErrCouponExpired},
+		{name: "expired before now", coupon: domain.Coupon{ValidUntil: ptr(now.Add(-time.Hour))}, want: domain.ErrCouponExpired},
+		{name: "last redemption left", coupon: domain.Coupon{MaxRedemptions: ptr(5), TimesRedeemed: 4}},
+		{name: "exhausted", coupon: domain.Coupon{MaxRedemptions: ptr(5), TimesRedeemed: 5}, want: domain.ErrCouponExhausted},
+		{name: "exhausted per user", coupon: domain.Coupon{MaxRedemptionsPerUser: ptr(1)}, usedByUser: 1, want: domain.ErrCouponExhausted},
+		{name: "other resource", coupon: domain.Coupon{ResourceIDs: []string{"other"}}, want: domain.ErrCouponNotApplicable},
+		{name: "scoped resource", coupon: domain.Coupon{ResourceIDs: []string{"other", "resource"}}},
+		{name: "currency mismatch", coupon: domain.Coupon{Currency: ptr("USD")}, want: domain.ErrCouponNotApplicable},
+		{name: "below min spend", coupon: domain.Coupon{MinSpend: 100001}, want: domain.ErrCouponNotApplicable},
+		{name: "exactly min spend", coupon: domain.Coupon{MinSpend: 100000}},
+		{name: "free booking", coupon: domain.Coupon{}, quote: &domain.Quote{Currency: "IDR"}, want: domain.ErrCouponNotApplicable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			coupon := tt.coupon
+			coupon.IsActive = !tt.inactive
+			q := tt.quote
+			if q == nil {
+				q = quote
+			}
+
+			if err := checkCoupon(&coupon, tt.usedByUser, "resource", q, now); !errors.Is(err, tt.want) {
+				t.Fatalf("checkCoupon() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyCoupon(t *testing.T) {
+	tests := []struct {
+		name     string
+		coupon   domain.Coupon
+		subtotal int64
+		discount int64
+	}{
+		{name: "percent", coupon: domain.Coupon{DiscountType: domain.CouponTypePercent, PercentOff: ptr(10)}, subtotal: 25000, discount: 2500},
+		{name: "percent rounds half up", coupon: domain.Coupon{DiscountType: domain.CouponTypePercent, PercentOff: ptr(15)}, subtotal: 333, discount: 50}, // 49.95
+		{name: "percent capped at total", coupon: domain.Coupon{DiscountType: domain.CouponTypePercent, PercentOff: ptr(100)}, subtotal: 999, discount: 999},
+		{name: "fixed", coupon: domain.Coupon{DiscountType: domain.CouponTypeFixed, AmountOff: ptr(int64(5000))}, subtotal: 25000, discount: 5000},
+		{name: "fixed capped at total", coupon: domain.Coupon{DiscountType: domain.CouponTypeFixed, AmountOff: ptr(int64(50000))}, subtotal: 25000, discount: 25000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.coupon.Code = "HEMAT"
+			quote := &domain.Quote{Subtotal: tt.subtotal, Total: tt.subtotal}
+
+			applyCoupon(quote, &tt.coupon)
+
+			if quote.Discount != tt.discount {
+				t.Errorf("discount = %d, want %d", quote.Discount, tt.discount)
+			}
+			if quote.Total != tt.subtotal-tt.discount || quote.Total < 0 {
+				t.Errorf("total = %d, want %d", quote.Total, tt.subtotal-tt.discount)
+			}
+			if quote.CouponCode != "HEMAT" {
+				t.Errorf("coupon code = %q, want HEMAT", quote.CouponCode)
+			}
+			last := quote.LineItems[len(quote.LineItems)-1]
+			if last.Type != domain.LineItemDiscount || last.Amount != -tt.discount {
+				t.Errorf("discount line item = {%s %d}, want {%s %d}", last.Type, last.Amount, domain.LineItemDiscount, -tt.discount)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"booking/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// Flow hold:
+// 1. CreateHold → slot ditahan di redis selama APP_BOOKING_HOLD_TTL, booking / hold lain di window itu ditolak.
+// 2. ConfirmHold → hold jadi reservasi beneran, hold dihapus di transaksi yang sama (sekali pakai).
+// 3. Kalau gak di-confirm, key hold expired sendiri dan slot kebuka lagi.
+
+func (u *bookingUsecase) CreateHold(ctx context.Context, req *domain.CreateHoldDTO) (*domain.Hold, error) {
+	if err := validateBookingTime(req.StartTime, req.EndTime); err != nil {
+		return nil, err
+	}
+	if req.PartySize == 0 {
+		req.PartySize = 1
+	}
+
+	holdID, err := uuid.NewV7()
+	if err != nil {
+		u.log.Error(err, "failed to generate uuidv7 for hold")
+		return nil, domain.ErrInternalServerError
+	}
+
+	ttl := u.config.App.BookingHoldTtl
+	hold := &domain.Hold{
+		ID:         holdID.String(),
+		ResourceID: req.ResourceID,
+		UserID:     req.UserID,
+		StartTime:  req.StartTime,
+		EndTime:    req.EndTime,
+		PartySize:  req.PartySize,
+		ExpiresAt:  time.Now().Add(ttl),
+	}
+
+	// transaksi cuma dipakai untuk lock row resource, supaya hold & booking di resource yang sama antri
+	err = u.uow.Do(ctx, func(tx *sqlx.Tx) error {
+		resource, err := u.lockResource(ctx, tx, req.ResourceID)
+		if err != nil {
+			return err
+		}
+		if err := u.checkSlot(ctx, tx, resource, req.StartTime, req.EndTime, req.PartySize, "", nil); err != nil {
+			return err
+		}
+		return u.holdRepository.Create(ctx, hold, ttl)
+	})
+	if err != nil {
+		return nil, u.mapError(err, "error creating hold")
+	}
+	u.invalidateAvailability(ctx, hold.ResourceID)
+
+	return hold, nil
+}
+
+func (u *bookingUsecase) ConfirmHold(ctx context.Context, userID, holdID string, req *domain.ConfirmHoldDTO) (*domain.Reservation, error) {
+	hold, err := u.getOwnHold(ctx, userID, holdID)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := u.create(ctx, &domain.CreateBookingDTO{
+		UserID:     hold.UserID,
+		ResourceID: hold.ResourceID,
+		StartTime:  hold.StartTime,
+		EndTime:    hold.EndTime,
+		PartySize:  hold.PartySize,
+		CouponCode: req.CouponCode,
+		Notes:      req.Notes,
+	}, hold)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (u *bookingUsecase) ReleaseHold(ctx context.Context, userID, holdID string) error {
+	hold, err := u.getOwnHold(ctx, userID, holdID)
+	if err != nil {
+		return err
+	}
+
+	if err := u.holdRepository.Delete(ctx, hold); err != nil {
+		u.log.Error(err, "failed to release hold")
+		return domain.ErrInternalServerError
+	}
+	u.invalidateAvailability(ctx, hold.ResourceID)
+
+	return nil
+}
+
+func (u *bookingUsecase) getOwnHold(ctx context.Context, userID, holdID string) (*domain.Hold, error) {
+	hold, err := u.holdRepository.GetByID(ctx, holdID)
+	if err != nil {
+		return nil, u.mapError(err, "failed to get hold")
+	}
+	if hold.UserID != userID {
+		return nil, domain.ErrHoldNotFound
+	}
+	return hold, nil
+}
@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"booking/internal/apps/booking/repository"
+	"booking/internal/domain"
+	"booking/pkg/config"
+	"booking/pkg/database"
+	"booking/pkg/logger"
+	"booking/pkg/payment"
+	uow "booking/pkg/unitOfWork"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/redis/go-redis/v9"
+)
+
+// org default dari migration 000016
+const testOrgID = "00000000-0000-0000-0000-000000000001"
+
+// newTestBookingUsecase repository asli: postgres dari TEST_DATABASE_URL (lihat booking_repository_test.go),
+// hold & cache availability di miniredis
+func newTestBookingUsecase(t *testing.T) (*bookingUsecase, *sqlx.DB) {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	db, err := sqlx.Open("pgx", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	database.RunMigrations(db.DB, dsn)
+
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	cfg := &config.Config{App: config.App{
+		BookingHoldTtl:  10 * time.Minute,
+		PaymentTtl:      15 * time.Minute,
+		DefaultCurrency: "IDR",
+	}}
+
+	u := NewBookingUsecase(
+		repository.NewBookingRepository(db),
+		repository.NewResourceRepository(db),
+		repository.NewHoldRepository(rdb),
+		repository.NewWaitlistRepository(db),
+		repository.NewRatePlanRepository(db),
+		repository.NewCouponRepository(db),
+		repository.NewBookingEventRepository(db),
+		repository.NewPaymentRepository(db),
+		payment.NewFakeProvider("test"),
+		repository.NewAvailabilityCache(rdb),
+		uow.NewUnitOfWork(db),
+		cfg,
+		logger.Logger{},
+	).(*bookingUsecase)
+	return u, db
+}
+
+// seedOpenResource resource gratis (tanpa rate plan) yang buka 09:00 - 12:00 UTC di hari start
+func seedOpenResource(t *testing.T, db *sqlx.DB, capacity int, start time.Time) (userID, resourceID string) {
+	t.Helper()
+
+	userID = uuid.NewString()
+	resourceID = uuid.NewString()
+	db.MustExec(`INSERT INTO users (id, name) VALUES ($1, 'hold test')`, userID)
+	db.MustExec(`
+		INSERT INTO resources (id, org_id, owner_id, name, capacity)
+			VALUES ($1, $2, $3, 'hold test', $4)
+	`, resourceID, testOrgID, userID, capacity)
+	db.MustExec(`
+		INSERT INTO availability_rules (id, resource_id, day_of_week, start_time, end_time)
+			VALUES ($1, $2, $3, '09:00', '12:00')
+	`, uuid.NewString(), resourceID, int(start.Weekday()))
+	return userID, resourceID
+}
+
+// confirm yang bersamaan untuk hold yang sama: cuma satu yang jadi reservasi, walaupun kursi resource masih cukup
+func TestConfirmHold_ConcurrentSameHold(t *testing.T) {
+	u, db := newTestBookingUsecase(t)
+	ctx := domain.WithOrg(context.Background(), &domain.OrgContext{OrgID: testOrgID})
+	start := time.Now().UTC().Add(48 * time.Hour).Truncate(24 * time.Hour).Add(10 * time.Hour)
+	userID, resourceID := seedOpenResource(t, db, 4, start)
+
+	hold, err := u.CreateHold(ctx, &domain.CreateHoldDTO{
+		UserID:     userID,
+		ResourceID: resourceID,
+		StartTime:  start,
+		EndTime:    start.Add(time.Hour),
+		PartySize:  2,
+	})
+	if err != nil {
+		t.Fatalf("CreateHold() error = %v", err)
+	}
+
+	const n = 8
+	var (
+		wg      sync.WaitGroup
+		startCh = make(chan struct{})
+		errs    = make([]error, n)
+	)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-startCh
+			_, errs[i] = u.ConfirmHold(ctx, userID, hold.ID, &domain.ConfirmHoldDTO{})
+		}(i)
+	}
+	close(startCh)
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case !errors.Is(err, domain.ErrHoldNotFound):
+			t.Fatalf("expected ErrHoldNotFound, got %v", err)
+		}
+	}
+	if succeeded != 1 {
+		t.Fatalf("expected exactly 1 confirmed hold, got %d", succeeded)
+	}
+
+	var count int
+	if err := db.Get(&count, `SELECT count(*) FROM reservations WHERE resource_id = $1`, resourceID); err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Fatalf("expected 1 stored reservation, got %d", count)
+	}
+}
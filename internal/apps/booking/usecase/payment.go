@@ -0,0 +1,355 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"booking/internal/domain"
+	"booking/pkg/logger"
+	uow "booking/pkg/unitOfWork"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	reconcileBatch = 100             // jumlah payment / refund yang diproses per sweep, sisanya di sweep berikutnya
+	reconcileDelay = 1 * time.Minute // beri waktu request yang baru commit menyelesaikan panggilan ke provider sendiri
+)
+
+type paymentUsecase struct {
+	paymentRepository      domain.PaymentRepository
+	bookingRepository      domain.BookingRepository
+	bookingEventRepository domain.BookingEventRepository
+	paymentProvider        domain.PaymentProvider
+	uow                    uow.UnitOfWork
+	log                    logger.Logger
+}
+
+func NewPaymentUsecase(
+	paymentRepository domain.PaymentRepository,
+	bookingRepository domain.BookingRepository,
+	bookingEventRepository domain.BookingEventRepository,
+	paymentProvider domain.PaymentProvider,
+	uow uow.UnitOfWork,
+	log logger.Logger,
+) domain.PaymentUsecase {
+	return &paymentUsecase{
+		paymentRepository:      paymentRepository,
+		bookingRepository:      bookingRepository,
+		bookingEventRepository: bookingEventRepository,
+		paymentProvider:        paymentProvider,
+		uow:                    uow,
+		log:                    log,
+	}
+}
+
+// HandleWebhook idempotent: payment yang statusnya sudah final diabaikan, jadi webhook yang dikirim ulang provider aman
+func (u *paymentUsecase) HandleWebhook(ctx context.Context, payload []byte, signature string) error {
+	event, err := u.paymentProvider.VerifyWebhook(payload, signature)
+	if err != nil {
+		return domain.ErrInvalidWebhookSignature
+	}
+
+	switch event.Type {
+	case domain.PaymentEventAuthorized, domain.PaymentEventSucceeded, domain.PaymentEventFailed:
+	default:
+		u.log.Infof("ignoring payment event %s of type %s", event.ID, event.Type)
+		return nil
+	}
+
+	payment, err := u.paymentRepository.GetByProviderRef(ctx, u.paymentProvider.Name(), event.IntentID)
+	if err != nil {
+		// intent yang gak dikenal tetap di-ack, kalau gak provider bakal retry terus
+		if errors.Is(err, sql.ErrNoRows) {
+			u.log.Warnf("payment event %s for unknown intent %s", event.ID, event.IntentID)
+			return nil
+		}
+		u.log.Error(err, "failed to get payment by provider ref")
+		return domain.ErrInternalServerError
+	}
+	// webhook gak lewat ResolveOrg, query selanjutnya di-scope ke org pemilik payment
+	ctx = domain.WithOrg(ctx, &domain.OrgContext{OrgID: payment.OrgID})
+
+	// urutan lock reservasi dulu baru payment, sama dengan jalur cancel
+	var (
+		capture bool
+		refund  *domain.Refund
+	)
+	err = u.uow.Do(ctx, func(tx *sqlx.Tx) error {
+		reservation, err := u.bookingRepository.GetByIDForUpdate(ctx, tx, payment.ReservationID)
+		if err != nil {
+			return err
+		}
+		payment, err := u.paymentRepository.GetByIDForUpdate(ctx, tx, payment.ID)
+		if err != nil {
+			return err
+		}
+		// failed masih bisa jadi succeeded kalau user bayar ulang, authorized menunggu capture, status lain sudah final
+		switch payment.Status {
+		case domain.PaymentStatusPending, domain.PaymentStatusFailed, domain.PaymentStatusAuthorized:
+		default:
+			return nil
+		}
+
+		switch event.Type {
+		case domain.PaymentEventFailed:
+			// reservasi pending tetap pending, user masih bisa bayar ulang dengan intent yang sama
+			if payment.Status == domain.PaymentStatusAuthorized {
+				return nil
+			}
+			_, err = u.paymentRepository.UpdateStatus(ctx, tx, payment.ID, domain.PaymentStatusFailed, 0)
+			return err
+		case domain.PaymentEventAuthorized:
+			// dana di-hold: dicatat dulu, capture ke provider setelah commit supaya lock gak ditahan selama request ke provider
+			capture = true
+			if payment.Status == domain.PaymentStatusAuthorized {
+				return nil
+			}
+			_, err = u.paymentRepository.UpdateStatus(ctx, tx, payment.ID, domain.PaymentStatusAuthorized, 0)
+			return err
+		}
+
+		refund, err = u.settlePayment(ctx, tx, reservation, payment, "payment "+event.ID)
+		return err
+	})
+	switch {
+	case err != nil:
+	case capture:
+		err = u.capturePayment(ctx, payment, "payment "+event.ID)
+	case refund != nil:
+		err = sendRefund(ctx, u.paymentRepository, u.paymentProvider, refund)
+	}
+	if err != nil {
+		u.log.Error(err, "error handling payment webhook")
+		return domain.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// ReconcilePayments dijalankan berkala: pembuatan intent, capture payment authorized dan refund pending yang gagal / terputus
+// setelah commit dicoba ulang. idempotency key intent & capture = id payment, refund = id refund,
+// jadi panggilan ganda gak membuat intent / memindahkan dana dua kali
+func (u *paymentUsecase) ReconcilePayments(ctx context.Context) error {
+	pending, err := u.paymentRepository.ListWithoutIntent(ctx, reconcileDelay, reconcileBatch)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range pending {
+		orgCtx := domain.WithOrg(ctx, &domain.OrgContext{OrgID: p.OrgID})
+		if err := createPaymentIntent(orgCtx, u.paymentRepository, u.paymentProvider, &p); err != nil {
+			u.log.Errorf(err, "failed to create payment intent for payment %s", p.ID)
+		}
+	}
+
+	payments, err := u.paymentRepository.ListAuthorized(ctx, reconcileDelay, reconcileBatch)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range payments {
+		// satu payment gagal gak menghentikan yang lain, dicoba lagi di sweep berikutnya
+		orgCtx := domain.WithOrg(ctx, &domain.OrgContext{OrgID: p.OrgID})
+		if err := u.capturePayment(orgCtx, &p, "payment captured"); err != nil {
+			u.log.Errorf(err, "failed to capture payment %s", p.ID)
+		}
+	}
+
+	refunds, err := u.paymentRepository.ListPendingRefunds(ctx, reconcileDelay, reconcileBatch)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range refunds {
+		orgCtx := domain.WithOrg(ctx, &domain.OrgContext{OrgID: r.OrgID})
+		if err := sendRefund(orgCtx, u.paymentRepository, u.paymentProvider, &r); err != nil {
+			u.log.Errorf(err, "failed to send refund %s to payment provider", r.ID)
+		}
+	}
+	return nil
+}
+
+// capturePayment dipanggil di luar transaksi. gagal = payment tetap authorized, dicoba ulang webhook berikutnya / ReconcilePayments
+func (u *paymentUsecase) capturePayment(ctx context.Context, payment *domain.Payment, reason string) error {
+	if err := u.paymentProvider.Capture(ctx, domain.NilStringHandler(payment.ProviderRef), payment.ID); err != nil {
+		return err
+	}
+
+	var refund *domain.Refund
+	err := u.uow.Do(ctx, func(tx *sqlx.Tx) error {
+		reservation, err := u.bookingRepository.GetByIDForUpdate(ctx, tx, payment.ReservationID)
+		if err != nil {
+			return err
+		}
+		payment, err := u.paymentRepository.GetByIDForUpdate(ctx, tx, payment.ID)
+		if err != nil {
+			return err
+		}
+		// sudah diselesaikan webhook succeeded / sweep lain
+		if payment.Status != domain.PaymentStatusAuthorized {
+			return nil
+		}
+		refund, err = u.settlePayment(ctx, tx, reservation, payment, reason)
+		return err
+	})
+	if err != nil || refund == nil {
+		return err
+	}
+	return sendRefund(ctx, u.paymentRepository, u.paymentProvider, refund)
+}
+
+// settlePayment dana sudah masuk: reservasi pending jadi confirmed, reservasi yang sudah batal dikembalikan penuh.
+// refund yang dikembalikan masih pending, dikirim ke provider setelah commit
+func (u *paymentUsecase) settlePayment(ctx context.Context, tx *sqlx.Tx, reservation *domain.Reservation, payment *domain.Payment, reason string) (*domain.Refund, error) {
+	if reservation.Status != domain.ReservationStatusPending {
+		return u.refundLatePayment(ctx, tx, payment)
+	}
+
+	if _, err := u.paymentRepository.UpdateStatus(ctx, tx, payment.ID, domain.PaymentStatusSucceeded, 0); err != nil {
+		return nil, err
+	}
+	_, err := transitionReservation(ctx, tx, u.bookingRepository, u.bookingEventRepository, reservation.ID, domain.ReservationStatusConfirmed, reason, domain.SystemActor)
+	return nil, err
+}
+
+func (u *paymentUsecase) refundLatePayment(ctx context.Context, tx *sqlx.Tx, payment *domain.Payment) (*domain.Refund, error) {
+	if _, err := u.paymentRepository.UpdateStatus(ctx, tx, payment.ID, domain.PaymentStatusRefunded, payment.Amount); err != nil {
+		return nil, err
+	}
+
+	refundID, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+	return createRefund(ctx, tx, u.paymentRepository, payment, &domain.Refund{
+		ID:            refundID.String(),
+		PaymentID:     payment.ID,
+		ReservationID: payment.ReservationID,
+		Amount:        payment.Amount,
+		RefundBp:      domain.BasisPoint,
+		Reason:        domain.RefundReasonLatePayment,
+	})
+}
+
+func (u *paymentUsecase) GetByReservation(ctx context.Context, userID, reservationID string) (*domain.Payment, error) {
+	if _, err := uuid.Parse(reservationID); err != nil {
+		return nil, domain.ErrBookingNotFound
+	}
+
+	reservation, err := u.bookingRepository.GetByID(ctx, reservationID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrBookingNotFound
+		}
+		u.log.Error(err, "failed to get booking of payment")
+		return nil, domain.ErrInternalServerError
+	}
+	if reservation.UserID != userID {
+		return nil, domain.ErrBookingNotFound
+	}
+
+	res, err := u.paymentRepository.GetLatestByReservation(ctx, reservationID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrPaymentNotFound
+		}
+		u.log.Error(err, "failed to get payment")
+		return nil, domain.ErrInternalServerError
+	}
+	// intent yang gagal dibuat setelah booking dicoba lagi saat client butuh client secret nya
+	if res.Status == domain.PaymentStatusPending && reservation.Status == domain.ReservationStatusPending {
+		if err := createPaymentIntent(ctx, u.paymentRepository, u.paymentProvider, res); err != nil {
+			u.log.Errorf(err, "failed to create payment intent for payment %s", res.ID)
+		}
+	}
+
+	return res, nil
+}
+
+// =============================
+// INSERT RESERVATION (dipakai booking usecase)
+// =============================
+
+// insertReservation reservasi gratis langsung confirmed, yang berbayar jadi pending dengan payment yang belum punya intent.
+// intent dibuat setelah commit (createPaymentIntents), supaya gak ada panggilan ke provider selama lock resource ditahan.
+// reason = asal reservasi untuk event (hold, waitlist, series)
+func (u *bookingUsecase) insertReservation(ctx context.Context, tx *sqlx.Tx, req *domain.CreateBookingDTO, actor domain.BookingActor, reason string) (*domain.Reservation, error) {
+	req.Status = domain.ReservationStatusConfirmed
+	if req.Price != nil && req.Price.Total > 0 {
+		req.Status = domain.ReservationStatusPending
+		dueAt := time.Now().Add(u.config.App.PaymentTtl)
+		req.PaymentDueAt = &dueAt
+	}
+	if err := domain.ValidateTransition("", req.Status); err != nil {
+		return nil, err
+	}
+
+	res, err := u.bookingRepository.Create(ctx, tx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := recordBookingEvent(ctx, tx, u.bookingEventRepository, res.ID, "", res.Status, reason, actor); err != nil {
+		return nil, err
+	}
+	if res.Status != domain.ReservationStatusPending {
+		return res, nil
+	}
+
+	paymentID, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+	res.Payment, err = u.paymentRepository.Create(ctx, tx, &domain.Payment{
+		ID:            paymentID.String(),
+		ReservationID: res.ID,
+		UserID:        res.UserID,
+		Provider:      u.paymentProvider.Name(),
+		Amount:        res.Price.Total,
+		Currency:      res.Price.Currency,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// createPaymentIntents dipanggil setelah commit untuk reservasi yang baru dibuat user. gagal gak membatalkan booking:
+// payment tetap tanpa intent, dicoba ulang GetByReservation / ReconcilePayments
+func (u *bookingUsecase) createPaymentIntents(ctx context.Context, reservations ...*domain.Reservation) {
+	for _, r := range reservations {
+		if r.Payment == nil {
+			continue
+		}
+		if err := createPaymentIntent(ctx, u.paymentRepository, u.paymentProvider, r.Payment); err != nil {
+			u.log.Errorf(err, "failed to create payment intent for payment %s", r.Payment.ID)
+		}
+	}
+}
+
+// createPaymentIntent di luar transaksi. idempotency key = id payment, jadi retry mendapat intent yang sama
+func createPaymentIntent(
+	ctx context.Context,
+	paymentRepository domain.PaymentRepository,
+	paymentProvider domain.PaymentProvider,
+	payment *domain.Payment,
+) error {
+	if payment.ProviderRef != nil {
+		return nil
+	}
+
+	intent, err := paymentProvider.CreateIntent(ctx, payment.Amount, payment.Currency, payment.ReservationID, payment.ID)
+	if err != nil {
+		return err
+	}
+	res, err := paymentRepository.SetIntent(ctx, payment.ID, intent.ID, intent.ClientSecret)
+	if err != nil {
+		return err
+	}
+	*payment = *res
+	return nil
+}
@@ -0,0 +1,253 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"booking/internal/domain"
+)
+
+// Hitungan harga:
+// 1. durasi booking dipotong per hari kalender + batas jam peak (timezone resource).
+// 2. tiap potongan = hourly rate x menit / 60 x multiplier hari (weekday / weekend) x multiplier peak.
+// 3. total per hari dibatasi daily rate (kalau diisi).
+// 4. ditambah per seat price x party size, lalu dinaikkan ke minimum charge kalau masih kurang.
+// Semua nominal integer minor unit, pembulatan half up di tiap line item.
+
+func (u *bookingUsecase) Quote(ctx context.Context, req *domain.QuoteDTO) (*domain.Quote, error) {
+	if err := validateBookingTime(req.StartTime, req.EndTime); err != nil {
+		return nil, err
+	}
+	if req.PartySize == 0 {
+		req.PartySize = 1
+	}
+
+	resource, err := u.resourceRepository.GetByID(ctx, req.ResourceID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrResourceNotFound
+		}
+		u.log.Error(err, "failed to get resource")
+		return nil, domain.ErrInternalServerError
+	}
+	if !resource.IsActive {
+		return nil, domain.ErrResourceNotFound
+	}
+	if req.PartySize > resource.Capacity {
+		return nil, domain.ErrPartySizeTooLarge
+	}
+
+	res, err := u.quoteFor(ctx, resource, req.StartTime, req.EndTime, req.PartySize)
+	if err != nil {
+		u.log.Error(err, "failed to compute quote")
+		return nil, domain.ErrInternalServerError
+	}
+	if req.CouponCode != "" {
+		if _, err := u.applyCouponCode(ctx, nil, req.UserID, resource.ID, req.CouponCode, res); err != nil {
+			return nil, u.mapError(err, "failed to apply coupon to quote")
+		}
+	}
+
+	return res, nil
+}
+
+// quoteFor harga booking dari rate plan resource, resource tanpa rate plan dianggap gratis
+func (u *bookingUsecase) quoteFor(ctx context.Context, resource *domain.Resource, start, end time.Time, partySize int) (*domain.Quote, error) {
+	plan, err := u.ratePlanRepository.GetByResource(ctx, resource.ID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		plan = &domain.RatePlan{
+			Currency:            u.config.App.DefaultCurrency,
+			WeekdayMultiplierBp: domain.BasisPoint,
+			WeekendMultiplierBp: domain.BasisPoint,
+		}
+	}
+
+	loc, err := time.LoadLocation(resource.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	res := computeQuote(plan, loc, start, end, partySize)
+	res.ResourceID = resource.ID
+	return res, nil
+}
+
+// =============================
+// PURE FUNCTIONS
+// =============================
+
+type priceSegment struct {
+	timeRange
+	multiplierBp int
+}
+
+func computeQuote(plan *domain.RatePlan, loc *time.Location, start, end time.Time, partySize int) *domain.Quote {
+	quote := &domain.Quote{
+		StartTime: start,
+		EndTime:   end,
+		PartySize: partySize,
+		Currency:  plan.Currency,
+		LineItems: []domain.QuoteLineItem{},
+	}
+
+	if plan.HourlyRate > 0 {
+		for _, day := range splitByDay(start, end, loc) {
+			dayBp := dayMultiplier(plan, day.start)
+			segments := priceSegments(plan, loc, day, dayBp)
+
+			items := make([]domain.QuoteLineItem, 0, len(segments))
+			var dayTotal int64
+			for _, s := range segments {
+				minutes := int(s.end.Sub(s.start).Minutes())
+				amount := roundDiv(plan.HourlyRate*int64(minutes)*int64(s.multiplierBp), 60*domain.BasisPoint)
+				items = append(items, domain.QuoteLineItem{
+					Type:         domain.LineItemTime,
+					Description:  describeRange(s.timeRange),
+					Quantity:     minutes,
+					UnitAmount:   plan.HourlyRate,
+					MultiplierBp: s.multiplierBp,
+					Amount:       amount,
+				})
+				dayTotal += amount
+			}
+
+			// lebih mahal dari daily rate → cukup bayar daily rate untuk hari itu
+			if plan.DailyRate > 0 {
+				dailyCap := roundDiv(plan.DailyRate*int64(dayBp), domain.BasisPoint)
+				if dayTotal > dailyCap {
+					items = []domain.QuoteLineItem{{
+						Type:         domain.LineItemDaily,
+						Description:  fmt.Sprintf("Daily rate %s", day.start.Format("Mon 2006-01-02")),
+						Quantity:     1,
+						UnitAmount:   plan.DailyRate,
+						MultiplierBp: dayBp,
+						Amount:       dailyCap,
+					}}
+				}
+			}
+			quote.LineItems = append(quote.LineItems, items...)
+		}
+	}
+
+	if plan.PerSeatPrice > 0 {
+		quote.LineItems = append(quote.LineItems, domain.QuoteLineItem{
+			Type:        domain.LineItemSeat,
+			Description: fmt.Sprintf("%d seat(s)", partySize),
+			Quantity:    partySize,
+			UnitAmount:  plan.PerSeatPrice,
+			Amount:      plan.PerSeatPrice * int64(partySize),
+		})
+	}
+
+	for _, item := range quote.LineItems {
+		quote.Subtotal += item.Amount
+	}
+	if quote.Subtotal < plan.MinimumCharge {
+		diff := plan.MinimumCharge - quote.Subtotal
+		quote.LineItems = append(quote.LineItems, domain.QuoteLineItem{
+			Type:        domain.LineItemMinimumCharge,
+			Description: "Minimum charge adjustment",
+			Quantity:    1,
+			UnitAmount:  diff,
+			Amount:      diff,
+		})
+		quote.Subtotal = plan.MinimumCharge
+	}
+	quote.Total = quote.Subtotal
+
+	return quote
+}
+
+// splitByDay potong [start, end) di tiap tengah malam timezone resource
+func splitByDay(start, end time.Time, loc *time.Location) []timeRange {
+	var days []timeRange
+	for cursor := start.In(loc); cursor.Before(end); {
+		y, m, d := cursor.Date()
+		next := time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+		if next.After(end) {
+			next = end.In(loc)
+		}
+		days = append(days, timeRange{start: cursor, end: next})
+		cursor = next
+	}
+	return days
+}
+
+// priceSegments potong satu hari di batas jam peak, segmen berurutan dengan multiplier sama digabung
+func priceSegments(plan *domain.RatePlan, loc *time.Location, day timeRange, dayBp int) []priceSegment {
+	cuts := []time.Time{day.start, day.end}
+	for _, p := range plan.Peaks {
+		if p.DayOfWeek != nil && *p.DayOfWeek != int(day.start.Weekday()) {
+			continue
+		}
+		for _, t := range []time.Time{clockOn(day.start, p.StartTime, loc), clockOn(day.start, p.EndTime, loc)} {
+			if t.After(day.start) && t.Before(day.end) {
+				cuts = append(cuts, t)
+			}
+		}
+	}
+	sort.Slice(cuts, func(i, j int) bool { return cuts[i].Before(cuts[j]) })
+
+	var segments []priceSegment
+	for i := 0; i < len(cuts)-1; i++ {
+		r := timeRange{start: cuts[i], end: cuts[i+1]}
+		if !r.start.Before(r.end) {
+			continue
+		}
+		bp := dayBp * peakMultiplier(plan, loc, r) / domain.BasisPoint
+
+		if n := len(segments); n > 0 && segments[n-1].multiplierBp == bp && segments[n-1].end.Equal(r.start) {
+			segments[n-1].end = r.end
+			continue
+		}
+		segments = append(segments, priceSegment{timeRange: r, multiplierBp: bp})
+	}
+	return segments
+}
+
+func dayMultiplier(plan *domain.RatePlan, day time.Time) int {
+	if wd := day.Weekday(); wd == time.Saturday || wd == time.Sunday {
+		return plan.WeekendMultiplierBp
+	}
+	return plan.WeekdayMultiplierBp
+}
+
+// peakMultiplier multiplier peak tertinggi yang mencakup segmen, 1x kalau gak ada
+func peakMultiplier(plan *domain.RatePlan, loc *time.Location, r timeRange) int {
+	bp := domain.BasisPoint
+	found := false
+	for _, p := range plan.Peaks {
+		if p.DayOfWeek != nil && *p.DayOfWeek != int(r.start.Weekday()) {
+			continue
+		}
+		window := timeRange{start: clockOn(r.start, p.StartTime, loc), end: clockOn(r.start, p.EndTime, loc)}
+		if !window.contains(r) {
+			continue
+		}
+		if !found || p.MultiplierBp > bp {
+			bp = p.MultiplierBp
+			found = true
+		}
+	}
+	return bp
+}
+
+func describeRange(r timeRange) string {
+	endClock := r.end.Format("15:04")
+	if endClock == "00:00" && r.end.After(r.start) {
+		endClock = "24:00"
+	}
+	return fmt.Sprintf("%s %s-%s", r.start.Format("Mon 2006-01-02"), r.start.Format("15:04"), endClock)
+}
+
+// roundDiv pembagian integer dengan pembulatan half up (n selalu >= 0)
+func roundDiv(n, d int64) int64 {
+	return (n + d/2) / d
+}
@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"booking/internal/domain"
+)
+
+func testRatePlan() *domain.RatePlan {
+	return &domain.RatePlan{
+		Currency:            "IDR",
+		HourlyRate:          10000,
+		WeekdayMultiplierBp: domain.BasisPoint,
+		WeekendMultiplierBp: 12000,
+		Peaks:               []domain.RatePlanPeak{{StartTime: "18:00", EndTime: "21:00", MultiplierBp: 15000}},
+	}
+}
+
+func TestComputeQuote(t *testing.T) {
+	wib := time.FixedZone("WIB", 7*60*60)
+	friday := testMonday.AddDate(0, 0, 4)
+	saturday := testMonday.AddDate(0, 0, 5)
+	on := func(day time.Time, clock string) time.Time { return clockOn(day, clock, time.UTC) }
+
+	type item struct {
+		kind   string
+		bp     int
+		amount int64
+	}
+	tests := []struct {
+		name       string
+		plan       func(p *domain.RatePlan)
+		loc        *time.Location
+		start, end time.Time
+		partySize  int
+		want       []item
+		subtotal   int64
+	}{
+		{
+			name:     "off-peak only",
+			start:    at("09:00"),
+			end:      at("11:00"),
+			want:     []item{{domain.LineItemTime, 10000, 20000}},
+			subtotal: 20000,
+		},
+		{
+			name:     "split at peak start",
+			start:    at("17:00"),
+			end:      at("19:00"),
+			want:     []item{{domain.LineItemTime, 10000, 10000}, {domain.LineItemTime, 15000, 15000}},
+			subtotal: 25000,
+		},
+		{
+			name:     "split at peak end",
+			start:    at("20:30"),
+			end:      at("22:00"),
+			want:     []item{{domain.LineItemTime, 15000, 7500}, {domain.LineItemTime, 10000, 10000}},
+			subtotal: 17500,
+		},
+		{
+			name:     "peak boundary in resource timezone",
+			loc:      wib,
+			start:    at("10:00"), // 17:00 WIB
+			end:      at("12:00"), // 19:00 WIB
+			want:     []item{{domain.LineItemTime, 10000, 10000}, {domain.LineItemTime, 15000, 15000}},
+			subtotal: 25000,
+		},
+		{
+			name:     "weekend multiplier stacks with peak",
+			start:    on(saturday, "20:00"),
+			end:      on(saturday, "22:00"),
+			want:     []item{{domain.LineItemTime, 18000, 18000}, {domain.LineItemTime, 12000, 12000}},
+			subtotal: 30000,
+		},
+		{
+			name:     "split at midnight into weekend",
+			start:    on(friday, "23:00"),
+			end:      on(saturday, "01:00"),
+			want:     []item{{domain.LineItemTime, 10000, 10000}, {domain.LineItemTime, 12000, 12000}},
+			subtotal: 22000,
+		},
+		{
+			name:     "half rounds up",
+			plan:     func(p *domain.RatePlan) { p.HourlyRate = 3 },
+			start:    at("09:00"),
+			end:      at("09:10"), // 0.5
+			want:     []item{{domain.LineItemTime, 10000, 1}},
+			subtotal: 1,
+		},
+		{
+			name:     "multiplier rounding",
+			plan:     func(p *domain.RatePlan) { p.HourlyRate = 100; p.WeekdayMultiplierBp = 12500 },
+			start:    at("17:50"),
+			end:      at("18:10"),
+			want:     []item{{domain.LineItemTime, 12500, 21}, {domain.LineItemTime, 18750, 31}}, // 20.83, 31.25
+			subtotal: 52,
+		},
+		{
+			name:     "daily cap",
+			plan:     func(p *domain.RatePlan) { p.DailyRate = 50000 },
+			start:    at("08:00"),
+			end:      at("20:00"),
+			want:     []item{{domain.LineItemDaily, 10000, 50000}},
+			subtotal: 50000,
+		},
+		{
+			name:      "per seat and minimum charge",
+			plan:      func(p *domain.RatePlan) { p.HourlyRate = 0; p.PerSeatPrice = 5000; p.MinimumCharge = 20000 },
+			start:     at("09:00"),
+			end:       at("10:00"),
+			partySize: 3,
+			want:      []item{{domain.LineItemSeat, 0, 15000}, {domain.LineItemMinimumCharge, 0, 5000}},
+			subtotal:  20000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := testRatePlan()
+			if tt.plan != nil {
+				tt.plan(plan)
+			}
+			loc := tt.loc
+			if loc == nil {
+				loc = time.UTC
+			}
+			partySize := tt.partySize
+			if partySize == 0 {
+				partySize = 1
+			}
+
+			got := computeQuote(plan, loc, tt.start, tt.end, partySize)
+
+			if len(got.LineItems) != len(tt.want) {
+				t.Fatalf("got %d line items, want %d: %+v", len(got.LineItems), len(tt.want), got.LineItems)
+			}
+			for i, w := range tt.want {
+				li := got.LineItems[i]
+				if li.Type != w.kind || li.MultiplierBp != w.bp || li.Amount != w.amount {
+					t.Errorf("line item %d = {%s %d %d}, want {%s %d %d}", i, li.Type, li.MultiplierBp, li.Amount, w.kind, w.bp, w.amount)
+				}
+			}
+			if got.Subtotal != tt.subtotal || got.Total != tt.subtotal {
+				t.Errorf("subtotal / total = %d / %d, want %d", got.Subtotal, got.Total, tt.subtotal)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"booking/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// refundReservation catat refund pembayaran reservasi yang baru dibatalkan sesuai snapshot policy nya.
+// dipanggil di transaksi cancel setelah row reservasi di-lock, jadi urutan lock tetap reservasi dulu baru payment.
+// dana nya belum bergerak, refund pending dikirim ke provider lewat sendRefund setelah commit.
+// payment yang masih pending gak di-refund di sini, kalau nanti masuk webhook yang mengembalikan penuh
+func (u *bookingUsecase) refundReservation(ctx context.Context, tx *sqlx.Tx, reservation *domain.Reservation, now time.Time) (*domain.Refund, error) {
+	payment, err := u.paymentRepository.GetLatestByReservation(ctx, reservation.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	payment, err = u.paymentRepository.GetByIDForUpdate(ctx, tx, payment.ID)
+	if err != nil {
+		return nil, err
+	}
+	if payment.Status != domain.PaymentStatusSucceeded {
+		return nil, nil
+	}
+
+	refundable := payment.Amount - payment.RefundedAmount
+	refundBp, amount := computeRefund(reservation.CancellationPolicy, refundable, reservation.StartTime, now)
+
+	if amount > 0 {
+		status := domain.PaymentStatusPartially
+		if amount == refundable {
+			status = domain.PaymentStatusRefunded
+		}
+		if _, err := u.paymentRepository.UpdateStatus(ctx, tx, payment.ID, status, payment.RefundedAmount+amount); err != nil {
+			return nil, err
+		}
+	}
+
+	// tetap dicatat walau nominal nya 0, supaya alasan dana ditahan bisa dilacak
+	refundID, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+	return createRefund(ctx, tx, u.paymentRepository, payment, &domain.Refund{
+		ID:             refundID.String(),
+		PaymentID:      payment.ID,
+		ReservationID:  reservation.ID,
+		Amount:         amount,
+		RetainedAmount: refundable - amount,
+		RefundBp:       refundBp,
+		Reason:         domain.RefundReasonCancellation,
+	})
+}
+
+// createRefund refund dengan nominal 0 langsung succeeded, sisanya pending sampai provider berhasil
+func createRefund(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	paymentRepository domain.PaymentRepository,
+	payment *domain.Payment,
+	refund *domain.Refund,
+) (*domain.Refund, error) {
+	refund.Status = domain.RefundStatusPending
+	if refund.Amount == 0 {
+		refund.Status = domain.RefundStatusSucceeded
+	}
+
+	res, err := paymentRepository.CreateRefund(ctx, tx, refund)
+	if err != nil {
+		return nil, err
+	}
+	res.OrgID = payment.OrgID
+	res.ProviderRef = domain.NilStringHandler(payment.ProviderRef)
+	return res, nil
+}
+
+// sendRefund kirim refund yang sudah di-commit ke provider, di luar transaksi. idempotency key = id refund,
+// jadi gagal di tengah (timeout, proses mati) aman dicoba ulang oleh ReconcilePayments
+func sendRefund(
+	ctx context.Context,
+	paymentRepository domain.PaymentRepository,
+	paymentProvider domain.PaymentProvider,
+	refund *domain.Refund,
+) error {
+	if refund.Status != domain.RefundStatusPending {
+		return nil
+	}
+	if err := paymentProvider.Refund(ctx, refund.ProviderRef, refund.Amount, refund.ID); err != nil {
+		return err
+	}
+	if err := paymentRepository.UpdateRefundStatus(ctx, refund.ID, domain.RefundStatusSucceeded); err != nil {
+		return err
+	}
+	refund.Status = domain.RefundStatusSucceeded
+	return nil
+}
+
+// computeRefund pure, nominal refund dari paid sesuai policy kalau dibatalkan di now
+func computeRefund(policy *domain.CancellationPolicy, paid int64, start, now time.Time) (int, int64) {
+	refundBp := refundBpFor(policy, start, now)
+	return refundBp, roundDiv(paid*int64(refundBp), domain.BasisPoint)
+}
+
+// refundBpFor tanpa policy = refund penuh, sudah lewat jam mulai = gak ada refund.
+// selain itu tier dengan hours_before terbesar yang masih terpenuhi
+func refundBpFor(policy *domain.CancellationPolicy, start, now time.Time) int {
+	if !now.Before(start) {
+		return 0
+	}
+	if policy == nil || len(policy.Tiers) == 0 {
+		return domain.BasisPoint
+	}
+
+	notice := start.Sub(now)
+	refundBp, best := 0, -1
+	for _, tier := range policy.Tiers {
+		if tier.HoursBefore > best && notice >= time.Duration(tier.HoursBefore)*time.Hour {
+			refundBp, best = tier.RefundBp, tier.HoursBefore
+		}
+	}
+	return refundBp
+}
@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"booking/internal/domain"
+)
+
+// tier sengaja gak urut, yang dipakai tetap hours_before terbesar yang terpenuhi
+var testCancellationPolicy = &domain.CancellationPolicy{Tiers: []domain.CancellationTier{
+	{HoursBefore: 24, RefundBp: 5000},
+	{HoursBefore: 48, RefundBp: domain.BasisPoint},
+	{HoursBefore: 0, RefundBp: 2500},
+}}
+
+func TestRefundBpFor(t *testing.T) {
+	start := testMonday.AddDate(0, 0, 3).Add(12 * time.Hour)
+
+	tests := []struct {
+		name   string
+		policy *domain.CancellationPolicy
+		before time.Duration // now = start - before
+		want   int
+	}{
+		{name: "nil policy", policy: nil, before: time.Hour, want: domain.BasisPoint},
+		{name: "empty policy", policy: &domain.CancellationPolicy{}, before: time.Hour, want: domain.BasisPoint},
+		{name: "nil policy after start", policy: nil, before: -time.Minute, want: 0},
+		{name: "well before top tier", policy: testCancellationPolicy, before: 72 * time.Hour, want: domain.BasisPoint},
+		{name: "exactly at top tier", policy: testCancellationPolicy, before: 48 * time.Hour, want: domain.BasisPoint},
+		{name: "just past top tier", policy: testCancellationPolicy, before: 48*time.Hour - time.Second, want: 5000},
+		{name: "exactly at middle tier", policy: testCancellationPolicy, before: 24 * time.Hour, want: 5000},
+		{name: "just past middle tier", policy: testCancellationPolicy, before: 24*time.Hour - time.Second, want: 2500},
+		{name: "one second before start", policy: testCancellationPolicy, before: time.Second, want: 2500},
+		{name: "at start", policy: testCancellationPolicy, before: 0, want: 0},
+		{name: "start already passed", policy: testCancellationPolicy, before: -time.Hour, want: 0},
+		{
+			name:   "no tier satisfied",
+			policy: &domain.CancellationPolicy{Tiers: []domain.CancellationTier{{HoursBefore: 24, RefundBp: 5000}}},
+			before: time.Hour,
+			want:   0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := refundBpFor(tt.policy, start, start.Add(-tt.before)); got != tt.want {
+				t.Fatalf("refundBpFor() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeRefund(t *testing.T) {
+	start := testMonday.AddDate(0, 0, 3).Add(12 * time.Hour)
+	half := &domain.CancellationPolicy{Tiers: []domain.CancellationTier{{HoursBefore: 0, RefundBp: 5000}}}
+	third := &domain.CancellationPolicy{Tiers: []domain.CancellationTier{{HoursBefore: 0, RefundBp: 3333}}}
+
+	tests := []struct {
+		name   string
+		policy *domain.CancellationPolicy
+		paid   int64
+		now    time.Time
+		bp     int
+		amount int64
+	}{
+		{name: "nil policy refunds everything", policy: nil, paid: 12345, now: start.Add(-time.Hour), bp: domain.BasisPoint, amount: 12345},
+		{name: "zero paid", policy: testCancellationPolicy, paid: 0, now: start.Add(-72 * time.Hour), bp: domain.BasisPoint, amount: 0},
+		{name: "tier amount", policy: testCancellationPolicy, paid: 100000, now: start.Add(-30 * time.Hour), bp: 5000, amount: 50000},
+		{name: "rounds down below half", policy: testCancellationPolicy, paid: 333, now: start.Add(-time.Hour), bp: 2500, amount: 83}, // 83.25
+		{name: "half rounds up", policy: half, paid: 3, now: start.Add(-time.Hour), bp: 5000, amount: 2},                              // 1.5
+		{name: "odd basis point", policy: third, paid: 999, now: start.Add(-time.Hour), bp: 3333, amount: 333},                        // 332.97
+		{name: "start already passed", policy: nil, paid: 100000, now: start.Add(time.Minute), bp: 0, amount: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bp, amount := computeRefund(tt.policy, tt.paid, start, tt.now)
+			if bp != tt.bp || amount != tt.amount {
+				t.Fatalf("computeRefund() = (%d, %d), want (%d, %d)", bp, amount, tt.bp, tt.amount)
+			}
+			if amount > tt.paid {
+				t.Fatalf("refund %d is more than paid %d", amount, tt.paid)
+			}
+		})
+	}
+}
@@ -0,0 +1,338 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"time"
+
+	"booking/internal/domain"
+	"booking/pkg/logger"
+	uow "booking/pkg/unitOfWork"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type resourceUsecase struct {
+	resourceRepository domain.ResourceRepository
+	bookingRepository  domain.BookingRepository
+	holdRepository     domain.HoldRepository
+	waitlistRepository domain.WaitlistRepository
+	ratePlanRepository domain.RatePlanRepository
+	availabilityCache  domain.AvailabilityCache
+	uow                uow.UnitOfWork
+	log                logger.Logger
+}
+
+func NewResourceUsecase(
+	resourceRepository domain.ResourceRepository,
+	bookingRepository domain.BookingRepository,
+	holdRepository domain.HoldRepository,
+	waitlistRepository domain.WaitlistRepository,
+	ratePlanRepository domain.RatePlanRepository,
+	availabilityCache domain.AvailabilityCache,
+	uow uow.UnitOfWork,
+	log logger.Logger,
+) domain.ResourceUsecase {
+	return &resourceUsecase{
+		resourceRepository: resourceRepository,
+		bookingRepository:  bookingRepository,
+		holdRepository:     holdRepository,
+		waitlistRepository: waitlistRepository,
+		ratePlanRepository: ratePlanRepository,
+		availabilityCache:  availabilityCache,
+		uow:                uow,
+		log:                log,
+	}
+}
+
+func (u *resourceUsecase) Create(ctx context.Context, req *domain.CreateResourceDTO) (*domain.Resource, error) {
+	resourceID, err := uuid.NewV7()
+	if err != nil {
+		u.log.Error(err, "failed to generate uuidv7 for resource")
+		return nil, domain.ErrInternalServerError
+	}
+	req.ID = resourceID.String()
+
+	if req.Timezone == "" {
+		req.Timezone = "UTC"
+	}
+	if req.SlotIntervalMinutes == 0 {
+		req.SlotIntervalMinutes = int(defaultSlotInterval.Minutes())
+	}
+	if req.Capacity == 0 {
+		req.Capacity = 1
+	}
+	if req.WaitlistMode == "" {
+		req.WaitlistMode = domain.WaitlistModeOffer
+	}
+
+	for i := range req.Rules {
+		if req.Rules[i].StartTime >= req.Rules[i].EndTime {
+			return nil, domain.ErrInvalidRequest
+		}
+		ruleID, err := uuid.NewV7()
+		if err != nil {
+			u.log.Error(err, "failed to generate uuidv7 for availability rule")
+			return nil, domain.ErrInternalServerError
+		}
+		req.Rules[i].ID = ruleID.String()
+	}
+
+	var res *domain.Resource
+	err = u.uow.Do(ctx, func(tx *sqlx.Tx) error {
+		res, err = u.resourceRepository.Create(ctx, tx, req)
+		return err
+	})
+	if err != nil {
+		u.log.Error(err, "error creating resource")
+		return nil, domain.ErrInternalServerError
+	}
+
+	return res, nil
+}
+
+func (u *resourceUsecase) GetByID(ctx context.Context, id string) (*domain.Resource, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, domain.ErrResourceNotFound
+	}
+
+	res, err := u.resourceRepository.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrResourceNotFound
+		}
+		u.log.Error(err, "failed to get resource")
+		return nil, domain.ErrInternalServerError
+	}
+
+	res.Rules, err = u.resourceRepository.GetRules(ctx, id)
+	if err != nil {
+		u.log.Error(err, "failed to get availability rules")
+		return nil, domain.ErrInternalServerError
+	}
+
+	return res, nil
+}
+
+func (u *resourceUsecase) List(ctx context.Context, q *domain.PaginationQuery) ([]domain.Resource, error) {
+	limit, offset := normalizePagination(q.Limit, q.Offset)
+
+	res, err := u.resourceRepository.List(ctx, limit, offset)
+	if err != nil {
+		u.log.Error(err, "failed to list resources")
+		return nil, domain.ErrInternalServerError
+	}
+
+	return res, nil
+}
+
+func (u *resourceUsecase) AddException(ctx context.Context, userID string, req *domain.CreateAvailabilityExceptionDTO) (*domain.AvailabilityException, error) {
+	resource, err := u.GetByID(ctx, req.ResourceID)
+	if err != nil {
+		return nil, err
+	}
+	if !resource.ManagedBy(ctx, userID) {
+		return nil, domain.ErrForbiden
+	}
+	if req.StartTime != "" && req.StartTime >= req.EndTime {
+		return nil, domain.ErrInvalidRequest
+	}
+
+	exceptionID, err := uuid.NewV7()
+	if err != nil {
+		u.log.Error(err, "failed to generate uuidv7 for availability exception")
+		return nil, domain.ErrInternalServerError
+	}
+	req.ID = exceptionID.String()
+
+	res, err := u.resourceRepository.CreateException(ctx, req)
+	if err != nil {
+		u.log.Error(err, "error creating availability exception")
+		return nil, domain.ErrInternalServerError
+	}
+
+	if err := u.availabilityCache.Invalidate(ctx, resource.ID); err != nil {
+		u.log.Error(err, "failed to invalidate availability cache")
+	}
+
+	return res, nil
+}
+
+func (u *resourceUsecase) GetAvailability(ctx context.Context, resourceID string, q *domain.AvailabilityQuery) ([]domain.Slot, error) {
+	resource, err := u.GetByID(ctx, resourceID)
+	if err != nil {
+		return nil, err
+	}
+	if !resource.IsActive {
+		return nil, domain.ErrResourceNotFound
+	}
+
+	loc, err := time.LoadLocation(resource.Timezone)
+	if err != nil {
+		u.log.Error(err, "invalid resource timezone")
+		return nil, domain.ErrInternalServerError
+	}
+	from, err := time.ParseInLocation(time.DateOnly, q.From, loc)
+	if err != nil {
+		return nil, domain.ErrInvalidRequest
+	}
+	to, err := time.ParseInLocation(time.DateOnly, q.To, loc)
+	if err != nil {
+		return nil, domain.ErrInvalidRequest
+	}
+	if to.Before(from) || to.After(from.AddDate(0, 0, maxAvailabilityRange)) {
+		return nil, domain.ErrInvalidRequest
+	}
+	if q.PartySize == 0 {
+		q.PartySize = 1
+	}
+	if q.PartySize > resource.Capacity {
+		return nil, domain.ErrPartySizeTooLarge
+	}
+
+	if slots, ok, err := u.availabilityCache.Get(ctx, resource.ID, q); err != nil {
+		u.log.Error(err, "failed to get availability cache")
+	} else if ok {
+		return slots, nil
+	}
+
+	exceptions, err := u.resourceRepository.GetExceptions(ctx, resource.ID, q.From, q.To)
+	if err != nil {
+		u.log.Error(err, "failed to get availability exceptions")
+		return nil, domain.ErrInternalServerError
+	}
+
+	buffer := time.Duration(resource.BufferMinutes) * time.Minute
+	reservations, err := u.bookingRepository.ListActiveByResource(ctx, resource.ID, from.Add(-buffer), to.AddDate(0, 0, 1).Add(buffer))
+	if err != nil {
+		u.log.Error(err, "failed to list reservations of resource")
+		return nil, domain.ErrInternalServerError
+	}
+
+	// hold aktif (checkout yang belum selesai) juga dianggap busy. hold yang expired sendiri gak invalidate cache,
+	// jadi TTL cache dipotong sampai hold / tawaran / batas bayar paling awal lepas (occupancyExpiry)
+	holds, err := u.holdRepository.ListActiveByResource(ctx, resource.ID)
+	if err != nil {
+		u.log.Error(err, "failed to list active holds of resource")
+		return nil, domain.ErrInternalServerError
+	}
+	// tawaran waitlist yang belum expired juga menahan slot
+	offers, err := u.waitlistRepository.ListActiveOffers(ctx, resource.ID, from.Add(-buffer), to.AddDate(0, 0, 1).Add(buffer))
+	if err != nil {
+		u.log.Error(err, "failed to list active waitlist offers of resource")
+		return nil, domain.ErrInternalServerError
+	}
+	now := time.Now()
+	busy := toOccupancy(reservations, holds, offers, now)
+
+	duration := time.Duration(q.Duration) * time.Minute
+	slots := computeSlots(resource, resource.Rules, exceptions, busy, loc, from, to, duration, q.PartySize, now)
+
+	expiresAt := occupancyExpiry(reservations, holds, offers, now)
+	if err := u.availabilityCache.Set(ctx, resource.ID, q, slots, expiresAt); err != nil {
+		u.log.Error(err, "failed to set availability cache")
+	}
+
+	return slots, nil
+}
+
+func (u *resourceUsecase) SetRatePlan(ctx context.Context, userID string, req *domain.UpsertRatePlanDTO) (*domain.RatePlan, error) {
+	resource, err := u.GetByID(ctx, req.ResourceID)
+	if err != nil {
+		return nil, err
+	}
+	if !resource.ManagedBy(ctx, userID) {
+		return nil, domain.ErrForbiden
+	}
+
+	if req.WeekdayMultiplierBp == 0 {
+		req.WeekdayMultiplierBp = domain.BasisPoint
+	}
+	if req.WeekendMultiplierBp == 0 {
+		req.WeekendMultiplierBp = domain.BasisPoint
+	}
+
+	planID, err := uuid.NewV7()
+	if err != nil {
+		u.log.Error(err, "failed to generate uuidv7 for rate plan")
+		return nil, domain.ErrInternalServerError
+	}
+	req.ID = planID.String()
+
+	for i := range req.Peaks {
+		if req.Peaks[i].StartTime >= req.Peaks[i].EndTime {
+			return nil, domain.ErrInvalidRequest
+		}
+		peakID, err := uuid.NewV7()
+		if err != nil {
+			u.log.Error(err, "failed to generate uuidv7 for rate plan peak")
+			return nil, domain.ErrInternalServerError
+		}
+		req.Peaks[i].ID = peakID.String()
+	}
+
+	var res *domain.RatePlan
+	err = u.uow.Do(ctx, func(tx *sqlx.Tx) error {
+		res, err = u.ratePlanRepository.Upsert(ctx, tx, req)
+		return err
+	})
+	if err != nil {
+		u.log.Error(err, "error saving rate plan")
+		return nil, domain.ErrInternalServerError
+	}
+
+	return res, nil
+}
+
+func (u *resourceUsecase) GetRatePlan(ctx context.Context, resourceID string) (*domain.RatePlan, error) {
+	if _, err := uuid.Parse(resourceID); err != nil {
+		return nil, domain.ErrRatePlanNotFound
+	}
+
+	res, err := u.ratePlanRepository.GetByResource(ctx, resourceID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrRatePlanNotFound
+		}
+		u.log.Error(err, "failed to get rate plan")
+		return nil, domain.ErrInternalServerError
+	}
+
+	return res, nil
+}
+
+// SetCancellationPolicy cuma berlaku untuk booking baru, booking lama tetap pakai snapshot nya
+func (u *resourceUsecase) SetCancellationPolicy(ctx context.Context, userID string, req *domain.SetCancellationPolicyDTO) (*domain.Resource, error) {
+	resource, err := u.GetByID(ctx, req.ResourceID)
+	if err != nil {
+		return nil, err
+	}
+	if !resource.ManagedBy(ctx, userID) {
+		return nil, domain.ErrForbiden
+	}
+
+	var policy *domain.CancellationPolicy
+	if len(req.Tiers) > 0 {
+		tiers := slices.Clone(req.Tiers)
+		slices.SortFunc(tiers, func(a, b domain.CancellationTier) int {
+			return b.HoursBefore - a.HoursBefore
+		})
+		for i := 1; i < len(tiers); i++ {
+			if tiers[i].HoursBefore == tiers[i-1].HoursBefore {
+				return nil, domain.ErrInvalidRequest
+			}
+		}
+		policy = &domain.CancellationPolicy{Tiers: tiers}
+	}
+
+	res, err := u.resourceRepository.SetCancellationPolicy(ctx, resource.ID, policy)
+	if err != nil {
+		u.log.Error(err, "error saving cancellation policy")
+		return nil, domain.ErrInternalServerError
+	}
+
+	return res, nil
+}
@@ -0,0 +1,371 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"booking/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/teambition/rrule-go"
+)
+
+const (
+	maxSeriesOccurrences = 100
+	maxSeriesHorizonDays = 365 // rrule tanpa COUNT / UNTIL di-expand sampai 1 tahun
+)
+
+func (u *bookingUsecase) CreateSeries(ctx context.Context, req *domain.CreateSeriesDTO) (*domain.CreateSeriesResult, error) {
+	if err := validateBookingTime(req.StartTime, req.EndTime); err != nil {
+		return nil, err
+	}
+	duration := req.EndTime.Sub(req.StartTime)
+	if req.PartySize == 0 {
+		req.PartySize = 1
+	}
+
+	seriesID, err := uuid.NewV7()
+	if err != nil {
+		u.log.Error(err, "failed to generate uuidv7 for booking series")
+		return nil, domain.ErrInternalServerError
+	}
+	req.ID = seriesID.String()
+
+	var res *domain.CreateSeriesResult
+	err = u.uow.Do(ctx, func(tx *sqlx.Tx) error {
+		resource, err := u.lockResource(ctx, tx, req.ResourceID)
+		if err != nil {
+			return err
+		}
+		loc, err := time.LoadLocation(resource.Timezone)
+		if err != nil {
+			return err
+		}
+
+		rule, occurrences, err := expandRRule(req.RRule, req.StartTime, loc)
+		if err != nil {
+			return err
+		}
+
+		var notes *string
+		if req.Notes != "" {
+			notes = &req.Notes
+		}
+		series, err := u.bookingRepository.CreateSeries(ctx, tx, &domain.BookingSeries{
+			ID:              req.ID,
+			ResourceID:      resource.ID,
+			UserID:          req.UserID,
+			RRule:           rule,
+			StartTime:       req.StartTime,
+			DurationMinutes: int(duration.Minutes()),
+			Timezone:        resource.Timezone,
+			Notes:           notes,
+		})
+		if err != nil {
+			return err
+		}
+
+		conflicts := []domain.OccurrenceConflict{}
+		series.Occurrences = []domain.Reservation{}
+		for _, start := range occurrences {
+			end := start.Add(duration)
+			err := validateBookingTime(start, end)
+			if err == nil {
+				err = u.checkSlot(ctx, tx, resource, start, end, req.PartySize, "", nil)
+			}
+			if err != nil {
+				conflict, ok := toOccurrenceConflict(err, start, end, loc)
+				if !ok {
+					return err
+				}
+				conflicts = append(conflicts, *conflict)
+				continue
+			}
+
+			occurrenceID, err := uuid.NewV7()
+			if err != nil {
+				return err
+			}
+			occurrenceStart := start
+			price, err := u.quoteFor(ctx, resource, start, end, req.PartySize)
+			if err != nil {
+				return err
+			}
+			reservation, err := u.insertReservation(ctx, tx, &domain.CreateBookingDTO{
+				ID:              occurrenceID.String(),
+				UserID:          req.UserID,
+				ResourceID:      resource.ID,
+				StartTime:       start,
+				EndTime:         end,
+				PartySize:       req.PartySize,
+				Notes:           req.Notes,
+				SeriesID:        &series.ID,
+				OccurrenceStart: &occurrenceStart,
+				Price:           price,
+			}, domain.UserActor(req.UserID), "series occurrence")
+			if err != nil {
+				return err
+			}
+			series.Occurrences = append(series.Occurrences, *reservation)
+		}
+
+		// gak boleh gagal diam-diam: kalau ada yang bentrok dan client gak minta skip, semua dibatalkan
+		if len(series.Occurrences) == 0 || (len(conflicts) > 0 && !req.SkipConflicts) {
+			return &domain.SeriesConflictError{Conflicts: conflicts}
+		}
+
+		res = &domain.CreateSeriesResult{Series: series, Conflicts: conflicts}
+		return nil
+	})
+	if err != nil {
+		return nil, u.mapError(err, "error creating booking series")
+	}
+	u.invalidateAvailability(ctx, res.Series.ResourceID)
+	for i := range res.Series.Occurrences {
+		u.createPaymentIntents(ctx, &res.Series.Occurrences[i])
+	}
+
+	return res, nil
+}
+
+func (u *bookingUsecase) GetSeries(ctx context.Context, userID, id string) (*domain.BookingSeries, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, domain.ErrSeriesNotFound
+	}
+
+	res, err := u.bookingRepository.GetSeriesByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrSeriesNotFound
+		}
+		u.log.Error(err, "failed to get booking series")
+		return nil, domain.ErrInternalServerError
+	}
+	if res.UserID != userID {
+		return nil, domain.ErrSeriesNotFound
+	}
+
+	res.Occurrences, err = u.bookingRepository.ListBySeries(ctx, id)
+	if err != nil {
+		u.log.Error(err, "failed to list occurrences of booking series")
+		return nil, domain.ErrInternalServerError
+	}
+
+	return res, nil
+}
+
+// Move pindah jadwal reservasi. scope following: occurrence setelahnya ikut digeser dengan
+// selisih hari + jam yang sama (wall clock di timezone resource, aman saat pergantian DST)
+func (u *bookingUsecase) Move(ctx context.Context, userID, id string, req *domain.MoveBookingDTO) ([]domain.Reservation, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, domain.ErrBookingNotFound
+	}
+	if err := validateBookingTime(req.StartTime, req.EndTime); err != nil {
+		return nil, err
+	}
+
+	var res []domain.Reservation
+	err := u.uow.Do(ctx, func(tx *sqlx.Tx) error {
+		anchor, resource, err := u.lockOwnReservation(ctx, tx, userID, id)
+		if err != nil {
+			return err
+		}
+		if !isModifiable(anchor) {
+			return domain.ErrBookingNotModifiable
+		}
+		if !resource.IsActive {
+			return domain.ErrResourceNotFound
+		}
+		loc, err := time.LoadLocation(resource.Timezone)
+		if err != nil {
+			return err
+		}
+
+		targets := []domain.Reservation{*anchor}
+		if req.Scope == domain.ScopeFollowing && anchor.SeriesID != nil {
+			following, err := u.bookingRepository.ListFollowingForUpdate(ctx, tx, *anchor.SeriesID, occurrenceStartOf(anchor))
+			if err != nil {
+				return err
+			}
+			for i := range following {
+				if following[i].ID != anchor.ID && isModifiable(&following[i]) {
+					targets = append(targets, following[i])
+				}
+			}
+		}
+
+		excludeIDs := make([]string, 0, len(targets))
+		for _, t := range targets {
+			excludeIDs = append(excludeIDs, t.ID)
+		}
+
+		type move struct {
+			id         string
+			start, end time.Time
+		}
+		moves := make([]move, 0, len(targets))
+		conflicts := []domain.OccurrenceConflict{}
+		for _, t := range targets {
+			start, end := shiftOccurrence(anchor.StartTime, t.StartTime, req.StartTime, req.EndTime.Sub(req.StartTime), loc)
+			err := validateBookingTime(start, end)
+			if err == nil {
+				err = u.checkSlot(ctx, tx, resource, start, end, t.PartySize, "", excludeIDs)
+			}
+			if err != nil {
+				conflict, ok := toOccurrenceConflict(err, start, end, loc)
+				if !ok {
+					return err
+				}
+				conflicts = append(conflicts, *conflict)
+				continue
+			}
+			moves = append(moves, move{id: t.ID, start: start, end: end})
+		}
+		if len(conflicts) > 0 {
+			return &domain.SeriesConflictError{Conflicts: conflicts}
+		}
+
+		// exclusion constraint dicek per baris, jadi kalau digeser maju update dari yang paling akhir
+		// supaya gak bentrok dengan jadwal lama occurrence berikutnya
+		if req.StartTime.After(anchor.StartTime) {
+			for i, j := 0, len(moves)-1; i < j; i, j = i+1, j-1 {
+				moves[i], moves[j] = moves[j], moves[i]
+			}
+		}
+		for _, m := range moves {
+			reservation, err := u.bookingRepository.UpdateTime(ctx, tx, m.id, m.start, m.end)
+			if err != nil {
+				return err
+			}
+			res = append(res, *reservation)
+		}
+
+		// jadwal lama yang ditinggal bisa diisi antrian waitlist
+		for _, t := range targets {
+			if err := u.promoteWaitlist(ctx, tx, resource, t.StartTime, t.EndTime); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, u.mapError(err, "error moving booking")
+	}
+	u.invalidateAvailability(ctx, res[0].ResourceID)
+
+	return res, nil
+}
+
+// cancelFollowing batalkan occurrence setelah reservation, lalu potong rrule series
+// supaya berhenti sebelum jadwal asli reservation. return occurrence yang ikut dibatalkan
+func (u *bookingUsecase) cancelFollowing(ctx context.Context, tx *sqlx.Tx, reservation *domain.Reservation, reason string, actor domain.BookingActor) ([]domain.Reservation, error) {
+	from := occurrenceStartOf(reservation)
+	following, err := u.bookingRepository.ListFollowingForUpdate(ctx, tx, *reservation.SeriesID, from)
+	if err != nil {
+		return nil, err
+	}
+	cancelled := []domain.Reservation{}
+	for i := range following {
+		if !isModifiable(&following[i]) {
+			continue
+		}
+		res, err := transitionReservation(ctx, tx, u.bookingRepository, u.bookingEventRepository, following[i].ID, domain.ReservationStatusCancelled, reason, actor)
+		if err != nil {
+			return nil, err
+		}
+		cancelled = append(cancelled, *res)
+	}
+
+	series, err := u.bookingRepository.GetSeriesByID(ctx, *reservation.SeriesID)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := time.LoadLocation(series.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	opt, err := rrule.StrToROptionInLocation(series.RRule, loc)
+	if err != nil {
+		return nil, err
+	}
+	opt.Count = 0
+	opt.Until = from.Add(-time.Second)
+	if err := u.bookingRepository.UpdateSeriesRule(ctx, tx, series.ID, opt.RRuleString()); err != nil {
+		return nil, err
+	}
+	return cancelled, nil
+}
+
+// =============================
+// HELPERS
+// =============================
+
+// expandRRule parse rrule (tanpa DTSTART) lalu expand occurrence nya mulai dtstart di timezone resource.
+// return rrule yang sudah dinormalisasi + jam mulai tiap occurrence
+func expandRRule(rule string, dtstart time.Time, loc *time.Location) (string, []time.Time, error) {
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	opt, err := rrule.StrToROptionInLocation(rule, loc)
+	if err != nil {
+		return "", nil, domain.ErrInvalidRecurrence
+	}
+	opt.Dtstart = dtstart.In(loc)
+
+	r, err := rrule.NewRRule(*opt)
+	if err != nil {
+		return "", nil, domain.ErrInvalidRecurrence
+	}
+
+	occurrences := r.Between(opt.Dtstart, opt.Dtstart.AddDate(0, 0, maxSeriesHorizonDays), true)
+	if len(occurrences) == 0 || len(occurrences) > maxSeriesOccurrences {
+		return "", nil, domain.ErrInvalidRecurrence
+	}
+
+	return opt.RRuleString(), occurrences, nil
+}
+
+// shiftOccurrence geser occurrence dengan selisih hari antara anchor lama dan jadwal baru,
+// jam mulai ikut jadwal baru
+func shiftOccurrence(anchorStart, occurrenceStart, newStart time.Time, duration time.Duration, loc *time.Location) (time.Time, time.Time) {
+	anchorStart = anchorStart.In(loc)
+	newStart = newStart.In(loc)
+	occurrenceStart = occurrenceStart.In(loc)
+
+	dayDelta := daysBetween(anchorStart, newStart)
+	y, m, d := occurrenceStart.Date()
+	start := time.Date(y, m, d+dayDelta, newStart.Hour(), newStart.Minute(), 0, 0, loc)
+	return start, start.Add(duration)
+}
+
+func daysBetween(a, b time.Time) int {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	da := time.Date(ay, am, ad, 0, 0, 0, 0, time.UTC)
+	db := time.Date(by, bm, bd, 0, 0, 0, 0, time.UTC)
+	return int(db.Sub(da).Hours() / 24)
+}
+
+func occurrenceStartOf(r *domain.Reservation) time.Time {
+	if r.OccurrenceStart != nil {
+		return *r.OccurrenceStart
+	}
+	return r.StartTime
+}
+
+// toOccurrenceConflict ubah error cek slot jadi conflict per tanggal, false kalau error lain (db, redis)
+func toOccurrenceConflict(err error, start, end time.Time, loc *time.Location) (*domain.OccurrenceConflict, bool) {
+	if !errors.Is(err, domain.ErrSlotUnavailable) &&
+		!errors.Is(err, domain.ErrOutsideOpeningHours) &&
+		!errors.Is(err, domain.ErrInvalidBookingTime) {
+		return nil, false
+	}
+	return &domain.OccurrenceConflict{
+		Date:      start.In(loc).Format(time.DateOnly),
+		StartTime: start,
+		EndTime:   end,
+		Reason:    err.Error(),
+	}, true
+}
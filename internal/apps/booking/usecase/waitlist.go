@@ -0,0 +1,383 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"booking/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// Flow waitlist:
+// 1. JoinWaitlist → cuma bisa kalau slot nya memang penuh, antrian urut berdasarkan waktu join.
+// 2. Reservasi dibatalkan / dipindah → promoteWaitlist di transaksi yang sama dengan pembatalan.
+//    mode auto: user pertama yang slot nya muat langsung dibuatkan reservasi.
+//    mode offer: user pertama dapat tawaran selama APP_WAITLIST_OFFER_TTL, slot ditahan untuk dia.
+// 3. AcceptWaitlistOffer → tawaran jadi reservasi. Kalau tawaran sudah expired / ditolak (leave),
+//    antrian berikutnya yang dapat giliran.
+// 4. Tawaran yang dibiarkan sampai expired diambil ExpireWaitlistOffers (job berkala, APP_WAITLIST_SWEEP_INTERVAL),
+//    ditandai expired (di-commit sendiri, promote_after diisi) lalu antrian berikutnya dipromosikan di transaksi terpisah.
+//    Promosi yang gagal dicoba lagi setelah promotionRetryDelay. Sebelum sweep jalan, tawaran expired sudah gak menahan slot.
+
+const (
+	lapsedOfferBatch    = 100             // jumlah tawaran expired / promosi yang diproses per sweep, sisanya di sweep berikutnya
+	promotionRetryDelay = 1 * time.Minute // promosi yang gagal pindah ke belakang antrian sweep
+)
+
+func (u *bookingUsecase) JoinWaitlist(ctx context.Context, req *domain.JoinWaitlistDTO) (*domain.WaitlistEntry, error) {
+	if err := validateBookingTime(req.StartTime, req.EndTime); err != nil {
+		return nil, err
+	}
+	if req.PartySize == 0 {
+		req.PartySize = 1
+	}
+
+	entryID, err := uuid.NewV7()
+	if err != nil {
+		u.log.Error(err, "failed to generate uuidv7 for waitlist entry")
+		return nil, domain.ErrInternalServerError
+	}
+	req.ID = entryID.String()
+
+	var res *domain.WaitlistEntry
+	err = u.uow.Do(ctx, func(tx *sqlx.Tx) error {
+		resource, err := u.lockResource(ctx, tx, req.ResourceID)
+		if err != nil {
+			return err
+		}
+
+		err = u.checkSlot(ctx, tx, resource, req.StartTime, req.EndTime, req.PartySize, "", nil)
+		if err == nil {
+			return domain.ErrSlotStillAvailable
+		}
+		if !errors.Is(err, domain.ErrSlotUnavailable) {
+			return err
+		}
+
+		res, err = u.waitlistRepository.Create(ctx, tx, req)
+		return err
+	})
+	if err != nil {
+		return nil, u.mapError(err, "error joining waitlist")
+	}
+
+	return res, nil
+}
+
+func (u *bookingUsecase) ListWaitlist(ctx context.Context, userID string, q *domain.PaginationQuery) ([]domain.WaitlistEntry, error) {
+	limit, offset := normalizePagination(q.Limit, q.Offset)
+
+	res, err := u.waitlistRepository.ListByUser(ctx, userID, limit, offset)
+	if err != nil {
+		u.log.Error(err, "failed to list user waitlist")
+		return nil, domain.ErrInternalServerError
+	}
+
+	return res, nil
+}
+
+func (u *bookingUsecase) LeaveWaitlist(ctx context.Context, userID, id string) error {
+	var resourceID string
+	err := u.uow.Do(ctx, func(tx *sqlx.Tx) error {
+		entry, resource, err := u.lockOwnWaitlistEntry(ctx, tx, userID, id)
+		if err != nil {
+			return err
+		}
+		if entry.Status != domain.WaitlistStatusWaiting && entry.Status != domain.WaitlistStatusOffered {
+			return domain.ErrWaitlistNotFound
+		}
+
+		if _, err := u.waitlistRepository.UpdateStatus(ctx, tx, entry.ID, domain.WaitlistStatusLeft, nil, nil); err != nil {
+			return err
+		}
+
+		// tawaran ditolak, giliran antrian berikutnya
+		if entry.Status == domain.WaitlistStatusOffered {
+			resourceID = resource.ID
+			return u.promoteWaitlist(ctx, tx, resource, entry.StartTime, entry.EndTime)
+		}
+		return nil
+	})
+	if err != nil {
+		return u.mapError(err, "error leaving waitlist")
+	}
+	if resourceID != "" {
+		u.invalidateAvailability(ctx, resourceID)
+	}
+
+	return nil
+}
+
+func (u *bookingUsecase) AcceptWaitlistOffer(ctx context.Context, userID, id string) (*domain.Reservation, error) {
+	reservationID, err := uuid.NewV7()
+	if err != nil {
+		u.log.Error(err, "failed to generate uuidv7 for booking")
+		return nil, domain.ErrInternalServerError
+	}
+
+	var (
+		res        *domain.Reservation
+		resourceID string
+		expired    bool
+	)
+	err = u.uow.Do(ctx, func(tx *sqlx.Tx) error {
+		entry, resource, err := u.lockOwnWaitlistEntry(ctx, tx, userID, id)
+		if err != nil {
+			return err
+		}
+		if entry.Status != domain.WaitlistStatusOffered {
+			return domain.ErrWaitlistNotFound
+		}
+		resourceID = resource.ID
+
+		// expired ditandai & antrian berikutnya dipromosikan, transaksi tetap di-commit
+		if entry.OfferExpiresAt == nil || !entry.OfferExpiresAt.After(time.Now()) {
+			expired = true
+			if _, err := u.waitlistRepository.UpdateStatus(ctx, tx, entry.ID, domain.WaitlistStatusExpired, nil, nil); err != nil {
+				return err
+			}
+			return u.promoteWaitlist(ctx, tx, resource, entry.StartTime, entry.EndTime)
+		}
+
+		if !resource.IsActive {
+			return domain.ErrResourceNotFound
+		}
+		if err := u.checkSlot(ctx, tx, resource, entry.StartTime, entry.EndTime, entry.PartySize, entry.ID, nil); err != nil {
+			return err
+		}
+		price, err := u.quoteFor(ctx, resource, entry.StartTime, entry.EndTime, entry.PartySize)
+		if err != nil {
+			return err
+		}
+
+		res, err = u.insertReservation(ctx, tx, &domain.CreateBookingDTO{
+			ID:         reservationID.String(),
+			UserID:     entry.UserID,
+			ResourceID: entry.ResourceID,
+			StartTime:  entry.StartTime,
+			EndTime:    entry.EndTime,
+			PartySize:  entry.PartySize,
+			Notes:      domain.NilStringHandler(entry.Notes),
+			Price:      price,
+		}, domain.UserActor(userID), "waitlist offer accepted")
+		if err != nil {
+			return err
+		}
+		_, err = u.waitlistRepository.UpdateStatus(ctx, tx, entry.ID, domain.WaitlistStatusPromoted, nil, &res.ID)
+		return err
+	})
+	if err != nil {
+		return nil, u.mapError(err, "error accepting waitlist offer")
+	}
+	u.invalidateAvailability(ctx, resourceID)
+	if expired {
+		return nil, domain.ErrWaitlistOfferExpired
+	}
+	u.createPaymentIntents(ctx, res)
+
+	return res, nil
+}
+
+func (u *bookingUsecase) ExpireWaitlistOffers(ctx context.Context) error {
+	entries, err := u.waitlistRepository.ListLapsedOffers(ctx, lapsedOfferBatch)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		// satu tawaran gagal gak menghentikan yang lain, dicoba lagi di sweep berikutnya
+		if err := u.expireOffer(ctx, &entry); err != nil {
+			u.log.Errorf(err, "failed to expire waitlist offer %s", entry.ID)
+		}
+	}
+
+	// termasuk yang barusan di-expire di atas
+	pending, err := u.waitlistRepository.ListPendingPromotions(ctx, lapsedOfferBatch)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range pending {
+		if err := u.promoteAfterExpiry(ctx, &entry); err != nil {
+			u.log.Errorf(err, "failed to promote waitlist after expired offer %s", entry.ID)
+		}
+	}
+	return nil
+}
+
+// expireOffer sama seperti AcceptWaitlistOffer yang telat: lock resource dulu, cek ulang status entry
+// (bisa saja sudah di-accept / leave di antara list dan lock), baru expired. promosi nya terpisah (promoteAfterExpiry)
+// supaya promosi yang gagal gak ikut membatalkan expired nya
+func (u *bookingUsecase) expireOffer(ctx context.Context, lapsed *domain.WaitlistEntry) error {
+	ctx = domain.WithOrg(ctx, &domain.OrgContext{OrgID: lapsed.OrgID})
+
+	err := u.uow.Do(ctx, func(tx *sqlx.Tx) error {
+		// urutan lock tetap resource -> entry, sama dengan accept / promosi
+		if _, err := u.resourceRepository.GetByIDForUpdate(ctx, tx, lapsed.ResourceID); err != nil {
+			return err
+		}
+		entry, err := u.waitlistRepository.GetByIDForUpdate(ctx, tx, lapsed.ID)
+		if err != nil {
+			return err
+		}
+		if entry.Status != domain.WaitlistStatusOffered || entry.OfferExpiresAt == nil || entry.OfferExpiresAt.After(time.Now()) {
+			return nil
+		}
+
+		if _, err := u.waitlistRepository.UpdateStatus(ctx, tx, entry.ID, domain.WaitlistStatusExpired, nil, nil); err != nil {
+			return err
+		}
+		now := time.Now()
+		return u.waitlistRepository.SetPromoteAfter(ctx, tx, entry.ID, &now)
+	})
+	if err != nil {
+		return err
+	}
+	u.invalidateAvailability(ctx, lapsed.ResourceID)
+
+	return nil
+}
+
+// promoteAfterExpiry promosikan antrian di slot tawaran yang sudah expired. gagal = promote_after dimundurkan,
+// jadi promosi yang terus gagal gak memenuhi batch sweep berikutnya
+func (u *bookingUsecase) promoteAfterExpiry(ctx context.Context, expired *domain.WaitlistEntry) error {
+	ctx = domain.WithOrg(ctx, &domain.OrgContext{OrgID: expired.OrgID})
+
+	err := u.uow.Do(ctx, func(tx *sqlx.Tx) error {
+		resource, err := u.resourceRepository.GetByIDForUpdate(ctx, tx, expired.ResourceID)
+		if err != nil {
+			return err
+		}
+		entry, err := u.waitlistRepository.GetByIDForUpdate(ctx, tx, expired.ID)
+		if err != nil {
+			return err
+		}
+		// sudah dipromosikan sweep lain
+		if entry.PromoteAfter == nil {
+			return nil
+		}
+
+		if err := u.promoteWaitlist(ctx, tx, resource, entry.StartTime, entry.EndTime); err != nil {
+			return err
+		}
+		return u.waitlistRepository.SetPromoteAfter(ctx, tx, entry.ID, nil)
+	})
+	if err != nil {
+		retryAt := time.Now().Add(promotionRetryDelay)
+		deferErr := u.uow.Do(ctx, func(tx *sqlx.Tx) error {
+			return u.waitlistRepository.SetPromoteAfter(ctx, tx, expired.ID, &retryAt)
+		})
+		if deferErr != nil {
+			u.log.Errorf(deferErr, "failed to defer waitlist promotion %s", expired.ID)
+		}
+		return err
+	}
+	u.invalidateAvailability(ctx, expired.ResourceID)
+
+	return nil
+}
+
+// promoteWaitlist dipanggil di dalam transaksi setelah slot [start, end) kosong.
+// antrian dicek urut dari yang paling dulu join, yang slot nya sudah muat dipromosikan
+// (antrian dengan jam berbeda tapi gak saling bentrok bisa dipromosikan sekaligus)
+func (u *bookingUsecase) promoteWaitlist(ctx context.Context, tx *sqlx.Tx, resource *domain.Resource, start, end time.Time) error {
+	if !resource.IsActive {
+		return nil
+	}
+
+	buffer := time.Duration(resource.BufferMinutes) * time.Minute
+	entries, err := u.waitlistRepository.ListWaitingForUpdate(ctx, tx, resource.ID, start.Add(-buffer), end.Add(buffer))
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if !entry.StartTime.After(time.Now()) {
+			if _, err := u.waitlistRepository.UpdateStatus(ctx, tx, entry.ID, domain.WaitlistStatusExpired, nil, nil); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := u.checkSlot(ctx, tx, resource, entry.StartTime, entry.EndTime, entry.PartySize, "", nil); err != nil {
+			if errors.Is(err, domain.ErrSlotUnavailable) || errors.Is(err, domain.ErrOutsideOpeningHours) {
+				continue
+			}
+			return err
+		}
+
+		// intent pembayaran promosi otomatis dibuat ReconcilePayments, user nya gak sedang menunggu respon
+		if resource.WaitlistMode == domain.WaitlistModeAuto {
+			reservationID, err := uuid.NewV7()
+			if err != nil {
+				return err
+			}
+			price, err := u.quoteFor(ctx, resource, entry.StartTime, entry.EndTime, entry.PartySize)
+			if err != nil {
+				return err
+			}
+			reservation, err := u.insertReservation(ctx, tx, &domain.CreateBookingDTO{
+				ID:         reservationID.String(),
+				UserID:     entry.UserID,
+				ResourceID: entry.ResourceID,
+				StartTime:  entry.StartTime,
+				EndTime:    entry.EndTime,
+				PartySize:  entry.PartySize,
+				Notes:      domain.NilStringHandler(entry.Notes),
+				Price:      price,
+			}, domain.SystemActor, "waitlist auto promotion")
+			if err != nil {
+				return err
+			}
+			if _, err := u.waitlistRepository.UpdateStatus(ctx, tx, entry.ID, domain.WaitlistStatusPromoted, nil, &reservation.ID); err != nil {
+				return err
+			}
+			u.log.Infof("waitlist entry %s promoted to reservation %s", entry.ID, reservation.ID)
+			continue
+		}
+
+		// tawaran gak boleh lewat jam mulai slot nya
+		expiresAt := time.Now().Add(u.config.App.WaitlistOfferTtl)
+		if expiresAt.After(entry.StartTime) {
+			expiresAt = entry.StartTime
+		}
+		if _, err := u.waitlistRepository.UpdateStatus(ctx, tx, entry.ID, domain.WaitlistStatusOffered, &expiresAt, nil); err != nil {
+			return err
+		}
+		u.log.Infof("waitlist entry %s offered until %s", entry.ID, expiresAt.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// lockOwnWaitlistEntry urutan lock sama dengan lockOwnReservation: resource dulu baru entry waitlist
+func (u *bookingUsecase) lockOwnWaitlistEntry(ctx context.Context, tx *sqlx.Tx, userID, id string) (*domain.WaitlistEntry, *domain.Resource, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, nil, domain.ErrWaitlistNotFound
+	}
+
+	entry, err := u.waitlistRepository.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, domain.ErrWaitlistNotFound
+		}
+		return nil, nil, err
+	}
+	if entry.UserID != userID {
+		return nil, nil, domain.ErrWaitlistNotFound
+	}
+
+	resource, err := u.resourceRepository.GetByIDForUpdate(ctx, tx, entry.ResourceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	entry, err = u.waitlistRepository.GetByIDForUpdate(ctx, tx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return entry, resource, nil
+}
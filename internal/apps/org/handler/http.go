@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"booking/internal/domain"
+	"booking/internal/server/middleware"
+	"booking/pkg/logger"
+	"booking/pkg/utils"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type orgHandler struct {
+	orgUsecase domain.OrgUsecase
+	mw         *middleware.Middleware
+	log        logger.Logger
+}
+
+func NewOrgHandler(orgUsecase domain.OrgUsecase, mw *middleware.Middleware, log logger.Logger) *orgHandler {
+	return &orgHandler{orgUsecase: orgUsecase, mw: mw, log: log}
+}
+
+// RegisterRoutes - /orgs. middleware dipasang per route karena /orgs/:org/... juga dipakai route booking
+func (h *orgHandler) RegisterRoutes(r fiber.Router) {
+	// tanpa org aktif, jadi yang dicek role global (admin platform)
+	r.Post("/", h.mw.Auth(), h.mw.RequirePermission(domain.PermOrgManage), h.create)
+	r.Get("/", h.mw.Auth(), h.listMine)
+	r.Post("/:org/switch", h.mw.Auth(), h.switchOrg)
+
+	// admin org
+	manage := h.mw.RequirePermission(domain.PermOrgManage)
+	r.Get("/:org/members", h.mw.Auth(), h.mw.ResolveOrg(), manage, h.listMembers)
+	r.Put("/:org/members/:userId", h.mw.Auth(), h.mw.ResolveOrg(), manage, h.upsertMember)
+	r.Delete("/:org/members/:userId", h.mw.Auth(), h.mw.ResolveOrg(), manage, h.removeMember)
+}
+
+func (h *orgHandler) create(c fiber.Ctx) error {
+	var req domain.CreateOrgDTO
+	if err := c.Bind().Body(&req); err != nil {
+		return err
+	}
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+	req.CreatedBy = session.UserID
+
+	res, err := h.orgUsecase.Create(c.RequestCtx(), &req)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.Status(fiber.StatusCreated).JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
+
+func (h *orgHandler) listMine(c fiber.Ctx) error {
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+
+	res, err := h.orgUsecase.ListMine(c.RequestCtx(), session.UserID)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
+
+// switchOrg ganti org aktif login yang sedang dipakai, dipakai kalau request gak bawa org di path / header / subdomain
+func (h *orgHandler) switchOrg(c fiber.Ctx) error {
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+
+	req := domain.SwitchOrgDTO{
+		Org:    c.Params("org"),
+		UserID: session.UserID,
+	}
+	req.SessionToken, _ = c.Locals(domain.SessionTokenCtxKey).(string)
+	req.FamilyID, _ = c.Locals(domain.TokenFamilyCtxKey).(string)
+
+	res, err := h.orgUsecase.Switch(c.RequestCtx(), &req)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
+
+func (h *orgHandler) listMembers(c fiber.Ctx) error {
+	org := c.Locals(domain.OrgCtxKey).(*domain.OrgContext)
+
+	res, err := h.orgUsecase.ListMembers(c.RequestCtx(), org.OrgID)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
+
+func (h *orgHandler) upsertMember(c fiber.Ctx) error {
+	var req domain.UpsertOrgMemberDTO
+	if err := c.Bind().Body(&req); err != nil {
+		return err
+	}
+	req.OrgID = c.Locals(domain.OrgCtxKey).(*domain.OrgContext).OrgID
+	req.UserID = c.Params("userId")
+
+	res, err := h.orgUsecase.UpsertMember(c.RequestCtx(), &req)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
+
+func (h *orgHandler) removeMember(c fiber.Ctx) error {
+	org := c.Locals(domain.OrgCtxKey).(*domain.OrgContext)
+
+	if err := h.orgUsecase.RemoveMember(c.RequestCtx(), org.OrgID, c.Params("userId")); err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+	})
+}
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+
+	"booking/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type orgRepository struct {
+	DB *sqlx.DB
+}
+
+func NewOrgRepository(db *sqlx.DB) domain.OrgRepository {
+	return &orgRepository{
+		DB: db,
+	}
+}
+
+const orgColumns = `id, slug, name, created_at, updated_at`
+
+// Create org baru sekaligus jadikan adminID admin org nya
+func (r *orgRepository) Create(ctx context.Context, org *domain.Organization, adminID string) (*domain.Organization, error) {
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var res domain.Organization
+	query := `
+		INSERT INTO organizations (id, slug, name)
+			VALUES ($1, $2, $3)
+		RETURNING ` + orgColumns
+	if err := tx.QueryRowxContext(ctx, query, org.ID, org.Slug, org.Name).StructScan(&res); err != nil {
+		return nil, err
+	}
+
+	memberQuery := `INSERT INTO organization_members (org_id, user_id, role) VALUES ($1, $2, $3)`
+	if _, err := tx.ExecContext(ctx, memberQuery, res.ID, adminID, domain.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (r *orgRepository) GetByRef(ctx context.Context, ref string) (*domain.Organization, error) {
+	var res domain.Organization
+
+	query := `SELECT ` + orgColumns + ` FROM organizations `
+	var err error
+	if _, parseErr := uuid.Parse(ref); parseErr == nil {
+		err = r.DB.GetContext(ctx, &res, query+`WHERE id = $1`, ref)
+	} else {
+		err = r.DB.GetContext(ctx, &res, query+`WHERE slug = $1`, ref)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+const memberColumns = `m.org_id, m.user_id, u.name, m.role, m.created_at, m.updated_at`
+
+func (r *orgRepository) GetMember(ctx context.Context, orgID, userID string) (*domain.OrgMember, error) {
+	var res domain.OrgMember
+
+	query := `
+		SELECT ` + memberColumns + `
+		FROM organization_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.org_id = $1 AND m.user_id = $2
+	`
+	if err := r.DB.GetContext(ctx, &res, query, orgID, userID); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (r *orgRepository) ListByUser(ctx context.Context, userID string) ([]domain.OrgMembership, error) {
+	res := []domain.OrgMembership{}
+
+	query := `
+		SELECT o.id, o.slug, o.name, o.created_at, o.updated_at, m.role
+		FROM organization_members m
+		JOIN organizations o ON o.id = m.org_id
+		WHERE m.user_id = $1
+		ORDER BY o.name
+	`
+	if err := r.DB.SelectContext(ctx, &res, query, userID); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (r *orgRepository) ListMembers(ctx context.Context, orgID string) ([]domain.OrgMember, error) {
+	res := []domain.OrgMember{}
+
+	query := `
+		SELECT ` + memberColumns + `
+		FROM organization_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.org_id = $1
+		ORDER BY m.created_at
+	`
+	if err := r.DB.SelectContext(ctx, &res, query, orgID); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// UpsertMember tambah member atau ganti role nya. admin terakhir gak boleh diturunkan
+func (r *orgRepository) UpsertMember(ctx context.Context, orgID, userID, role string) (*domain.OrgMember, error) {
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if role != domain.RoleAdmin {
+		if err := r.ensureOtherAdmin(ctx, tx, orgID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	query := `
+		INSERT INTO organization_members (org_id, user_id, role)
+			VALUES ($1, $2, $3)
+		ON CONFLICT (org_id, user_id) DO UPDATE
+			SET role = EXCLUDED.role,
+				updated_at = now()
+	`
+	if _, err := tx.ExecContext(ctx, query, orgID, userID, role); err != nil {
+		return nil, err
+	}
+
+	var res domain.OrgMember
+	selectQuery := `
+		SELECT ` + memberColumns + `
+		FROM organization_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.org_id = $1 AND m.user_id = $2
+	`
+	if err := tx.GetContext(ctx, &res, selectQuery, orgID, userID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (r *orgRepository) DeleteMember(ctx context.Context, orgID, userID string) error {
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := r.ensureOtherAdmin(ctx, tx, orgID, userID); err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM organization_members WHERE org_id = $1 AND user_id = $2`, orgID, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return tx.Commit()
+}
+
+// ensureOtherAdmin lock semua admin org, supaya 2 request yang saling menurunkan admin gak bikin org tanpa admin
+func (r *orgRepository) ensureOtherAdmin(ctx context.Context, tx *sqlx.Tx, orgID, userID string) error {
+	var admins []string
+	query := `SELECT user_id FROM organization_members WHERE org_id = $1 AND role = $2 FOR UPDATE`
+	if err := tx.SelectContext(ctx, &admins, query, orgID, domain.RoleAdmin); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if len(admins) == 1 && slices.Contains(admins, userID) {
+		return domain.ErrLastOrgAdmin
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"booking/internal/domain"
+	"booking/pkg/constant"
+	"booking/pkg/logger"
+	"booking/pkg/security"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type orgUsecase struct {
+	orgRepository  domain.OrgRepository
+	userRepository domain.UserRepository
+	security       *security.Security
+	log            logger.Logger
+}
+
+func NewOrgUsecase(orgRepository domain.OrgRepository, userRepository domain.UserRepository, security *security.Security, log logger.Logger) domain.OrgUsecase {
+	return &orgUsecase{
+		orgRepository:  orgRepository,
+		userRepository: userRepository,
+		security:       security,
+		log:            log,
+	}
+}
+
+func (u *orgUsecase) Create(ctx context.Context, req *domain.CreateOrgDTO) (*domain.Organization, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		u.log.Error(err, "failed to generate uuidv7 for organization")
+		return nil, domain.ErrInternalServerError
+	}
+
+	res, err := u.orgRepository.Create(ctx, &domain.Organization{
+		ID:   id.String(),
+		Slug: req.Slug,
+		Name: req.Name,
+	}, req.CreatedBy)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == constant.PgErrUniqueViolation {
+			return nil, domain.ErrOrgSlugExists
+		}
+		u.log.Error(err, "failed to create organization")
+		return nil, domain.ErrInternalServerError
+	}
+
+	return res, nil
+}
+
+func (u *orgUsecase) ListMine(ctx context.Context, userID string) ([]domain.OrgMembership, error) {
+	res, err := u.orgRepository.ListByUser(ctx, userID)
+	if err != nil {
+		u.log.Error(err, "failed to list user organizations")
+		return nil, domain.ErrInternalServerError
+	}
+	return res, nil
+}
+
+func (u *orgUsecase) ListMembers(ctx context.Context, orgID string) ([]domain.OrgMember, error) {
+	res, err := u.orgRepository.ListMembers(ctx, orgID)
+	if err != nil {
+		u.log.Error(err, "failed to list organization members")
+		return nil, domain.ErrInternalServerError
+	}
+	return res, nil
+}
+
+func (u *orgUsecase) UpsertMember(ctx context.Context, req *domain.UpsertOrgMemberDTO) (*domain.OrgMember, error) {
+	res, err := u.orgRepository.UpsertMember(ctx, req.OrgID, req.UserID, req.Role)
+	if err != nil {
+		if errors.Is(err, domain.ErrLastOrgAdmin) {
+			return nil, err
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == constant.PgErrForeignKeyViolation {
+			return nil, domain.ErrUserNotFound
+		}
+		u.log.Error(err, "failed to upsert organization member")
+		return nil, domain.ErrInternalServerError
+	}
+	return res, nil
+}
+
+func (u *orgUsecase) RemoveMember(ctx context.Context, orgID, userID string) error {
+	if err := u.orgRepository.DeleteMember(ctx, orgID, userID); err != nil {
+		if errors.Is(err, domain.ErrLastOrgAdmin) {
+			return err
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrOrgMemberNotFound
+		}
+		u.log.Error(err, "failed to delete organization member")
+		return domain.ErrInternalServerError
+	}
+	return nil
+}
+
+// Switch ganti org aktif login yang sedang dipakai. org yang bukan tempat user jadi member tetap boleh
+// dipilih (pelanggan booking di org tsb), role nya user biasa
+func (u *orgUsecase) Switch(ctx context.Context, req *domain.SwitchOrgDTO) (*domain.SwitchOrgResult, error) {
+	org, err := u.orgRepository.GetByRef(ctx, req.Org)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrOrgNotFound
+		}
+		u.log.Error(err, "failed to get organization")
+		return nil, domain.ErrInternalServerError
+	}
+
+	session, ok := domain.SessionFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthorized
+	}
+
+	member, err := u.orgRepository.GetMember(ctx, org.ID, req.UserID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		u.log.Error(err, "failed to get organization member")
+		return nil, domain.ErrInternalServerError
+	}
+	res := &domain.SwitchOrgResult{
+		Org: &domain.OrgMembership{
+			Organization: *org,
+			Role:         domain.EffectiveOrgRole(session.Role, member),
+		},
+	}
+
+	// web: org disimpan di session redis
+	if req.SessionToken != "" {
+		if err := u.security.SetSessionOrg(ctx, req.SessionToken, org.ID); err != nil {
+			if errors.Is(err, domain.ErrUnauthorized) {
+				return nil, err
+			}
+			u.log.Error(err, "failed to set session organization")
+			return nil, domain.ErrInternalServerError
+		}
+		return res, nil
+	}
+
+	// mobile: org disimpan per refresh token family, access token lama masih bawa org lama sampai expired
+	if err := u.security.SetFamilyOrg(ctx, req.FamilyID, org.ID); err != nil {
+		if errors.Is(err, domain.ErrInvalidToken) {
+			return nil, err
+		}
+		u.log.Error(err, "failed to set refresh token family organization")
+		return nil, domain.ErrInternalServerError
+	}
+
+	user, err := u.userRepository.GetByProviderID(ctx, session.Provider, session.ProviderID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrInvalidToken
+		}
+		u.log.Error(err, "failed to get user by provider id")
+		return nil, domain.ErrInternalServerError
+	}
+
+	res.AccessToken, err = u.security.GenerateAccessToken(user, session.Device, req.FamilyID, org.ID)
+	if err != nil {
+		u.log.Error(err, "failed to generate access token")
+		return nil, domain.ErrInternalServerError
+	}
+
+	return res, nil
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"booking/internal/domain"
+	"booking/pkg/utils"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// RegisterAdminRoutes - /admin/users
+func (h *userHandler) RegisterAdminRoutes(r fiber.Router) {
+	r.Use(h.middleware.Auth())
+	r.Get("/", h.middleware.RequirePermission(domain.PermUserRead), h.listUsers)
+	r.Get("/:id", h.middleware.RequirePermission(domain.PermUserRead), h.getUserDetail)
+	r.Put("/:id/role", h.middleware.RequirePermission(domain.PermUserManage), h.changeRole)
+	r.Post("/:id/disable", h.middleware.RequirePermission(domain.PermUserManage), h.disableUser)
+	r.Post("/:id/enable", h.middleware.RequirePermission(domain.PermUserManage), h.enableUser)
+	r.Post("/:id/logout", h.middleware.RequirePermission(domain.PermUserManage), h.forceLogout)
+}
+
+// listUsers e.g: /admin/users?search=budi&role=staff&verified=true&created_from=2025-01-01&limit=20
+func (h *userHandler) listUsers(c fiber.Ctx) error {
+	var q domain.AdminUserQuery
+	if err := c.Bind().Query(&q); err != nil {
+		return err
+	}
+
+	res, err := h.UseCase.ListUsers(c.RequestCtx(), &q)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
+
+func (h *userHandler) getUserDetail(c fiber.Ctx) error {
+	res, err := h.UseCase.GetUserDetail(c.RequestCtx(), c.Params("id"))
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
+
+func (h *userHandler) disableUser(c fiber.Ctx) error {
+	return h.setUserDisabled(c, true)
+}
+
+func (h *userHandler) enableUser(c fiber.Ctx) error {
+	return h.setUserDisabled(c, false)
+}
+
+func (h *userHandler) setUserDisabled(c fiber.Ctx, disabled bool) error {
+	res, err := h.UseCase.SetUserDisabled(c.RequestCtx(), c.Params("id"), disabled)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
+
+func (h *userHandler) forceLogout(c fiber.Ctx) error {
+	if err := h.UseCase.ForceLogout(c.RequestCtx(), c.Params("id")); err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+	})
+}
+
+func (h *userHandler) changeRole(c fiber.Ctx) error {
+	var req domain.ChangeRoleDTO
+	if err := c.Bind().Body(&req); err != nil {
+		return err
+	}
+	req.UserID = c.Params("id")
+
+	res, err := h.UseCase.ChangeRole(c.RequestCtx(), &req)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
@@ -22,12 +22,23 @@ func NewUserHandler(useCase domain.UserUsecase, middleware *middleware.Middlewar
 func (h *userHandler) RegisterRoutes(r fiber.Router) {
 	r.Use(h.middleware.Auth())
 	r.Get("/me", h.getUser)
+	r.Put("/me/password", h.changePassword)
+	r.Post("/me/email", h.changeEmail)
+	r.Post("/me/reauth", h.reauthenticate)
+	r.Get("/me/identities", h.listIdentities)
+	r.Post("/me/identities/local", h.linkLocalIdentity)
+	r.Delete("/me/identities/:id", h.unlinkIdentity)
+	r.Post("/me/mfa/totp", h.enrollTotp)
+	r.Post("/me/mfa/totp/confirm", h.confirmTotp)
+	r.Post("/me/mfa/recovery-codes", h.regenerateRecoveryCodes)
+	r.Delete("/me/mfa", h.disableMfa)
+	r.Post("/me/passkeys/register/begin", h.beginPasskeyRegistration)
+	r.Post("/me/passkeys/register/finish", h.finishPasskeyRegistration)
 }
 
 func (h *userHandler) getUser(c fiber.Ctx) error {
-	val := c.Locals(domain.SessionCtxKey)
-	session, ok := val.(*domain.Session)
-	if !ok || session == nil {
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
 		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
 	}
 
@@ -38,3 +49,235 @@ func (h *userHandler) getUser(c fiber.Ctx) error {
 		Data:    session,
 	})
 }
+
+func (h *userHandler) changePassword(c fiber.Ctx) error {
+	var req domain.ChangePasswordDTO
+	if err := c.Bind().Body(&req); err != nil {
+		return err
+	}
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+	req.UserID = session.UserID
+	req.SessionToken, _ = c.Locals(domain.SessionTokenCtxKey).(string)
+	req.FamilyID, _ = c.Locals(domain.TokenFamilyCtxKey).(string)
+
+	if err := h.UseCase.ChangePassword(c.RequestCtx(), &req); err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+	})
+}
+
+// changeEmail email belum berubah sampai link konfirmasi yang dikirim ke email baru dibuka
+func (h *userHandler) changeEmail(c fiber.Ctx) error {
+	var req domain.ChangeEmailDTO
+	if err := c.Bind().Body(&req); err != nil {
+		return err
+	}
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+	req.UserID = session.UserID
+
+	if err := h.UseCase.RequestEmailChange(c.RequestCtx(), &req); err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+	})
+}
+
+func (h *userHandler) reauthenticate(c fiber.Ctx) error {
+	var req domain.ReauthDTO
+	if err := c.Bind().Body(&req); err != nil {
+		return err
+	}
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+	userID := session.UserID
+
+	if err := h.UseCase.Reauthenticate(c.RequestCtx(), userID, &req); err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+	})
+}
+
+func (h *userHandler) listIdentities(c fiber.Ctx) error {
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+	userID := session.UserID
+
+	res, err := h.UseCase.ListIdentities(c.RequestCtx(), userID)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
+
+// linkLocalIdentity provider sosial ditautkan lewat GET /auth/oauth/:provider/link
+func (h *userHandler) linkLocalIdentity(c fiber.Ctx) error {
+	var req domain.LinkLocalIdentityDTO
+	if err := c.Bind().Body(&req); err != nil {
+		return err
+	}
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+	req.UserID = session.UserID
+
+	res, err := h.UseCase.LinkLocalIdentity(c.RequestCtx(), &req)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.Status(fiber.StatusCreated).JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res.UserIdentity,
+	})
+}
+
+func (h *userHandler) unlinkIdentity(c fiber.Ctx) error {
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+	userID := session.UserID
+
+	if err := h.UseCase.UnlinkIdentity(c.RequestCtx(), userID, c.Params("id")); err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+	})
+}
+
+// enrollTotp butuh login / re-auth yang masih baru
+func (h *userHandler) enrollTotp(c fiber.Ctx) error {
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+	userID := session.UserID
+
+	res, err := h.UseCase.EnrollTotp(c.RequestCtx(), userID)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
+
+func (h *userHandler) confirmTotp(c fiber.Ctx) error {
+	var req domain.ConfirmTotpDTO
+	if err := c.Bind().Body(&req); err != nil {
+		return err
+	}
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+	userID := session.UserID
+
+	res, err := h.UseCase.ConfirmTotp(c.RequestCtx(), userID, &req)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
+
+func (h *userHandler) regenerateRecoveryCodes(c fiber.Ctx) error {
+	var req domain.MfaCodeDTO
+	if err := c.Bind().Body(&req); err != nil {
+		return err
+	}
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+	userID := session.UserID
+
+	res, err := h.UseCase.RegenerateRecoveryCodes(c.RequestCtx(), userID, &req)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
+
+func (h *userHandler) disableMfa(c fiber.Ctx) error {
+	var req domain.MfaCodeDTO
+	if err := c.Bind().Body(&req); err != nil {
+		return err
+	}
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+	userID := session.UserID
+
+	if err := h.UseCase.DisableMfa(c.RequestCtx(), userID, &req); err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+	})
+}
+
+// beginPasskeyRegistration butuh login / re-auth yang masih baru
+func (h *userHandler) beginPasskeyRegistration(c fiber.Ctx) error {
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+	userID := session.UserID
+
+	res, err := h.UseCase.BeginPasskeyRegistration(c.RequestCtx(), userID)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
+
+func (h *userHandler) finishPasskeyRegistration(c fiber.Ctx) error {
+	var req domain.PasskeyRegisterDTO
+	if err := c.Bind().Body(&req); err != nil {
+		return err
+	}
+	session, ok := domain.SessionFromContext(c.RequestCtx())
+	if !ok {
+		return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+	}
+	req.UserID = session.UserID
+
+	res, err := h.UseCase.FinishPasskeyRegistration(c.RequestCtx(), &req)
+	if err != nil {
+		return utils.ErrorResponse(c, err, nil)
+	}
+	return c.Status(fiber.StatusCreated).JSON(domain.HttpResponse{
+		Success: true,
+		Data:    res,
+	})
+}
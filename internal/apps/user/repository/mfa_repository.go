@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"booking/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type mfaRepository struct {
+	DB *sqlx.DB
+}
+
+func NewMfaRepository(db *sqlx.DB) domain.MfaRepository {
+	return &mfaRepository{
+		DB: db,
+	}
+}
+
+func (r *mfaRepository) GetByUserID(ctx context.Context, userID string) (*domain.UserMfa, error) {
+	var res domain.UserMfa
+
+	query := `
+		SELECT user_id, totp_secret, enabled, enabled_at, created_at, updated_at
+		FROM user_mfa
+		WHERE user_id = $1
+	`
+	if err := r.DB.GetContext(ctx, &res, query, userID); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (r *mfaRepository) Upsert(ctx context.Context, userID, encryptedSecret string) error {
+	query := `
+		INSERT INTO user_mfa (user_id, totp_secret)
+			VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET
+			totp_secret = EXCLUDED.totp_secret,
+			updated_at = NOW()
+		WHERE NOT user_mfa.enabled
+	`
+	result, err := r.DB.ExecContext(ctx, query, userID, encryptedSecret)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return domain.ErrMfaAlreadyEnabled
+	}
+
+	return nil
+}
+
+func (r *mfaRepository) Enable(ctx context.Context, userID string, recoveryCodeHashes []string) error {
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE user_mfa SET enabled = TRUE, enabled_at = NOW(), updated_at = NOW()
+		WHERE user_id = $1 AND NOT enabled
+	`
+	result, err := tx.ExecContext(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return domain.ErrMfaAlreadyEnabled
+	}
+
+	if err := replaceRecoveryCodes(ctx, tx, userID, recoveryCodeHashes); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *mfaRepository) Disable(ctx context.Context, userID string) error {
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	result, err := tx.ExecContext(ctx, `DELETE FROM user_mfa WHERE user_id = $1`, userID)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return sql.ErrNoRows
+	}
+
+	return tx.Commit()
+}
+
+func (r *mfaRepository) ReplaceRecoveryCodes(ctx context.Context, userID string, recoveryCodeHashes []string) error {
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := replaceRecoveryCodes(ctx, tx, userID, recoveryCodeHashes); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UseRecoveryCode UPDATE ... WHERE used_at IS NULL, jadi kode yang sama gak bisa dipakai dua request
+func (r *mfaRepository) UseRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error) {
+	query := `
+		UPDATE user_recovery_codes SET used_at = NOW()
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`
+	result, err := r.DB.ExecContext(ctx, query, userID, codeHash)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return n > 0, nil
+}
+
+// replaceRecoveryCodes kode lama (terpakai atau belum) dihapus semua
+func replaceRecoveryCodes(ctx context.Context, tx *sqlx.Tx, userID string, hashes []string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO user_recovery_codes (id, user_id, code_hash) VALUES ($1, $2, $3)`
+	for _, hash := range hashes {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, query, id.String(), userID, hash); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
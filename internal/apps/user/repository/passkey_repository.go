@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+
+	"booking/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type passkeyRepository struct {
+	DB *sqlx.DB
+}
+
+func NewPasskeyRepository(db *sqlx.DB) domain.PasskeyRepository {
+	return &passkeyRepository{
+		DB: db,
+	}
+}
+
+const selectPasskeyCredential = `
+	SELECT id, user_id, identity_id, name, public_key, attestation_type, aaguid, sign_count,
+		transports, backup_eligible, backup_state, last_used_at, created_at
+	FROM passkey_credentials
+`
+
+func (r *passkeyRepository) GetByID(ctx context.Context, credentialID []byte) (*domain.PasskeyCredential, error) {
+	var res domain.PasskeyCredential
+
+	query := selectPasskeyCredential + `WHERE id = $1`
+	if err := r.DB.GetContext(ctx, &res, query, credentialID); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (r *passkeyRepository) ListByUserID(ctx context.Context, userID string) ([]domain.PasskeyCredential, error) {
+	res := []domain.PasskeyCredential{}
+
+	query := selectPasskeyCredential + `WHERE user_id = $1 ORDER BY created_at`
+	if err := r.DB.SelectContext(ctx, &res, query, userID); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (r *passkeyRepository) Create(ctx context.Context, credential *domain.PasskeyCredential) error {
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	identityID, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+
+	// passkey pertama user = identity baru, passkey berikutnya nempel ke identity yang sama
+	query := `
+		INSERT INTO user_identities (id, user_id, provider, provider_id, verified)
+			VALUES ($1, $2, $3, $2, TRUE)
+		ON CONFLICT DO NOTHING
+	`
+	if _, err := tx.ExecContext(ctx, query, identityID.String(), credential.UserID, domain.ProviderPasskey); err != nil {
+		return err
+	}
+	query = `SELECT id FROM user_identities WHERE user_id = $1 AND provider = $2`
+	if err := tx.GetContext(ctx, &credential.IdentityID, query, credential.UserID, domain.ProviderPasskey); err != nil {
+		return err
+	}
+
+	query = `
+		INSERT INTO passkey_credentials (id, user_id, identity_id, name, public_key, attestation_type, aaguid,
+			sign_count, transports, backup_eligible, backup_state)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING created_at
+	`
+	err = tx.QueryRowxContext(ctx, query,
+		credential.ID,
+		credential.UserID,
+		credential.IdentityID,
+		credential.Name,
+		credential.PublicKey,
+		credential.AttestationType,
+		credential.AAGUID,
+		credential.SignCount,
+		credential.Transports,
+		credential.BackupEligible,
+		credential.BackupState,
+	).Scan(&credential.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UpdateSignCount counter 0 = authenticator yang gak pakai counter (passkey tersinkron), selalu diterima
+func (r *passkeyRepository) UpdateSignCount(ctx context.Context, credentialID []byte, signCount int64, backupState bool) (bool, error) {
+	query := `
+		UPDATE passkey_credentials SET sign_count = $2, backup_state = $3, last_used_at = NOW()
+		WHERE id = $1 AND (sign_count < $2 OR (sign_count = 0 AND $2 = 0))
+	`
+	result, err := r.DB.ExecContext(ctx, query, credentialID, signCount, backupState)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return n > 0, nil
+}
@@ -2,6 +2,9 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
+	"strings"
 
 	"booking/internal/domain"
 
@@ -18,32 +21,89 @@ func NewUserRepository(db *sqlx.DB) domain.UserRepository {
 	}
 }
 
+// kolom user + identity, dipakai semua query yang balikin domain.UserWithIdentity
+const selectUserWithIdentity = `
+	SELECT
+		u.id AS "user.id",
+		u.name AS "user.name",
+		u.image_url AS "user.image_url",
+		u.role AS "user.role",
+		u.disabled_at AS "user.disabled_at",
+		u.created_at AS "user.created_at",
+		u.updated_at AS "user.updated_at",
+
+		ui.id AS "useridentity.id",
+		ui.user_id AS "useridentity.user_id",
+		ui.provider AS "useridentity.provider",
+		ui.provider_id AS "useridentity.provider_id",
+		ui.email AS "useridentity.email",
+		ui.phone AS "useridentity.phone",
+		ui.password_hash AS "useridentity.password_hash",
+		ui.verified AS "useridentity.verified",
+		ui.created_at AS "useridentity.created_at",
+		ui.updated_at AS "useridentity.updated_at"
+	FROM user_identities ui
+	JOIN users u ON ui.user_id = u.id
+`
+
+// GetByEmail cuma identity local (email + password), identity sosial bisa punya email yang sama
 func (r *userRepository) GetByEmail(ctx context.Context, email string) (*domain.UserWithIdentity, error) {
 	var res domain.UserWithIdentity
 
-	query := `
-		SELECT
-			u.id AS "user.id",
-			u.name AS "user.name",
-			u.image_url AS "user.image_url",
-			u.role AS "user.role",
-			u.created_at AS "user.created_at",
-			u.updated_at AS "user.updated_at",
-
-			ui.id AS "useridentity.id",
-			ui.user_id AS "useridentity.user_id",
-			ui.provider AS "useridentity.provider",
-			ui.provider_id AS "useridentity.provider_id",
-			ui.email AS "useridentity.email",
-			ui.phone AS "useridentity.phone",
-			ui.password_hash AS "useridentity.password_hash",
-			ui.verified AS "useridentity.verified",
-			ui.created_at AS "useridentity.created_at",
-			ui.updated_at AS "useridentity.updated_at"
-		FROM user_identities ui
-		JOIN users u ON ui.user_id = u.id
-		WHERE ui.email = $1
-	`
+	query := selectUserWithIdentity + `WHERE ui.email = $1 AND ui.provider = $2`
+
+	err := r.DB.GetContext(ctx, &res, query, email, domain.ProviderLocal)
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (r *userRepository) GetByIdentityID(ctx context.Context, identityID string) (*domain.UserWithIdentity, error) {
+	var res domain.UserWithIdentity
+
+	query := selectUserWithIdentity + `WHERE ui.id = $1`
+
+	err := r.DB.GetContext(ctx, &res, query, identityID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (r *userRepository) GetByUserIDAndProvider(ctx context.Context, userID, provider string) (*domain.UserWithIdentity, error) {
+	var res domain.UserWithIdentity
+
+	query := selectUserWithIdentity + `WHERE ui.user_id = $1 AND ui.provider = $2`
+
+	err := r.DB.GetContext(ctx, &res, query, userID, provider)
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (r *userRepository) GetByProviderID(ctx context.Context, provider, providerID string) (*domain.UserWithIdentity, error) {
+	var res domain.UserWithIdentity
+
+	query := selectUserWithIdentity + `WHERE ui.provider = $1 AND ui.provider_id = $2`
+
+	err := r.DB.GetContext(ctx, &res, query, provider, providerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// GetByVerifiedEmail identity apapun (provider manapun) yang email nya sudah terverifikasi
+func (r *userRepository) GetByVerifiedEmail(ctx context.Context, email string) (*domain.UserWithIdentity, error) {
+	var res domain.UserWithIdentity
+
+	query := selectUserWithIdentity + `WHERE ui.email = $1 AND ui.verified ORDER BY ui.created_at LIMIT 1`
 
 	err := r.DB.GetContext(ctx, &res, query, email)
 	if err != nil {
@@ -53,6 +113,76 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*domain.
 	return &res, nil
 }
 
+// ExistsByEmail ada identity (terverifikasi atau belum) yang memakai email ini
+func (r *userRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	var exists bool
+	err := r.DB.GetContext(ctx, &exists, `SELECT EXISTS (SELECT 1 FROM user_identities WHERE email = $1)`, email)
+	return exists, err
+}
+
+// CreateWithIdentity user baru dari login sosial
+func (r *userRepository) CreateWithIdentity(ctx context.Context, user *domain.User, identity *domain.UserIdentity) (*domain.UserWithIdentity, error) {
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var res domain.UserWithIdentity
+	query := `
+		INSERT INTO users (id, name, image_url, role)
+			VALUES ($1, $2, $3, $4)
+		RETURNING id, name, image_url, role, created_at, updated_at
+	`
+	err = tx.QueryRowxContext(ctx, query,
+		user.ID,
+		user.Name,
+		user.ImageURL,
+		user.Role,
+	).StructScan(&res.User)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := insertIdentity(ctx, tx, identity, &res.UserIdentity); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// CreateIdentity tambah login method ke user yang sudah ada
+func (r *userRepository) CreateIdentity(ctx context.Context, identity *domain.UserIdentity) (*domain.UserWithIdentity, error) {
+	var res domain.UserIdentity
+	if err := insertIdentity(ctx, r.DB, identity, &res); err != nil {
+		return nil, err
+	}
+
+	return r.GetByIdentityID(ctx, res.ID)
+}
+
+func insertIdentity(ctx context.Context, q sqlx.QueryerContext, identity *domain.UserIdentity, dest *domain.UserIdentity) error {
+	query := `
+		INSERT INTO user_identities (id, user_id, provider, provider_id, email, phone, password_hash, verified)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, user_id, provider, provider_id, email, phone, password_hash, verified, created_at, updated_at
+	`
+	return q.QueryRowxContext(ctx, query,
+		identity.ID,
+		identity.UserID,
+		identity.Provider,
+		identity.ProviderID,
+		identity.Email,
+		identity.Phone,
+		identity.PasswordHash,
+		identity.Verified,
+	).StructScan(dest)
+}
+
 func (r *userRepository) RegisterUser(ctx context.Context, req *domain.RegisterDTO) (*domain.UserWithIdentity, error) {
 	// start trx
 	tx, err := r.DB.BeginTxx(ctx, nil)
@@ -88,7 +218,7 @@ func (r *userRepository) RegisterUser(ctx context.Context, req *domain.RegisterD
 		createUserIdentityQuery,
 		req.IdIdentity,
 		req.ID,
-		"local",
+		domain.ProviderLocal,
 		req.Email, // provider_id (pakai email untuk local)
 		req.Email,
 		req.Password,
@@ -108,3 +238,243 @@ func (r *userRepository) RegisterUser(ctx context.Context, req *domain.RegisterD
 		UserIdentity: userIdentity,
 	}, nil
 }
+
+func (r *userRepository) SetIdentityVerified(ctx context.Context, identityID string) (*domain.UserWithIdentity, error) {
+	query := `
+		UPDATE user_identities SET verified = TRUE, updated_at = NOW()
+		WHERE id = $1
+	`
+	result, err := r.DB.ExecContext(ctx, query, identityID)
+	if err != nil {
+		return nil, err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return nil, err
+	} else if n == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return r.GetByIdentityID(ctx, identityID)
+}
+
+func (r *userRepository) UpdatePassword(ctx context.Context, identityID, passwordHash string) error {
+	query := `
+		UPDATE user_identities SET password_hash = $2, updated_at = NOW()
+		WHERE id = $1
+	`
+	result, err := r.DB.ExecContext(ctx, query, identityID, passwordHash)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// UpdateEmail email baru sudah dikonfirmasi lewat link, jadi langsung verified.
+// identity local juga pakai email sebagai provider_id
+func (r *userRepository) UpdateEmail(ctx context.Context, identityID, email string) (*domain.UserWithIdentity, error) {
+	query := `
+		UPDATE user_identities SET
+			email = $2,
+			provider_id = CASE WHEN provider = $3 THEN $2 ELSE provider_id END,
+			verified = TRUE,
+			updated_at = NOW()
+		WHERE id = $1
+	`
+	result, err := r.DB.ExecContext(ctx, query, identityID, email, domain.ProviderLocal)
+	if err != nil {
+		return nil, err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return nil, err
+	} else if n == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return r.GetByIdentityID(ctx, identityID)
+}
+
+func (r *userRepository) ListIdentities(ctx context.Context, userID string) ([]domain.UserIdentity, error) {
+	res := make([]domain.UserIdentity, 0)
+
+	query := `
+		SELECT id, user_id, provider, provider_id, email, phone, password_hash, verified, created_at, updated_at
+		FROM user_identities
+		WHERE user_id = $1
+		ORDER BY created_at
+	`
+	if err := r.DB.SelectContext(ctx, &res, query, userID); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// DeleteIdentity semua identity user di-lock dulu, supaya dua unlink yang bersamaan
+// gak bisa sama-sama lolos cek lalu menghapus login terakhir
+func (r *userRepository) DeleteIdentity(ctx context.Context, userID, identityID string) (*domain.UserIdentity, error) {
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var identities []domain.UserIdentity
+	query := `
+		SELECT id, user_id, provider, provider_id, email, phone, password_hash, verified, created_at, updated_at
+		FROM user_identities
+		WHERE user_id = $1
+		FOR UPDATE
+	`
+	if err := tx.SelectContext(ctx, &identities, query, userID); err != nil {
+		return nil, err
+	}
+
+	var target *domain.UserIdentity
+	usable := 0
+	for i := range identities {
+		if identities[i].ID == identityID {
+			target = &identities[i]
+			continue
+		}
+		if identities[i].Usable() {
+			usable++
+		}
+	}
+	if target == nil {
+		return nil, sql.ErrNoRows
+	}
+	if usable == 0 {
+		return nil, domain.ErrLastIdentity
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_identities WHERE id = $1`, identityID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return target, nil
+}
+
+func (r *userRepository) UpdateRole(ctx context.Context, userID, role string) (*domain.User, error) {
+	var res domain.User
+
+	query := `
+		UPDATE users SET role = $2, updated_at = NOW()
+		WHERE id = $1
+		RETURNING id, name, image_url, role, disabled_at, created_at, updated_at
+	`
+	if err := r.DB.GetContext(ctx, &res, query, userID, role); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// kolom domain.AdminUser. email diambil dari identity pertama yang punya email
+const selectAdminUser = `
+	SELECT
+		u.id, u.name, u.image_url, u.role, u.disabled_at, u.created_at, u.updated_at,
+		(
+			SELECT ui.email FROM user_identities ui
+			WHERE ui.user_id = u.id AND ui.email IS NOT NULL
+			ORDER BY ui.created_at LIMIT 1
+		) AS email,
+		EXISTS (SELECT 1 FROM user_identities ui WHERE ui.user_id = u.id AND ui.verified) AS verified
+	FROM users u
+`
+
+// List WHERE cuma berisi filter yang diisi, supaya planner bisa pakai index nya.
+// search cocok dengan awalan nama atau awalan email identity manapun (case insensitive, index lower(...)
+// text_pattern_ops di migration 000025), pattern LIKE sudah di-escape usecase
+func (r *userRepository) List(ctx context.Context, filter *domain.AdminUserFilter) ([]domain.AdminUser, error) {
+	res := make([]domain.AdminUser, 0)
+
+	var (
+		conds []string
+		args  []any
+	)
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.Search != "" {
+		pattern := arg(strings.ToLower(filter.Search) + "%")
+		// UNION bukan OR, supaya masing-masing sisi bisa index scan
+		conds = append(conds, `u.id IN (
+			SELECT id FROM users WHERE lower(name) LIKE `+pattern+`
+			UNION
+			SELECT user_id FROM user_identities WHERE lower(email) LIKE `+pattern+`
+		)`)
+	}
+	if filter.Role != "" {
+		conds = append(conds, "u.role = "+arg(filter.Role))
+	}
+	if filter.Verified != nil {
+		conds = append(conds, `EXISTS (
+			SELECT 1 FROM user_identities ui WHERE ui.user_id = u.id AND ui.verified
+		) = `+arg(*filter.Verified))
+	}
+	if filter.CreatedFrom != nil {
+		conds = append(conds, "u.created_at >= "+arg(*filter.CreatedFrom))
+	}
+	if filter.CreatedTo != nil {
+		conds = append(conds, "u.created_at < "+arg(*filter.CreatedTo))
+	}
+
+	query := selectAdminUser
+	if len(conds) > 0 {
+		query += "WHERE " + strings.Join(conds, " AND ")
+	}
+	query += `
+		ORDER BY u.created_at DESC, u.id
+		LIMIT ` + arg(filter.Limit) + ` OFFSET ` + arg(filter.Offset)
+
+	err := r.DB.SelectContext(ctx, &res, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (r *userRepository) GetAdminUser(ctx context.Context, userID string) (*domain.AdminUser, error) {
+	var res domain.AdminUser
+
+	query := selectAdminUser + `WHERE u.id = $1`
+
+	if err := r.DB.GetContext(ctx, &res, query, userID); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (r *userRepository) SetDisabled(ctx context.Context, userID string, disabled bool) (*domain.AdminUser, error) {
+	query := `
+		UPDATE users SET
+			disabled_at = CASE WHEN $2 THEN COALESCE(disabled_at, NOW()) END,
+			updated_at = NOW()
+		WHERE id = $1
+	`
+	result, err := r.DB.ExecContext(ctx, query, userID, disabled)
+	if err != nil {
+		return nil, err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return nil, err
+	} else if n == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return r.GetAdminUser(ctx, userID)
+}
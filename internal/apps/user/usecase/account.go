@@ -0,0 +1,185 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"booking/internal/domain"
+	"booking/pkg/constant"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ChangePassword salah password dihitung ke limiter login yang sama dengan email identity local nya
+func (u *userUseCase) ChangePassword(ctx context.Context, req *domain.ChangePasswordDTO) error {
+	user, err := u.getLocalIdentity(ctx, req.UserID)
+	if err != nil {
+		return err
+	}
+	email := domain.NilStringHandler(user.UserIdentity.Email)
+
+	if err := u.checkPassword(ctx, user, email, req.CurrentPassword); err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		u.log.Error(err, "failed to hash password")
+		return domain.ErrInternalServerError
+	}
+	if err := u.userRepository.UpdatePassword(ctx, user.UserIdentity.ID, string(hashedPassword)); err != nil {
+		u.log.Error(err, "failed to update password")
+		return domain.ErrInternalServerError
+	}
+
+	if !req.LogoutOtherSessions {
+		return nil
+	}
+	// session web & refresh token mobile yang sedang dipakai tetap hidup
+	if err := u.security.LogoutOtherSessions(ctx, req.UserID, req.SessionToken); err != nil {
+		u.log.Error(err, "failed to logout other sessions")
+		return domain.ErrInternalServerError
+	}
+	if err := u.security.RevokeOtherRefreshFamilies(ctx, req.UserID, req.FamilyID); err != nil {
+		u.log.Error(err, "failed to revoke other refresh token families")
+		return domain.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// RequestEmailChange kirim link konfirmasi ke email baru, email lama tetap dipakai sampai dikonfirmasi
+func (u *userUseCase) RequestEmailChange(ctx context.Context, req *domain.ChangeEmailDTO) error {
+	user, err := u.getLocalIdentity(ctx, req.UserID)
+	if err != nil {
+		return err
+	}
+	email := domain.NilStringHandler(user.UserIdentity.Email)
+	if email == req.Email {
+		return domain.ErrInvalidRequest
+	}
+
+	if err := u.checkPassword(ctx, user, email, req.Password); err != nil {
+		return err
+	}
+
+	if _, err := u.userRepository.GetByEmail(ctx, req.Email); err == nil {
+		return domain.ErrUserAlreadyExists
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		u.log.Error(err, "failed to get user by email")
+		return domain.ErrInternalServerError
+	}
+
+	token, err := u.security.CreateEmailChangeToken(ctx, user.UserIdentity.ID, req.Email)
+	if err != nil {
+		return domain.ErrInternalServerError
+	}
+
+	link := fmt.Sprintf("%s/api/v1/auth/email/confirm?token=%s", u.config.App.PublicURL, url.QueryEscape(token))
+	err = u.mailer.Send(ctx, &domain.Email{
+		To:      req.Email,
+		Subject: "Confirm your new email",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nPlease confirm your new email address by opening the link below:\n%s\n\nThe link expires in %s.\n",
+			user.User.Name, link, u.config.App.EmailVerifyTtl,
+		),
+	})
+	if err != nil {
+		u.log.Error(err, "failed to send email change confirmation")
+		return domain.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// ConfirmEmailChange ganti email identity + update session aktif, lalu kabari email lama
+func (u *userUseCase) ConfirmEmailChange(ctx context.Context, token string) (*domain.UserWithIdentity, error) {
+	identityID, email, err := u.security.ConsumeEmailChangeToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidVerificationToken) {
+			return nil, err
+		}
+		return nil, domain.ErrInternalServerError
+	}
+
+	previous, err := u.GetByIdentityID(ctx, identityID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, domain.ErrInvalidVerificationToken
+		}
+		return nil, err
+	}
+
+	res, err := u.userRepository.UpdateEmail(ctx, identityID, email)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == constant.PgErrUniqueViolation {
+			return nil, domain.ErrUserAlreadyExists
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrInvalidVerificationToken
+		}
+		u.log.Error(err, "failed to update email")
+		return nil, domain.ErrInternalServerError
+	}
+
+	if err := u.security.UpdateIdentitySessions(ctx, res.User.ID, res.UserIdentity.Provider, map[string]interface{}{
+		"email":       domain.NilStringHandler(res.UserIdentity.Email),
+		"provider_id": res.UserIdentity.ProviderID,
+		"verified":    domain.BoolToString(res.UserIdentity.Verified),
+	}); err != nil {
+		u.log.Error(err, "failed to update email in sessions")
+	}
+
+	if previous.UserIdentity.Email != nil {
+		err := u.mailer.Send(ctx, &domain.Email{
+			To:      *previous.UserIdentity.Email,
+			Subject: "Your email was changed",
+			Body: fmt.Sprintf(
+				"Hi %s,\n\nThe email of your account was changed to %s. If you did not do this, reset your password immediately.\n",
+				res.User.Name, email,
+			),
+		})
+		if err != nil {
+			u.log.Error(err, "failed to send email changed notice")
+		}
+	}
+
+	return res, nil
+}
+
+// getLocalIdentity password & email cuma ada di identity local, user yang cuma login sosial gak bisa ganti
+func (u *userUseCase) getLocalIdentity(ctx context.Context, userID string) (*domain.UserWithIdentity, error) {
+	res, err := u.userRepository.GetByUserIDAndProvider(ctx, userID, domain.ProviderLocal)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrInvalidRequest
+		}
+		u.log.Error(err, "failed to get local identity")
+		return nil, domain.ErrInternalServerError
+	}
+	if res.UserIdentity.PasswordHash == nil {
+		return nil, domain.ErrInvalidRequest
+	}
+	return res, nil
+}
+
+func (u *userUseCase) checkPassword(ctx context.Context, user *domain.UserWithIdentity, email, password string) error {
+	delay, err := u.security.CheckBan(ctx, email)
+	if err != nil {
+		return domain.ErrInternalServerError
+	}
+	if delay > 0 {
+		return domain.ErrToomanyrequest
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(*user.UserIdentity.PasswordHash), []byte(password)); err != nil {
+		_, _ = u.security.IncrementAttempts(ctx, email)
+		return domain.ErrInvalidCredentials
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"booking/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultAdminPageLimit = 20
+	maxAdminPageLimit     = 100
+)
+
+// likeEscaper karakter wildcard di input search dianggap huruf biasa
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// ChangeRole role di session redis yang masih aktif langsung diganti. access token jwt (mobile) yang
+// bawa role lama ditolak middleware Auth lewat penanda di redis, client refresh untuk dapat role terbaru dari db
+func (u *userUseCase) ChangeRole(ctx context.Context, req *domain.ChangeRoleDTO) (*domain.User, error) {
+	if _, err := uuid.Parse(req.UserID); err != nil {
+		return nil, domain.ErrUserNotFound
+	}
+	// admin gak bisa ganti role sendiri, supaya gak ada yang gak sengaja mengunci akses admin terakhir
+	if session, ok := domain.SessionFromContext(ctx); ok && session.UserID == req.UserID {
+		return nil, domain.ErrForbiden
+	}
+
+	res, err := u.userRepository.UpdateRole(ctx, req.UserID, req.Role)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		u.log.Error(err, "failed to update user role")
+		return nil, domain.ErrInternalServerError
+	}
+
+	if err := u.security.MarkRoleChanged(ctx, req.UserID, res.Role); err != nil {
+		// access token dengan role lama gak boleh tetap hidup, family yang di-revoke ikut mematikan access token nya
+		if err := u.security.RevokeAllRefreshFamilies(ctx, req.UserID); err != nil {
+			u.log.Error(err, "failed to revoke user refresh token families")
+			return nil, domain.ErrInternalServerError
+		}
+	}
+	if err := u.security.UpdateUserSessions(ctx, req.UserID, map[string]interface{}{"role": res.Role}); err != nil {
+		// session dengan role lama gak boleh tetap hidup
+		u.log.Error(err, "failed to update role in user sessions, logging out all sessions")
+		if err := u.security.LogoutAllSessions(ctx, req.UserID); err != nil {
+			u.log.Error(err, "failed to logout user sessions")
+			return nil, domain.ErrInternalServerError
+		}
+	}
+
+	return res, nil
+}
+
+func (u *userUseCase) ListUsers(ctx context.Context, q *domain.AdminUserQuery) ([]domain.AdminUser, error) {
+	filter := domain.AdminUserFilter{
+		Search: likeEscaper.Replace(strings.TrimSpace(q.Search)),
+		Role:   q.Role,
+		Limit:  q.Limit,
+		Offset: q.Offset,
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAdminPageLimit
+	}
+	if filter.Limit > maxAdminPageLimit {
+		filter.Limit = maxAdminPageLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	if q.Verified != "" {
+		verified := q.Verified == "true"
+		filter.Verified = &verified
+	}
+	if q.CreatedFrom != "" {
+		from, err := time.Parse(time.DateOnly, q.CreatedFrom)
+		if err != nil {
+			return nil, domain.ErrInvalidRequest
+		}
+		filter.CreatedFrom = &from
+	}
+	if q.CreatedTo != "" {
+		to, err := time.Parse(time.DateOnly, q.CreatedTo)
+		if err != nil {
+			return nil, domain.ErrInvalidRequest
+		}
+		// created_to inklusif, user yang daftar di hari itu ikut
+		to = to.AddDate(0, 0, 1)
+		filter.CreatedTo = &to
+	}
+
+	res, err := u.userRepository.List(ctx, &filter)
+	if err != nil {
+		u.log.Error(err, "failed to list users")
+		return nil, domain.ErrInternalServerError
+	}
+
+	return res, nil
+}
+
+func (u *userUseCase) GetUserDetail(ctx context.Context, userID string) (*domain.AdminUserDetail, error) {
+	user, err := u.getAdminUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	identities, err := u.userRepository.ListIdentities(ctx, userID)
+	if err != nil {
+		u.log.Error(err, "failed to list user identities")
+		return nil, domain.ErrInternalServerError
+	}
+
+	return &domain.AdminUserDetail{AdminUser: *user, Identities: identities}, nil
+}
+
+// SetUserDisabled user yang dinonaktifkan langsung di-logout dari semua device. access token jwt yang masih hidup
+// ikut ditolak middleware Auth karena family refresh token nya sudah di-revoke, login ulang ditolak ErrAccountDisabled
+func (u *userUseCase) SetUserDisabled(ctx context.Context, userID string, disabled bool) (*domain.AdminUser, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, domain.ErrUserNotFound
+	}
+	// sama seperti ChangeRole, admin gak bisa menonaktifkan akun sendiri
+	if session, ok := domain.SessionFromContext(ctx); ok && session.UserID == userID {
+		return nil, domain.ErrForbiden
+	}
+
+	res, err := u.userRepository.SetDisabled(ctx, userID, disabled)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		u.log.Error(err, "failed to update user disabled status")
+		return nil, domain.ErrInternalServerError
+	}
+
+	if !disabled {
+		return res, nil
+	}
+
+	if err := u.security.LogoutAllSessions(ctx, userID); err != nil {
+		u.log.Error(err, "failed to logout disabled user sessions")
+		return nil, domain.ErrInternalServerError
+	}
+
+	return res, nil
+}
+
+// ForceLogout semua session web dan family refresh token user dihapus. access token jwt yang sudah terbit
+// ikut ditolak middleware Auth karena family nya sudah gak aktif
+func (u *userUseCase) ForceLogout(ctx context.Context, userID string) error {
+	if _, err := u.getAdminUser(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := u.security.LogoutAllSessions(ctx, userID); err != nil {
+		u.log.Error(err, "failed to logout user sessions")
+		return domain.ErrInternalServerError
+	}
+	return nil
+}
+
+func (u *userUseCase) getAdminUser(ctx context.Context, userID string) (*domain.AdminUser, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, domain.ErrUserNotFound
+	}
+
+	res, err := u.userRepository.GetAdminUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		u.log.Error(err, "failed to get user")
+		return nil, domain.ErrInternalServerError
+	}
+	return res, nil
+}
@@ -0,0 +1,198 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"booking/internal/domain"
+	"booking/pkg/constant"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func (u *userUseCase) ListIdentities(ctx context.Context, userID string) ([]domain.UserIdentity, error) {
+	res, err := u.userRepository.ListIdentities(ctx, userID)
+	if err != nil {
+		u.log.Error(err, "failed to list identities")
+		return nil, domain.ErrInternalServerError
+	}
+
+	return res, nil
+}
+
+// Reauthenticate cuma untuk user yang punya password, user login sosial cukup login ulang
+func (u *userUseCase) Reauthenticate(ctx context.Context, userID string, req *domain.ReauthDTO) error {
+	ref, ok := domain.AuthRefFromContext(ctx)
+	if !ok {
+		return domain.ErrUnauthorized
+	}
+
+	user, err := u.getLocalIdentity(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := u.checkPassword(ctx, user, domain.NilStringHandler(user.UserIdentity.Email), req.Password); err != nil {
+		return err
+	}
+
+	if err := u.security.MarkRecentAuth(ctx, ref); err != nil {
+		return domain.ErrInternalServerError
+	}
+	return nil
+}
+
+// RequireRecentAuth login yang sedang dipakai harus baru dibuat / baru re-auth
+func (u *userUseCase) RequireRecentAuth(ctx context.Context) error {
+	ref, ok := domain.AuthRefFromContext(ctx)
+	if !ok {
+		return domain.ErrReauthRequired
+	}
+
+	recent, err := u.security.HasRecentAuth(ctx, ref)
+	if err != nil {
+		return domain.ErrInternalServerError
+	}
+	if !recent {
+		return domain.ErrReauthRequired
+	}
+	return nil
+}
+
+// LinkLocalIdentity email langsung terverifikasi kalau sama dengan email terverifikasi milik user,
+// selain itu dikirim email verifikasi seperti register
+func (u *userUseCase) LinkLocalIdentity(ctx context.Context, req *domain.LinkLocalIdentityDTO) (*domain.UserWithIdentity, error) {
+	if err := u.RequireRecentAuth(ctx); err != nil {
+		return nil, err
+	}
+
+	identities, err := u.ListIdentities(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	verified := false
+	for _, identity := range identities {
+		if identity.Provider == domain.ProviderLocal {
+			return nil, domain.ErrIdentityAlreadyLinked
+		}
+		if identity.Verified && domain.NilStringHandler(identity.Email) == req.Email {
+			verified = true
+		}
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		u.log.Error(err, "failed to hash password")
+		return nil, domain.ErrInternalServerError
+	}
+	passwordHash := string(hashedPassword)
+
+	identityID, err := uuid.NewV7()
+	if err != nil {
+		u.log.Error(err, "failed to generate uuidv7 for user identity")
+		return nil, domain.ErrInternalServerError
+	}
+
+	res, err := u.userRepository.CreateIdentity(ctx, &domain.UserIdentity{
+		ID:           identityID.String(),
+		UserID:       req.UserID,
+		Provider:     domain.ProviderLocal,
+		ProviderID:   req.Email,
+		Email:        &req.Email,
+		PasswordHash: &passwordHash,
+		Verified:     verified,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == constant.PgErrUniqueViolation {
+			// constraint (user_id, provider) = sudah punya local, (provider, provider_id) = email dipakai user lain
+			if pgErr.ConstraintName == "uq_user_provider" {
+				return nil, domain.ErrIdentityAlreadyLinked
+			}
+			return nil, domain.ErrUserAlreadyExists
+		}
+		u.log.Error(err, "error creating local identity")
+		return nil, domain.ErrInternalServerError
+	}
+
+	if !res.UserIdentity.Verified {
+		if err := u.sendVerificationEmail(ctx, res); err != nil {
+			u.log.Error(err, "failed to send verification email")
+		}
+	}
+
+	return res, nil
+}
+
+// LinkOAuthIdentity dipanggil dari callback oauth, re-auth sudah dicek waktu flow tautkan dimulai
+func (u *userUseCase) LinkOAuthIdentity(ctx context.Context, userID string, info *domain.OAuthUserInfo) (*domain.UserWithIdentity, error) {
+	existing, err := u.userRepository.GetByProviderID(ctx, info.Provider, info.ProviderID)
+	if err == nil {
+		if existing.User.ID == userID {
+			return existing, nil
+		}
+		return nil, domain.ErrIdentityAlreadyLinked
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		u.log.Error(err, "failed to get user by provider id")
+		return nil, domain.ErrInternalServerError
+	}
+
+	identityID, err := uuid.NewV7()
+	if err != nil {
+		u.log.Error(err, "failed to generate uuidv7 for user identity")
+		return nil, domain.ErrInternalServerError
+	}
+	identity := &domain.UserIdentity{
+		ID:         identityID.String(),
+		UserID:     userID,
+		Provider:   info.Provider,
+		ProviderID: info.ProviderID,
+		Verified:   info.Email != "" && info.EmailVerified,
+	}
+	if info.Email != "" {
+		identity.Email = &info.Email
+	}
+
+	res, err := u.userRepository.CreateIdentity(ctx, identity)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == constant.PgErrUniqueViolation {
+			return nil, domain.ErrIdentityAlreadyLinked
+		}
+		u.log.Error(err, "error linking oauth identity")
+		return nil, domain.ErrInternalServerError
+	}
+
+	return res, nil
+}
+
+// UnlinkIdentity session yang login lewat identity tsb ikut dimatikan
+func (u *userUseCase) UnlinkIdentity(ctx context.Context, userID, identityID string) error {
+	if _, err := uuid.Parse(identityID); err != nil {
+		return domain.ErrIdentityNotFound
+	}
+	if err := u.RequireRecentAuth(ctx); err != nil {
+		return err
+	}
+
+	removed, err := u.userRepository.DeleteIdentity(ctx, userID, identityID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrIdentityNotFound
+		}
+		if errors.Is(err, domain.ErrLastIdentity) {
+			return err
+		}
+		u.log.Error(err, "failed to delete identity")
+		return domain.ErrInternalServerError
+	}
+
+	if err := u.security.LogoutProviderSessions(ctx, userID, removed.Provider); err != nil {
+		u.log.Error(err, "failed to logout sessions of unlinked identity")
+	}
+
+	return nil
+}
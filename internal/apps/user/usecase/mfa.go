@@ -0,0 +1,239 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"booking/internal/domain"
+	"booking/pkg/security"
+)
+
+// CompleteMfaLogin kode salah gak menghapus challenge, tapi dihitung ke ban per user
+func (u *userUseCase) CompleteMfaLogin(ctx context.Context, req *domain.MfaLoginDTO) (*domain.LoginResult, error) {
+	challenge, err := u.security.GetMfaChallenge(ctx, req.MfaToken)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidMfaChallenge) {
+			return nil, err
+		}
+		return nil, domain.ErrInternalServerError
+	}
+
+	user, err := u.GetByIdentityID(ctx, challenge.IdentityID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, domain.ErrInvalidMfaChallenge
+		}
+		return nil, err
+	}
+
+	if _, err := u.checkMfaCode(ctx, user.User.ID, &req.MfaCodeDTO); err != nil {
+		return nil, err
+	}
+
+	// dua request dengan kode benar bersamaan, cuma satu yang dapat session
+	ok, err := u.security.ConsumeMfaChallenge(ctx, req.MfaToken)
+	if err != nil {
+		return nil, domain.ErrInternalServerError
+	}
+	if !ok {
+		return nil, domain.ErrInvalidMfaChallenge
+	}
+
+	return u.createLogin(ctx, user, challenge.ClientType, challenge.Device, challenge.UserAgent, challenge.IpAddress)
+}
+
+// EnrollTotp secret baru belum aktif sampai dikonfirmasi dengan kode pertama (ConfirmTotp)
+func (u *userUseCase) EnrollTotp(ctx context.Context, userID string) (*domain.TotpEnrollment, error) {
+	if err := u.RequireRecentAuth(ctx); err != nil {
+		return nil, err
+	}
+
+	secret, err := security.GenerateTotpSecret()
+	if err != nil {
+		u.log.Error(err, "failed to generate totp secret")
+		return nil, domain.ErrInternalServerError
+	}
+	encrypted, err := u.security.EncryptSecret(secret)
+	if err != nil {
+		u.log.Error(err, "failed to encrypt totp secret")
+		return nil, domain.ErrInternalServerError
+	}
+
+	if err := u.mfaRepository.Upsert(ctx, userID, encrypted); err != nil {
+		if errors.Is(err, domain.ErrMfaAlreadyEnabled) {
+			return nil, err
+		}
+		u.log.Error(err, "failed to save totp secret")
+		return nil, domain.ErrInternalServerError
+	}
+
+	// label di authenticator: email / nomor hp yang dipakai login
+	account := userID
+	if session, ok := domain.SessionFromContext(ctx); ok {
+		if session.Email != "" {
+			account = session.Email
+		} else if session.Phone != "" {
+			account = session.Phone
+		}
+	}
+
+	return &domain.TotpEnrollment{
+		Secret:     secret,
+		OtpauthURI: u.security.TotpURI(secret, account),
+	}, nil
+}
+
+// ConfirmTotp aktifkan 2fa, recovery code cuma ditampilkan di response ini
+func (u *userUseCase) ConfirmTotp(ctx context.Context, userID string, req *domain.ConfirmTotpDTO) (*domain.RecoveryCodes, error) {
+	mfa, err := u.mfaRepository.GetByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrMfaNotEnabled
+		}
+		u.log.Error(err, "failed to get user mfa")
+		return nil, domain.ErrInternalServerError
+	}
+	if mfa.Enabled {
+		return nil, domain.ErrMfaAlreadyEnabled
+	}
+
+	ok, err := u.validateTotp(ctx, mfa, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, domain.ErrInvalidMfaCode
+	}
+
+	codes, hashes, err := security.GenerateRecoveryCodes(domain.RecoveryCodeCount)
+	if err != nil {
+		u.log.Error(err, "failed to generate recovery codes")
+		return nil, domain.ErrInternalServerError
+	}
+	if err := u.mfaRepository.Enable(ctx, userID, hashes); err != nil {
+		if errors.Is(err, domain.ErrMfaAlreadyEnabled) {
+			return nil, err
+		}
+		u.log.Error(err, "failed to enable mfa")
+		return nil, domain.ErrInternalServerError
+	}
+
+	return &domain.RecoveryCodes{Codes: codes}, nil
+}
+
+func (u *userUseCase) DisableMfa(ctx context.Context, userID string, req *domain.MfaCodeDTO) error {
+	if err := u.RequireRecentAuth(ctx); err != nil {
+		return err
+	}
+	if _, err := u.checkMfaCode(ctx, userID, req); err != nil {
+		return err
+	}
+
+	if err := u.mfaRepository.Disable(ctx, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrMfaNotEnabled
+		}
+		u.log.Error(err, "failed to disable mfa")
+		return domain.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// RegenerateRecoveryCodes semua recovery code lama hangus
+func (u *userUseCase) RegenerateRecoveryCodes(ctx context.Context, userID string, req *domain.MfaCodeDTO) (*domain.RecoveryCodes, error) {
+	if _, err := u.checkMfaCode(ctx, userID, req); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := security.GenerateRecoveryCodes(domain.RecoveryCodeCount)
+	if err != nil {
+		u.log.Error(err, "failed to generate recovery codes")
+		return nil, domain.ErrInternalServerError
+	}
+	if err := u.mfaRepository.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		u.log.Error(err, "failed to replace recovery codes")
+		return nil, domain.ErrInternalServerError
+	}
+
+	return &domain.RecoveryCodes{Codes: codes}, nil
+}
+
+// =============================
+// HELPERS
+// =============================
+
+func (u *userUseCase) mfaEnabled(ctx context.Context, userID string) (bool, error) {
+	mfa, err := u.mfaRepository.GetByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		u.log.Error(err, "failed to get user mfa")
+		return false, domain.ErrInternalServerError
+	}
+	return mfa.Enabled, nil
+}
+
+// checkMfaCode kode totp atau recovery code untuk user dengan 2fa aktif. kode salah pakai
+// counter & ban yang sama dengan login (key mfa:<userID>)
+func (u *userUseCase) checkMfaCode(ctx context.Context, userID string, req *domain.MfaCodeDTO) (*domain.UserMfa, error) {
+	banKey := "mfa:" + userID
+	delay, err := u.security.CheckBan(ctx, banKey)
+	if err != nil {
+		return nil, domain.ErrInternalServerError
+	}
+	if delay > 0 {
+		return nil, domain.ErrToomanyrequest
+	}
+
+	mfa, err := u.mfaRepository.GetByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrMfaNotEnabled
+		}
+		u.log.Error(err, "failed to get user mfa")
+		return nil, domain.ErrInternalServerError
+	}
+	if !mfa.Enabled {
+		return nil, domain.ErrMfaNotEnabled
+	}
+
+	var ok bool
+	if req.Code != "" {
+		ok, err = u.validateTotp(ctx, mfa, req.Code)
+	} else {
+		ok, err = u.mfaRepository.UseRecoveryCode(ctx, userID, security.HashRecoveryCode(req.RecoveryCode))
+		if err != nil {
+			u.log.Error(err, "failed to use recovery code")
+			err = domain.ErrInternalServerError
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		_, _ = u.security.IncrementAttempts(ctx, banKey)
+		return nil, domain.ErrInvalidMfaCode
+	}
+
+	if err := u.security.ResetLoginAttempts(ctx, banKey); err != nil {
+		u.log.Error(err, "failed to reset mfa attempts")
+	}
+	return mfa, nil
+}
+
+func (u *userUseCase) validateTotp(ctx context.Context, mfa *domain.UserMfa, code string) (bool, error) {
+	secret, err := u.security.DecryptSecret(mfa.TotpSecret)
+	if err != nil {
+		u.log.Error(err, "failed to decrypt totp secret")
+		return false, domain.ErrInternalServerError
+	}
+
+	ok, err := u.security.ValidateTotp(ctx, mfa.UserID, secret, code)
+	if err != nil {
+		return false, domain.ErrInternalServerError
+	}
+	return ok, nil
+}
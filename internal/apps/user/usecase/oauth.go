@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"booking/internal/domain"
+	"booking/pkg/constant"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// LoginWithOAuth identity yang sudah pernah login langsung dipakai. identity baru ditautkan ke user
+// yang punya email terverifikasi yang sama (email dari provider juga harus terverifikasi),
+// kalau gak ada dibuatkan user baru
+func (u *userUseCase) LoginWithOAuth(ctx context.Context, info *domain.OAuthUserInfo, device, userAgent, ipAddress string) (*domain.LoginResult, error) {
+	res, err := u.findOrCreateOAuthUser(ctx, info)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.issueLogin(ctx, res, domain.ClientTypeWeb, device, userAgent, ipAddress)
+}
+
+func (u *userUseCase) findOrCreateOAuthUser(ctx context.Context, info *domain.OAuthUserInfo) (*domain.UserWithIdentity, error) {
+	res, err := u.userRepository.GetByProviderID(ctx, info.Provider, info.ProviderID)
+	if err == nil {
+		return res, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		u.log.Error(err, "failed to get user by provider id")
+		return nil, domain.ErrInternalServerError
+	}
+
+	identityID, err := uuid.NewV7()
+	if err != nil {
+		u.log.Error(err, "failed to generate uuidv7 for user identity")
+		return nil, domain.ErrInternalServerError
+	}
+	identity := &domain.UserIdentity{
+		ID:         identityID.String(),
+		Provider:   info.Provider,
+		ProviderID: info.ProviderID,
+		Verified:   info.Email != "" && info.EmailVerified,
+	}
+	if info.Email != "" {
+		identity.Email = &info.Email
+	}
+
+	if identity.Verified {
+		owner, err := u.userRepository.GetByVerifiedEmail(ctx, info.Email)
+		if err == nil {
+			identity.UserID = owner.User.ID
+			res, err = u.userRepository.CreateIdentity(ctx, identity)
+			return u.oauthIdentityResult(ctx, info, res, err)
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			u.log.Error(err, "failed to get user by verified email")
+			return nil, domain.ErrInternalServerError
+		}
+	}
+
+	// email sudah dipakai akun yang belum terverifikasi (atau email dari provider belum terverifikasi),
+	// gak boleh ditautkan otomatis. user harus login dulu lalu tautkan manual
+	if info.Email != "" {
+		exists, err := u.userRepository.ExistsByEmail(ctx, info.Email)
+		if err != nil {
+			u.log.Error(err, "failed to check email")
+			return nil, domain.ErrInternalServerError
+		}
+		if exists {
+			return nil, domain.ErrUserAlreadyExists
+		}
+	}
+
+	userID, err := uuid.NewV7()
+	if err != nil {
+		u.log.Error(err, "failed to generate uuidv7 for user")
+		return nil, domain.ErrInternalServerError
+	}
+	identity.UserID = userID.String()
+	user := &domain.User{
+		ID:   userID.String(),
+		Name: oauthDisplayName(info),
+		Role: domain.RoleUser,
+	}
+	if info.ImageURL != "" {
+		user.ImageURL = &info.ImageURL
+	}
+
+	res, err = u.userRepository.CreateWithIdentity(ctx, user, identity)
+	return u.oauthIdentityResult(ctx, info, res, err)
+}
+
+// oauthIdentityResult callback yang sama bisa masuk bersamaan, yang kalah balapan pakai identity yang menang
+func (u *userUseCase) oauthIdentityResult(ctx context.Context, info *domain.OAuthUserInfo, res *domain.UserWithIdentity, err error) (*domain.UserWithIdentity, error) {
+	if err == nil {
+		return res, nil
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == constant.PgErrUniqueViolation {
+		existing, getErr := u.userRepository.GetByProviderID(ctx, info.Provider, info.ProviderID)
+		if getErr == nil {
+			return existing, nil
+		}
+		// user sudah punya identity lain dari provider yang sama
+		return nil, domain.ErrUserAlreadyExists
+	}
+
+	u.log.Error(err, "error creating oauth identity")
+	return nil, domain.ErrInternalServerError
+}
+
+func oauthDisplayName(info *domain.OAuthUserInfo) string {
+	name := strings.TrimSpace(info.Name)
+	if len(name) < 2 && info.Email != "" {
+		name, _, _ = strings.Cut(info.Email, "@")
+	}
+	if len(name) < 2 {
+		name = "User"
+	}
+	if r := []rune(name); len(r) > 100 {
+		name = string(r[:100])
+	}
+	return name
+}
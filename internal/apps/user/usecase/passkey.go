@@ -0,0 +1,259 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"booking/internal/domain"
+	"booking/pkg/constant"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const defaultPasskeyName = "Passkey"
+
+// BeginPasskeyRegistration tambah login method, jadi butuh login / re-auth yang masih baru
+func (u *userUseCase) BeginPasskeyRegistration(ctx context.Context, userID string) (*domain.PasskeyCeremony, error) {
+	if err := u.RequireRecentAuth(ctx); err != nil {
+		return nil, err
+	}
+
+	user := &passkeyUser{id: userID, name: userID}
+	if session, ok := domain.SessionFromContext(ctx); ok {
+		user.displayName = session.Name
+		if session.Email != "" {
+			user.name = session.Email
+		} else if session.Phone != "" {
+			user.name = session.Phone
+		}
+	}
+
+	// passkey yang sudah terdaftar ditolak authenticator, supaya gak dobel di device yang sama
+	credentials, err := u.passkeyRepository.ListByUserID(ctx, userID)
+	if err != nil {
+		u.log.Error(err, "failed to list passkeys")
+		return nil, domain.ErrInternalServerError
+	}
+	for i := range credentials {
+		user.credentials = append(user.credentials, toWebAuthnCredential(&credentials[i]))
+	}
+
+	creation, session, err := u.webAuthn.BeginRegistration(user,
+		webauthn.WithExclusions(webauthn.Credentials(user.credentials).CredentialDescriptors()),
+	)
+	if err != nil {
+		u.log.Error(err, "failed to begin passkey registration")
+		return nil, domain.ErrInternalServerError
+	}
+
+	return u.createPasskeyChallenge(ctx, domain.PasskeyCeremonyRegister, userID, creation, session)
+}
+
+func (u *userUseCase) FinishPasskeyRegistration(ctx context.Context, req *domain.PasskeyRegisterDTO) (*domain.PasskeyCredential, error) {
+	session, err := u.consumePasskeyChallenge(ctx, req.ChallengeToken, domain.PasskeyCeremonyRegister, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(req.Credential)
+	if err != nil {
+		u.log.Debugf("invalid passkey attestation: %v", err)
+		return nil, domain.ErrPasskeyFailed
+	}
+	credential, err := u.webAuthn.CreateCredential(&passkeyUser{id: req.UserID}, *session, parsed)
+	if err != nil {
+		u.log.Debugf("passkey registration rejected: %v", err)
+		return nil, domain.ErrPasskeyFailed
+	}
+
+	name := req.Name
+	if name == "" {
+		name = defaultPasskeyName
+	}
+	transports := make([]string, 0, len(credential.Transport))
+	for _, t := range credential.Transport {
+		transports = append(transports, string(t))
+	}
+
+	res := &domain.PasskeyCredential{
+		ID:              credential.ID,
+		UserID:          req.UserID,
+		Name:            name,
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		AAGUID:          credential.Authenticator.AAGUID,
+		SignCount:       int64(credential.Authenticator.SignCount),
+		Transports:      strings.Join(transports, ","),
+		BackupEligible:  credential.Flags.BackupEligible,
+		BackupState:     credential.Flags.BackupState,
+	}
+	if err := u.passkeyRepository.Create(ctx, res); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == constant.PgErrUniqueViolation {
+			return nil, domain.ErrPasskeyAlreadyRegistered
+		}
+		u.log.Error(err, "failed to save passkey")
+		return nil, domain.ErrInternalServerError
+	}
+
+	return res, nil
+}
+
+// BeginPasskeyLogin tanpa email / username, authenticator yang menawarkan passkey yang tersimpan untuk RP ini
+func (u *userUseCase) BeginPasskeyLogin(ctx context.Context) (*domain.PasskeyCeremony, error) {
+	assertion, session, err := u.webAuthn.BeginDiscoverableLogin()
+	if err != nil {
+		u.log.Error(err, "failed to begin passkey login")
+		return nil, domain.ErrInternalServerError
+	}
+
+	return u.createPasskeyChallenge(ctx, domain.PasskeyCeremonyLogin, "", assertion, session)
+}
+
+// FinishPasskeyLogin passkey sudah dua faktor (device + pin / biometrik), jadi gak lewat challenge 2fa totp
+func (u *userUseCase) FinishPasskeyLogin(ctx context.Context, req *domain.PasskeyLoginDTO) (*domain.LoginResult, error) {
+	session, err := u.consumePasskeyChallenge(ctx, req.ChallengeToken, domain.PasskeyCeremonyLogin, "")
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(req.Credential)
+	if err != nil {
+		u.log.Debugf("invalid passkey assertion: %v", err)
+		return nil, domain.ErrPasskeyFailed
+	}
+
+	var (
+		stored    *domain.PasskeyCredential
+		lookupErr error
+	)
+	handler := func(rawID, userHandle []byte) (webauthn.User, error) {
+		stored, lookupErr = u.passkeyRepository.GetByID(ctx, rawID)
+		if lookupErr != nil {
+			return nil, lookupErr
+		}
+		// user handle dari authenticator harus pemilik credential nya
+		if string(userHandle) != stored.UserID {
+			return nil, domain.ErrPasskeyFailed
+		}
+		return &passkeyUser{
+			id:          stored.UserID,
+			credentials: []webauthn.Credential{toWebAuthnCredential(stored)},
+		}, nil
+	}
+
+	_, credential, err := u.webAuthn.ValidatePasskeyLogin(handler, *session, parsed)
+	if err != nil {
+		if lookupErr != nil && !errors.Is(lookupErr, sql.ErrNoRows) {
+			u.log.Error(lookupErr, "failed to get passkey")
+			return nil, domain.ErrInternalServerError
+		}
+		u.log.Debugf("passkey login rejected: %v", err)
+		return nil, domain.ErrPasskeyFailed
+	}
+	if credential.Authenticator.CloneWarning {
+		u.log.Warnf("passkey sign counter did not increase, possible cloned authenticator (user %s)", stored.UserID)
+		return nil, domain.ErrPasskeyFailed
+	}
+
+	// dicek ulang di db, dua assertion dengan counter yang sama cuma satu yang lolos
+	ok, err := u.passkeyRepository.UpdateSignCount(ctx, stored.ID, int64(credential.Authenticator.SignCount), credential.Flags.BackupState)
+	if err != nil {
+		u.log.Error(err, "failed to update passkey sign count")
+		return nil, domain.ErrInternalServerError
+	}
+	if !ok {
+		u.log.Warnf("passkey sign counter replayed (user %s)", stored.UserID)
+		return nil, domain.ErrPasskeyFailed
+	}
+
+	user, err := u.GetByIdentityID(ctx, stored.IdentityID)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.createLogin(ctx, user, req.ClientType, req.Device, req.UserAgent, req.IpAddress)
+}
+
+// =============================
+// HELPERS
+// =============================
+
+// passkeyUser user handle = user id (uuid string, 36 byte), jadi bisa langsung dibandingkan dengan credential di db
+type passkeyUser struct {
+	id          string
+	name        string
+	displayName string
+	credentials []webauthn.Credential
+}
+
+func (p *passkeyUser) WebAuthnID() []byte                         { return []byte(p.id) }
+func (p *passkeyUser) WebAuthnName() string                       { return p.name }
+func (p *passkeyUser) WebAuthnDisplayName() string                { return p.displayName }
+func (p *passkeyUser) WebAuthnCredentials() []webauthn.Credential { return p.credentials }
+
+func toWebAuthnCredential(c *domain.PasskeyCredential) webauthn.Credential {
+	var transports []protocol.AuthenticatorTransport
+	for _, t := range strings.Split(c.Transports, ",") {
+		if t != "" {
+			transports = append(transports, protocol.AuthenticatorTransport(t))
+		}
+	}
+
+	return webauthn.Credential{
+		ID:              c.ID,
+		PublicKey:       c.PublicKey,
+		AttestationType: c.AttestationType,
+		Transport:       transports,
+		Flags: webauthn.CredentialFlags{
+			BackupEligible: c.BackupEligible,
+			BackupState:    c.BackupState,
+		},
+		Authenticator: webauthn.Authenticator{
+			AAGUID:    c.AAGUID,
+			SignCount: uint32(c.SignCount),
+		},
+	}
+}
+
+func (u *userUseCase) createPasskeyChallenge(ctx context.Context, ceremony, userID string, options any, session *webauthn.SessionData) (*domain.PasskeyCeremony, error) {
+	data, err := json.Marshal(session)
+	if err != nil {
+		u.log.Error(err, "failed to marshal passkey session")
+		return nil, domain.ErrInternalServerError
+	}
+
+	token, err := u.security.CreatePasskeyChallenge(ctx, ceremony, userID, data)
+	if err != nil {
+		return nil, domain.ErrInternalServerError
+	}
+
+	return &domain.PasskeyCeremony{ChallengeToken: token, Options: options}, nil
+}
+
+// consumePasskeyChallenge challenge registrasi cuma berlaku untuk user yang memulainya
+func (u *userUseCase) consumePasskeyChallenge(ctx context.Context, token, ceremony, userID string) (*webauthn.SessionData, error) {
+	challengeUserID, data, err := u.security.ConsumePasskeyChallenge(ctx, token, ceremony)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidPasskeyChallenge) {
+			return nil, err
+		}
+		return nil, domain.ErrInternalServerError
+	}
+	if challengeUserID != userID {
+		return nil, domain.ErrInvalidPasskeyChallenge
+	}
+
+	var session webauthn.SessionData
+	if err := json.Unmarshal(data, &session); err != nil {
+		u.log.Error(err, "failed to unmarshal passkey session")
+		return nil, domain.ErrInternalServerError
+	}
+
+	return &session, nil
+}
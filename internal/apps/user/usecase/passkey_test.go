@@ -0,0 +1,520 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"booking/internal/domain"
+	"booking/pkg/config"
+	"booking/pkg/constant"
+	"booking/pkg/logger"
+	"booking/pkg/passkey"
+	"booking/pkg/security"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/redis/go-redis/v9"
+)
+
+// harness passkey: challenge di miniredis, credential & user di memory, browser + authenticator diganti
+// authenticator software (ES256, attestation none) yang menandatangani ceremony seperti authenticator asli
+
+const testRPOrigin = "http://localhost:3000"
+
+type memPasskeyRepository struct {
+	mu          sync.Mutex
+	credentials map[string]domain.PasskeyCredential
+
+	// dipanggil sebelum UpdateSignCount, untuk simulasi login lain dengan counter yang sama commit duluan
+	beforeUpdate func()
+}
+
+func (r *memPasskeyRepository) GetByID(ctx context.Context, credentialID []byte) (*domain.PasskeyCredential, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c, ok := r.credentials[string(credentialID)]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &c, nil
+}
+
+func (r *memPasskeyRepository) ListByUserID(ctx context.Context, userID string) ([]domain.PasskeyCredential, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	res := []domain.PasskeyCredential{}
+	for _, c := range r.credentials {
+		if c.UserID == userID {
+			res = append(res, c)
+		}
+	}
+	return res, nil
+}
+
+func (r *memPasskeyRepository) Create(ctx context.Context, credential *domain.PasskeyCredential) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.credentials[string(credential.ID)]; ok {
+		return &pgconn.PgError{Code: constant.PgErrUniqueViolation}
+	}
+	credential.IdentityID = testIdentityID(credential.UserID)
+	r.credentials[string(credential.ID)] = *credential
+	return nil
+}
+
+// UpdateSignCount kondisi nya sama dengan query di repository postgres
+func (r *memPasskeyRepository) UpdateSignCount(ctx context.Context, credentialID []byte, signCount int64, backupState bool) (bool, error) {
+	if r.beforeUpdate != nil {
+		r.beforeUpdate()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c, ok := r.credentials[string(credentialID)]
+	if !ok || !(c.SignCount < signCount || (c.SignCount == 0 && signCount == 0)) {
+		return false, nil
+	}
+	c.SignCount = signCount
+	c.BackupState = backupState
+	r.credentials[string(credentialID)] = c
+	return true, nil
+}
+
+func (r *memPasskeyRepository) setSignCount(credentialID []byte, signCount int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c := r.credentials[string(credentialID)]
+	c.SignCount = signCount
+	r.credentials[string(credentialID)] = c
+}
+
+func (r *memPasskeyRepository) signCount(credentialID []byte) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.credentials[string(credentialID)].SignCount
+}
+
+// memUserRepository cuma GetByIdentityID yang dipakai login passkey, method lain panic kalau terpanggil
+type memUserRepository struct {
+	domain.UserRepository
+	users map[string]*domain.UserWithIdentity // key identity id
+}
+
+func (r *memUserRepository) GetByIdentityID(ctx context.Context, identityID string) (*domain.UserWithIdentity, error) {
+	u, ok := r.users[identityID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return u, nil
+}
+
+func testIdentityID(userID string) string {
+	return "passkey-" + userID
+}
+
+type passkeyHarness struct {
+	usecase  *userUseCase
+	security *security.Security
+	passkeys *memPasskeyRepository
+	users    *memUserRepository
+}
+
+func newPasskeyHarness(t *testing.T) *passkeyHarness {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	cfg := &config.Config{
+		App: config.App{
+			AuthSessionTtl:  time.Hour,
+			AuthSessionsTtl: time.Hour,
+			ReauthTtl:       5 * time.Minute,
+		},
+		Mfa: config.MfaConfig{EncryptionKey: base64.StdEncoding.EncodeToString(make([]byte, 32))},
+		WebAuthn: config.WebAuthnConfig{
+			RPID:          "localhost",
+			RPDisplayName: "Booking",
+			RPOrigins:     []string{testRPOrigin},
+			ChallengeTtl:  5 * time.Minute,
+		},
+	}
+	log := logger.Logger{} // zero value = gak menulis apa-apa
+
+	h := &passkeyHarness{
+		security: security.NewSecurity(cfg, rdb, log),
+		passkeys: &memPasskeyRepository{credentials: map[string]domain.PasskeyCredential{}},
+		users:    &memUserRepository{users: map[string]*domain.UserWithIdentity{}},
+	}
+	h.usecase = NewUserUseCase(h.users, nil, h.passkeys, h.security, passkey.NewWebAuthn(&cfg.WebAuthn, log), nil, nil, cfg, log).(*userUseCase)
+	return h
+}
+
+func (h *passkeyHarness) addUser(t *testing.T) string {
+	t.Helper()
+
+	id := uuid.NewString()
+	h.users.users[testIdentityID(id)] = &domain.UserWithIdentity{
+		User:         domain.User{ID: id, Name: "Passkey User", Role: domain.RoleUser},
+		UserIdentity: domain.UserIdentity{ID: testIdentityID(id), UserID: id, Provider: domain.ProviderPasskey, ProviderID: id, Verified: true},
+	}
+	return id
+}
+
+// register login web yang baru (re-auth masih berlaku) lalu daftarkan passkey authenticator untuk user nya
+func (h *passkeyHarness) register(t *testing.T, userID string, auth *virtualAuthenticator) *domain.PasskeyCredential {
+	t.Helper()
+
+	ctx := h.recentAuthContext(t, userID)
+	ceremony, err := h.usecase.BeginPasskeyRegistration(ctx, userID)
+	if err != nil {
+		t.Fatalf("BeginPasskeyRegistration() error = %v", err)
+	}
+
+	res, err := h.usecase.FinishPasskeyRegistration(ctx, &domain.PasskeyRegisterDTO{
+		ChallengeToken: ceremony.ChallengeToken,
+		Credential:     auth.create(t, ceremony),
+		UserID:         userID,
+	})
+	if err != nil {
+		t.Fatalf("FinishPasskeyRegistration() error = %v", err)
+	}
+	return res
+}
+
+func (h *passkeyHarness) recentAuthContext(t *testing.T, userID string) context.Context {
+	t.Helper()
+
+	token := "session-" + userID
+	if err := h.security.MarkRecentAuth(context.Background(), token); err != nil {
+		t.Fatalf("MarkRecentAuth() error = %v", err)
+	}
+	return context.WithValue(context.Background(), domain.SessionTokenCtxKey, token)
+}
+
+func (h *passkeyHarness) beginLogin(t *testing.T) *domain.PasskeyCeremony {
+	t.Helper()
+
+	ceremony, err := h.usecase.BeginPasskeyLogin(context.Background())
+	if err != nil {
+		t.Fatalf("BeginPasskeyLogin() error = %v", err)
+	}
+	return ceremony
+}
+
+func (h *passkeyHarness) finishLogin(ceremony *domain.PasskeyCeremony, credential json.RawMessage) (*domain.LoginResult, error) {
+	return h.usecase.FinishPasskeyLogin(context.Background(), &domain.PasskeyLoginDTO{
+		ChallengeToken: ceremony.ChallengeToken,
+		Credential:     credential,
+		ClientType:     domain.ClientTypeWeb,
+	})
+}
+
+// virtualAuthenticator passkey discoverable dengan user verification, counter naik tiap assertion
+type virtualAuthenticator struct {
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+	userHandle   []byte
+	signCount    uint32
+}
+
+func newVirtualAuthenticator(t *testing.T, userID string) *virtualAuthenticator {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	credentialID := make([]byte, 16)
+	if _, err := rand.Read(credentialID); err != nil {
+		t.Fatal(err)
+	}
+	return &virtualAuthenticator{key: key, credentialID: credentialID, userHandle: []byte(userID)}
+}
+
+// create respon navigator.credentials.create, attestation none
+func (a *virtualAuthenticator) create(t *testing.T, ceremony *domain.PasskeyCeremony) json.RawMessage {
+	t.Helper()
+
+	options, ok := ceremony.Options.(*protocol.CredentialCreation)
+	if !ok {
+		t.Fatalf("registration options = %T, want *protocol.CredentialCreation", ceremony.Options)
+	}
+
+	pub, err := a.key.PublicKey.ECDH()
+	if err != nil {
+		t.Fatal(err)
+	}
+	point := pub.Bytes() // 0x04 || x || y
+	coseKey, err := webauthncbor.Marshal(webauthncose.EC2PublicKeyData{
+		PublicKeyData: webauthncose.PublicKeyData{
+			KeyType:   int64(webauthncose.EllipticKey),
+			Algorithm: int64(webauthncose.AlgES256),
+		},
+		Curve:  int64(webauthncose.P256),
+		XCoord: point[1:33],
+		YCoord: point[33:],
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authData := a.authData(protocol.FlagAttestedCredentialData, 0)
+	authData = append(authData, make([]byte, 16)...) // aaguid kosong
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(a.credentialID)))
+	authData = append(authData, a.credentialID...)
+	authData = append(authData, coseKey...)
+
+	attestation, err := webauthncbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": authData,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return marshalCredential(t, a.credentialID, map[string]any{
+		"clientDataJSON":    clientData(t, protocol.CreateCeremony, options.Response.Challenge),
+		"attestationObject": protocol.URLEncodedBase64(attestation),
+	})
+}
+
+// get respon navigator.credentials.get dengan counter berikutnya
+func (a *virtualAuthenticator) get(t *testing.T, ceremony *domain.PasskeyCeremony) json.RawMessage {
+	t.Helper()
+
+	a.signCount++
+	return a.assert(t, ceremony, a.signCount, a.userHandle)
+}
+
+// assert assertion dengan counter & user handle bebas, untuk replay / user handle palsu
+func (a *virtualAuthenticator) assert(t *testing.T, ceremony *domain.PasskeyCeremony, signCount uint32, userHandle []byte) json.RawMessage {
+	t.Helper()
+
+	options, ok := ceremony.Options.(*protocol.CredentialAssertion)
+	if !ok {
+		t.Fatalf("login options = %T, want *protocol.CredentialAssertion", ceremony.Options)
+	}
+
+	clientDataJSON := clientData(t, protocol.AssertCeremony, options.Response.Challenge)
+	authData := a.authData(0, signCount)
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	digest := sha256.Sum256(append(bytes.Clone(authData), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return marshalCredential(t, a.credentialID, map[string]any{
+		"clientDataJSON":    protocol.URLEncodedBase64(clientDataJSON),
+		"authenticatorData": protocol.URLEncodedBase64(authData),
+		"signature":         protocol.URLEncodedBase64(signature),
+		"userHandle":        protocol.URLEncodedBase64(userHandle),
+	})
+}
+
+// authData rpIdHash || flags (user present + verified) || sign count
+func (a *virtualAuthenticator) authData(flags protocol.AuthenticatorFlags, signCount uint32) []byte {
+	rpIDHash := sha256.Sum256([]byte("localhost"))
+	res := append(rpIDHash[:], byte(protocol.FlagUserPresent|protocol.FlagUserVerified|flags))
+	return binary.BigEndian.AppendUint32(res, signCount)
+}
+
+func clientData(t *testing.T, ceremony protocol.CeremonyType, challenge protocol.URLEncodedBase64) protocol.URLEncodedBase64 {
+	t.Helper()
+
+	res, err := json.Marshal(protocol.CollectedClientData{
+		Type:      ceremony,
+		Challenge: base64.RawURLEncoding.EncodeToString(challenge),
+		Origin:    testRPOrigin,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func marshalCredential(t *testing.T, credentialID []byte, response map[string]any) json.RawMessage {
+	t.Helper()
+
+	res, err := json.Marshal(map[string]any{
+		"id":       protocol.URLEncodedBase64(credentialID),
+		"rawId":    protocol.URLEncodedBase64(credentialID),
+		"type":     "public-key",
+		"response": response,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func TestPasskey_RegisterThenLogin(t *testing.T) {
+	h := newPasskeyHarness(t)
+	userID := h.addUser(t)
+	auth := newVirtualAuthenticator(t, userID)
+
+	credential := h.register(t, userID, auth)
+	if !bytes.Equal(credential.ID, auth.credentialID) || credential.UserID != userID || credential.Name != defaultPasskeyName {
+		t.Fatalf("registered credential = %+v", credential)
+	}
+
+	for i := 1; i <= 2; i++ {
+		ceremony := h.beginLogin(t)
+		res, err := h.finishLogin(ceremony, auth.get(t, ceremony))
+		if err != nil {
+			t.Fatalf("login %d error = %v", i, err)
+		}
+		if res.User.User.ID != userID || res.SessionToken == "" {
+			t.Fatalf("login %d result = %+v", i, res)
+		}
+		if got := h.passkeys.signCount(auth.credentialID); got != int64(auth.signCount) {
+			t.Fatalf("stored sign count = %d, want %d", got, auth.signCount)
+		}
+	}
+}
+
+func TestPasskeyRegistration_ChallengeBoundToUser(t *testing.T) {
+	h := newPasskeyHarness(t)
+	owner := h.addUser(t)
+	other := h.addUser(t)
+
+	ctx := h.recentAuthContext(t, owner)
+	ceremony, err := h.usecase.BeginPasskeyRegistration(ctx, owner)
+	if err != nil {
+		t.Fatalf("BeginPasskeyRegistration() error = %v", err)
+	}
+
+	_, err = h.usecase.FinishPasskeyRegistration(ctx, &domain.PasskeyRegisterDTO{
+		ChallengeToken: ceremony.ChallengeToken,
+		Credential:     newVirtualAuthenticator(t, other).create(t, ceremony),
+		UserID:         other,
+	})
+	if !errors.Is(err, domain.ErrInvalidPasskeyChallenge) {
+		t.Fatalf("FinishPasskeyRegistration() error = %v, want ErrInvalidPasskeyChallenge", err)
+	}
+}
+
+func TestPasskeyLogin_UserHandleMismatch(t *testing.T) {
+	h := newPasskeyHarness(t)
+	owner := h.addUser(t)
+	other := h.addUser(t)
+	auth := newVirtualAuthenticator(t, owner)
+	h.register(t, owner, auth)
+
+	// assertion valid dari credential milik owner, tapi authenticator mengaku sebagai user lain
+	ceremony := h.beginLogin(t)
+	_, err := h.finishLogin(ceremony, auth.assert(t, ceremony, 1, []byte(other)))
+	if !errors.Is(err, domain.ErrPasskeyFailed) {
+		t.Fatalf("FinishPasskeyLogin() error = %v, want ErrPasskeyFailed", err)
+	}
+	if got := h.passkeys.signCount(auth.credentialID); got != 0 {
+		t.Fatalf("stored sign count = %d after rejected login, want 0", got)
+	}
+}
+
+func TestPasskeyLogin_SignCountReplay(t *testing.T) {
+	t.Run("counter not increased", func(t *testing.T) {
+		h := newPasskeyHarness(t)
+		userID := h.addUser(t)
+		auth := newVirtualAuthenticator(t, userID)
+		h.register(t, userID, auth)
+
+		ceremony := h.beginLogin(t)
+		if _, err := h.finishLogin(ceremony, auth.get(t, ceremony)); err != nil {
+			t.Fatalf("first login error = %v", err)
+		}
+
+		// authenticator clone mengirim counter yang sama untuk challenge baru
+		ceremony = h.beginLogin(t)
+		_, err := h.finishLogin(ceremony, auth.assert(t, ceremony, auth.signCount, auth.userHandle))
+		if !errors.Is(err, domain.ErrPasskeyFailed) {
+			t.Fatalf("replayed counter error = %v, want ErrPasskeyFailed", err)
+		}
+	})
+
+	t.Run("concurrent login committed first", func(t *testing.T) {
+		h := newPasskeyHarness(t)
+		userID := h.addUser(t)
+		auth := newVirtualAuthenticator(t, userID)
+		h.register(t, userID, auth)
+
+		// counter di db masih 0 saat assertion divalidasi, login lain dengan counter 1 commit sebelum update
+		h.passkeys.beforeUpdate = func() { h.passkeys.setSignCount(auth.credentialID, 1) }
+
+		ceremony := h.beginLogin(t)
+		_, err := h.finishLogin(ceremony, auth.assert(t, ceremony, 1, auth.userHandle))
+		if !errors.Is(err, domain.ErrPasskeyFailed) {
+			t.Fatalf("FinishPasskeyLogin() error = %v, want ErrPasskeyFailed when UpdateSignCount returns false", err)
+		}
+	})
+}
+
+func TestPasskeyLogin_ChallengeSingleUse(t *testing.T) {
+	h := newPasskeyHarness(t)
+	userID := h.addUser(t)
+	auth := newVirtualAuthenticator(t, userID)
+	h.register(t, userID, auth)
+
+	t.Run("reused after success", func(t *testing.T) {
+		ceremony := h.beginLogin(t)
+		if _, err := h.finishLogin(ceremony, auth.get(t, ceremony)); err != nil {
+			t.Fatalf("first login error = %v", err)
+		}
+
+		_, err := h.finishLogin(ceremony, auth.get(t, ceremony))
+		if !errors.Is(err, domain.ErrInvalidPasskeyChallenge) {
+			t.Fatalf("reused challenge error = %v, want ErrInvalidPasskeyChallenge", err)
+		}
+	})
+
+	t.Run("burned by failed attempt", func(t *testing.T) {
+		ceremony := h.beginLogin(t)
+		if _, err := h.finishLogin(ceremony, auth.assert(t, ceremony, auth.signCount+1, []byte("someone-else"))); !errors.Is(err, domain.ErrPasskeyFailed) {
+			t.Fatalf("failed attempt error = %v, want ErrPasskeyFailed", err)
+		}
+
+		_, err := h.finishLogin(ceremony, auth.get(t, ceremony))
+		if !errors.Is(err, domain.ErrInvalidPasskeyChallenge) {
+			t.Fatalf("retry on burned challenge error = %v, want ErrInvalidPasskeyChallenge", err)
+		}
+	})
+
+	t.Run("registration challenge used for login", func(t *testing.T) {
+		ceremony, err := h.usecase.BeginPasskeyRegistration(h.recentAuthContext(t, userID), userID)
+		if err != nil {
+			t.Fatalf("BeginPasskeyRegistration() error = %v", err)
+		}
+
+		login := h.beginLogin(t)
+		login.ChallengeToken = ceremony.ChallengeToken
+		_, err = h.finishLogin(login, auth.get(t, login))
+		if !errors.Is(err, domain.ErrInvalidPasskeyChallenge) {
+			t.Fatalf("FinishPasskeyLogin() error = %v, want ErrInvalidPasskeyChallenge", err)
+		}
+	})
+}
@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"booking/internal/domain"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ForgotPassword email yang gak terdaftar / gak punya password (login sosial) diam-diam diabaikan.
+// error cuma untuk log, handler tetap balas sukses
+func (u *userUseCase) ForgotPassword(ctx context.Context, req *domain.ForgotPasswordDTO) error {
+	wait, err := u.security.AllowPasswordResetRequest(ctx, req.Email)
+	if err != nil {
+		return err
+	}
+	if wait > 0 {
+		return nil
+	}
+
+	user, err := u.userRepository.GetByEmail(ctx, req.Email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	if user.UserIdentity.PasswordHash == nil {
+		return nil
+	}
+
+	token, err := u.security.CreatePasswordResetToken(ctx, user.User.ID, user.UserIdentity.ID, req.Email)
+	if err != nil {
+		return err
+	}
+
+	q := url.Values{}
+	q.Set("token", token)
+	q.Set("email", req.Email)
+	link := fmt.Sprintf("%s?%s", u.config.App.PasswordResetURL, q.Encode())
+	return u.mailer.Send(ctx, &domain.Email{
+		To:      req.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nWe received a request to reset your password. Open the link below to choose a new one:\n%s\n\nThe link expires in %s. If you did not request this, you can ignore this email.\n",
+			user.User.Name, link, u.config.App.PasswordResetTtl,
+		),
+	})
+}
+
+// ResetPassword token yang salah dihitung sebagai percobaan login gagal. setelah berhasil
+// semua session & refresh token user dicabut, jadi session yang dicuri ikut mati
+func (u *userUseCase) ResetPassword(ctx context.Context, req *domain.ResetPasswordDTO) error {
+	userID, identityID, err := u.security.ConsumePasswordResetToken(ctx, req.Token, req.Email)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidResetToken) {
+			_, _ = u.security.IncrementAttempts(ctx, req.Email)
+			return err
+		}
+		return domain.ErrInternalServerError
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		u.log.Error(err, "failed to hash password")
+		return domain.ErrInternalServerError
+	}
+
+	if err := u.userRepository.UpdatePassword(ctx, identityID, string(hashedPassword)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrInvalidResetToken
+		}
+		u.log.Error(err, "failed to update password")
+		return domain.ErrInternalServerError
+	}
+
+	if err := u.security.LogoutAllSessions(ctx, userID); err != nil {
+		u.log.Error(err, "failed to logout all sessions after password reset")
+		return domain.ErrInternalServerError
+	}
+	if err := u.security.ResetLoginAttempts(ctx, req.Email); err != nil {
+		u.log.Error(err, "failed to reset login attempts")
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"booking/internal/domain"
+	"booking/pkg/constant"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// RequestPhoneOtp selalu sukses walaupun nomornya belum terdaftar (login sekaligus daftar)
+func (u *userUseCase) RequestPhoneOtp(ctx context.Context, req *domain.PhoneOtpRequestDTO) error {
+	delay, err := u.security.CheckBan(ctx, req.Phone)
+	if err != nil {
+		return domain.ErrInternalServerError
+	}
+	if delay > 0 {
+		return domain.ErrToomanyrequest
+	}
+
+	wait, err := u.security.AllowPhoneOtpRequest(ctx, req.Phone)
+	if err != nil {
+		return domain.ErrInternalServerError
+	}
+	if wait > 0 {
+		return domain.ErrToomanyrequest
+	}
+
+	code, err := u.security.CreatePhoneOtp(ctx, req.Phone)
+	if err != nil {
+		return domain.ErrInternalServerError
+	}
+
+	message := fmt.Sprintf("Your login code is %s. It expires in %s. Do not share this code with anyone.", code, u.config.App.PhoneOtpTtl)
+	if err := u.smsSender.Send(ctx, req.Phone, message); err != nil {
+		u.log.Error(err, "failed to send phone otp")
+		return domain.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// LoginWithPhone kode salah dihitung ke ban login per nomor (IncrementAttempts)
+func (u *userUseCase) LoginWithPhone(ctx context.Context, req *domain.PhoneLoginDTO) (*domain.LoginResult, error) {
+	ok, err := u.security.VerifyPhoneOtp(ctx, req.Phone, req.Code)
+	if err != nil {
+		return nil, domain.ErrInternalServerError
+	}
+	if !ok {
+		_, _ = u.security.IncrementAttempts(ctx, req.Phone)
+		return nil, domain.ErrInvalidOtp
+	}
+	if err := u.security.ResetLoginAttempts(ctx, req.Phone); err != nil {
+		return nil, domain.ErrInternalServerError
+	}
+
+	res, err := u.findOrCreatePhoneUser(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.issueLogin(ctx, res, req.ClientType, req.Device, req.UserAgent, req.IpAddress)
+}
+
+func (u *userUseCase) findOrCreatePhoneUser(ctx context.Context, req *domain.PhoneLoginDTO) (*domain.UserWithIdentity, error) {
+	res, err := u.userRepository.GetByProviderID(ctx, domain.ProviderPhone, req.Phone)
+	if err == nil {
+		return res, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		u.log.Error(err, "failed to get user by phone")
+		return nil, domain.ErrInternalServerError
+	}
+
+	userID, err := uuid.NewV7()
+	if err != nil {
+		u.log.Error(err, "failed to generate uuidv7 for user")
+		return nil, domain.ErrInternalServerError
+	}
+	identityID, err := uuid.NewV7()
+	if err != nil {
+		u.log.Error(err, "failed to generate uuidv7 for user identity")
+		return nil, domain.ErrInternalServerError
+	}
+
+	name := req.Name
+	if name == "" {
+		name = "User"
+	}
+	// nomor sudah terbukti milik user karena kode otp nya benar
+	res, err = u.userRepository.CreateWithIdentity(ctx, &domain.User{
+		ID:   userID.String(),
+		Name: name,
+		Role: domain.RoleUser,
+	}, &domain.UserIdentity{
+		ID:         identityID.String(),
+		UserID:     userID.String(),
+		Provider:   domain.ProviderPhone,
+		ProviderID: req.Phone,
+		Phone:      &req.Phone,
+		Verified:   true,
+	})
+	if err != nil {
+		// login pertama dari dua request bersamaan
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == constant.PgErrUniqueViolation {
+			if existing, err := u.userRepository.GetByProviderID(ctx, domain.ProviderPhone, req.Phone); err == nil {
+				return existing, nil
+			}
+		}
+		u.log.Error(err, "error creating phone user")
+		return nil, domain.ErrInternalServerError
+	}
+
+	return res, nil
+}
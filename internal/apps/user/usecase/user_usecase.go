@@ -6,26 +6,50 @@ import (
 	"errors"
 
 	"booking/internal/domain"
+	"booking/pkg/config"
 	"booking/pkg/constant"
 	"booking/pkg/logger"
 	"booking/pkg/security"
 
+	"github.com/go-webauthn/webauthn/webauthn"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgconn"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type userUseCase struct {
-	security       *security.Security
-	userRepository domain.UserRepository
-	log            logger.Logger
+	security          *security.Security
+	userRepository    domain.UserRepository
+	mailer            domain.Mailer
+	smsSender         domain.SmsSender
+	mfaRepository     domain.MfaRepository
+	passkeyRepository domain.PasskeyRepository
+	webAuthn          *webauthn.WebAuthn
+	config            *config.Config
+	log               logger.Logger
 }
 
-func NewUserUseCase(userRepository domain.UserRepository, security *security.Security, log logger.Logger) domain.UserUsecase {
+func NewUserUseCase(
+	userRepository domain.UserRepository,
+	mfaRepository domain.MfaRepository,
+	passkeyRepository domain.PasskeyRepository,
+	security *security.Security,
+	webAuthn *webauthn.WebAuthn,
+	mailer domain.Mailer,
+	smsSender domain.SmsSender,
+	config *config.Config,
+	log logger.Logger,
+) domain.UserUsecase {
 	return &userUseCase{
-		userRepository: userRepository,
-		security:       security,
-		log:            log,
+		userRepository:    userRepository,
+		mfaRepository:     mfaRepository,
+		passkeyRepository: passkeyRepository,
+		webAuthn:          webAuthn,
+		security:          security,
+		mailer:            mailer,
+		smsSender:         smsSender,
+		config:            config,
+		log:               log,
 	}
 }
 
@@ -42,6 +66,19 @@ func (u *userUseCase) GetByEmail(ctx context.Context, email string) (*domain.Use
 	return res, nil
 }
 
+func (u *userUseCase) GetByIdentityID(ctx context.Context, identityID string) (*domain.UserWithIdentity, error) {
+	res, err := u.userRepository.GetByIdentityID(ctx, identityID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			u.log.Debug("User not found")
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, domain.ErrInternalServerError
+	}
+
+	return res, nil
+}
+
 func (u *userUseCase) RegisterUser(ctx context.Context, req *domain.RegisterDTO) (*domain.UserWithIdentity, error) {
 	userId, err := uuid.NewV7()
 	if err != nil {
@@ -65,7 +102,7 @@ func (u *userUseCase) RegisterUser(ctx context.Context, req *domain.RegisterDTO)
 		ID:         userId.String(),
 		Name:       req.Name,
 		ImageURL:   "",
-		Role:       "user",
+		Role:       domain.RoleUser,
 		IdIdentity: identityId.String(),
 		Email:      req.Email,
 		Password:   string(hashedPassword),
@@ -84,39 +121,99 @@ func (u *userUseCase) RegisterUser(ctx context.Context, req *domain.RegisterDTO)
 		return nil, err
 	}
 
+	// user sudah terdaftar, gagal kirim email cukup di-log. user bisa minta kirim ulang
+	if err := u.sendVerificationEmail(ctx, res); err != nil {
+		u.log.Error(err, "failed to send verification email")
+	}
+
 	return res, nil
 }
 
-func (u *userUseCase) Login(ctx context.Context, req *domain.LoginDTO) (*domain.UserWithIdentity, string, error) {
+func (u *userUseCase) Login(ctx context.Context, req *domain.LoginDTO) (*domain.LoginResult, error) {
 	// get user by email
 	res, err := u.userRepository.GetByEmail(ctx, req.Email)
 	if err != nil {
 		u.log.Error(err, "failed to get user by email")
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, "", domain.ErrInvalidCredentials
+			return nil, domain.ErrInvalidCredentials
 		}
-		return nil, "", err
+		return nil, err
 	}
 
 	// compare password
 	if res.UserIdentity.PasswordHash == nil {
-		return nil, "", domain.ErrInvalidCredentials
+		return nil, domain.ErrInvalidCredentials
 	}
 	err = bcrypt.CompareHashAndPassword([]byte(*res.UserIdentity.PasswordHash), []byte(req.Password))
 	if err != nil {
 		_, _ = u.security.IncrementAttempts(ctx, req.Email)
-		return nil, "", domain.ErrInvalidCredentials
+		return nil, domain.ErrInvalidCredentials
 	}
 	if err := u.security.ResetLoginAttempts(ctx, req.Email); err != nil {
-		return nil, "", domain.ErrInternalServerError
+		return nil, domain.ErrInternalServerError
+	}
+
+	return u.issueLogin(ctx, res, req.ClientType, req.Device, req.UserAgent, req.IpAddress)
+}
+
+// issueLogin dipakai semua jalur login. user dengan 2fa aktif cuma dapat mfa challenge,
+// session / token baru dibuat setelah kode 2fa benar (CompleteMfaLogin).
+// akun nonaktif ditolak setelah kredensial benar, supaya status akun gak bocor ke orang lain
+func (u *userUseCase) issueLogin(ctx context.Context, res *domain.UserWithIdentity, clientType, device, userAgent, ipAddress string) (*domain.LoginResult, error) {
+	if res.User.Disabled() {
+		return nil, domain.ErrAccountDisabled
+	}
+
+	enabled, err := u.mfaEnabled(ctx, res.User.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return u.createLogin(ctx, res, clientType, device, userAgent, ipAddress)
+	}
+
+	token, err := u.security.CreateMfaChallenge(ctx, &domain.MfaChallenge{
+		IdentityID: res.UserIdentity.ID,
+		ClientType: clientType,
+		Device:     device,
+		UserAgent:  userAgent,
+		IpAddress:  ipAddress,
+	})
+	if err != nil {
+		return nil, domain.ErrInternalServerError
+	}
+
+	return &domain.LoginResult{MfaRequired: true, MfaToken: token}, nil
+}
+
+// createLogin mobile client pakai jwt + refresh token, web pakai session cookie.
+// dicek ulang di sini karena passkey dan 2fa langsung ke createLogin, akun bisa dinonaktifkan di tengah challenge
+func (u *userUseCase) createLogin(ctx context.Context, res *domain.UserWithIdentity, clientType, device, userAgent, ipAddress string) (*domain.LoginResult, error) {
+	if res.User.Disabled() {
+		return nil, domain.ErrAccountDisabled
+	}
+
+	if clientType == domain.ClientTypeMobile {
+		refreshToken, familyID, err := u.security.CreateRefreshToken(ctx, res, device, userAgent, ipAddress)
+		if err != nil {
+			u.log.Error(err, "failed to create refresh token")
+			return nil, domain.ErrInternalServerError
+		}
+		accessToken, err := u.security.GenerateAccessToken(res, device, familyID, "")
+		if err != nil {
+			u.log.Error(err, "failed to generate access token")
+			return nil, domain.ErrInternalServerError
+		}
+		accessToken.RefreshToken = refreshToken
+		return &domain.LoginResult{User: res, AccessToken: accessToken}, nil
 	}
 
 	// create session
-	token, err := u.security.CreateSession(ctx, res, req.Device, req.UserAgent, req.IpAddress)
+	token, err := u.security.CreateSession(ctx, res, device, userAgent, ipAddress)
 	if err != nil {
 		u.log.Error(err, "failed to create session")
-		return nil, "", domain.ErrInternalServerError
+		return nil, domain.ErrInternalServerError
 	}
 
-	return res, token, nil
+	return &domain.LoginResult{User: res, SessionToken: token}, nil
 }
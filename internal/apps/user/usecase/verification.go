@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"booking/internal/domain"
+)
+
+// VerifyEmail token sekali pakai, langsung hangus walaupun verifikasi nya gagal
+func (u *userUseCase) VerifyEmail(ctx context.Context, token string) (*domain.UserWithIdentity, error) {
+	identityID, email, err := u.security.ConsumeEmailVerificationToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidVerificationToken) {
+			return nil, err
+		}
+		return nil, domain.ErrInternalServerError
+	}
+
+	user, err := u.GetByIdentityID(ctx, identityID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, domain.ErrInvalidVerificationToken
+		}
+		return nil, err
+	}
+	// email identity sudah diganti setelah token dibuat, token lama gak berlaku
+	if user.UserIdentity.Email == nil || *user.UserIdentity.Email != email {
+		return nil, domain.ErrInvalidVerificationToken
+	}
+	if user.UserIdentity.Verified {
+		return user, nil
+	}
+
+	res, err := u.userRepository.SetIdentityVerified(ctx, identityID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrInvalidVerificationToken
+		}
+		u.log.Error(err, "failed to set identity verified")
+		return nil, domain.ErrInternalServerError
+	}
+
+	// session yang sedang aktif ikut terverifikasi tanpa perlu login ulang
+	if err := u.security.UpdateIdentitySessions(ctx, res.User.ID, res.UserIdentity.Provider, map[string]interface{}{
+		"verified": domain.BoolToString(true),
+	}); err != nil {
+		u.log.Error(err, "failed to update verified flag in sessions")
+	}
+
+	return res, nil
+}
+
+// ResendVerification dibatasi satu kali per cooldown per email. email yang gak terdaftar
+// atau sudah terverifikasi tetap dianggap sukses
+func (u *userUseCase) ResendVerification(ctx context.Context, req *domain.ResendVerificationDTO) error {
+	wait, err := u.security.AllowVerificationResend(ctx, req.Email)
+	if err != nil {
+		return domain.ErrInternalServerError
+	}
+	if wait > 0 {
+		return domain.ErrToomanyrequest
+	}
+
+	user, err := u.userRepository.GetByEmail(ctx, req.Email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		u.log.Error(err, "failed to get user by email")
+		return domain.ErrInternalServerError
+	}
+	if user.UserIdentity.Verified {
+		return nil
+	}
+
+	if err := u.sendVerificationEmail(ctx, user); err != nil {
+		u.log.Error(err, "failed to resend verification email")
+		return domain.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (u *userUseCase) sendVerificationEmail(ctx context.Context, user *domain.UserWithIdentity) error {
+	if user.UserIdentity.Email == nil {
+		return nil
+	}
+	email := *user.UserIdentity.Email
+
+	token, err := u.security.CreateEmailVerificationToken(ctx, user.UserIdentity.ID, email)
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/api/v1/auth/verify?token=%s", u.config.App.PublicURL, url.QueryEscape(token))
+	return u.mailer.Send(ctx, &domain.Email{
+		To:      email,
+		Subject: "Verify your email",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nPlease verify your email by opening the link below:\n%s\n\nThe link expires in %s.\n",
+			user.User.Name, link, u.config.App.EmailVerifyTtl,
+		),
+	})
+}
@@ -1,8 +1,16 @@
 package bootstrap
 
 import (
+	"context"
+
 	authHandler "booking/internal/apps/auth/handler"
 	authUsecase "booking/internal/apps/auth/usecase"
+	bookingHandler "booking/internal/apps/booking/handler"
+	br "booking/internal/apps/booking/repository"
+	bookingUsecase "booking/internal/apps/booking/usecase"
+	orgHandler "booking/internal/apps/org/handler"
+	or "booking/internal/apps/org/repository"
+	orgUsecase "booking/internal/apps/org/usecase"
 	userHandler "booking/internal/apps/user/handler"
 	ur "booking/internal/apps/user/repository"
 	userUsecase "booking/internal/apps/user/usecase"
@@ -11,8 +19,17 @@ import (
 	"booking/pkg/config"
 	"booking/pkg/database"
 	"booking/pkg/logger"
+	"booking/pkg/mailer"
+	"booking/pkg/oauth"
+	"booking/pkg/passkey"
+	"booking/pkg/payment"
 	"booking/pkg/redis"
+	"booking/pkg/scheduler"
 	"booking/pkg/security"
+	"booking/pkg/sms"
+	uow "booking/pkg/unitOfWork"
+
+	"github.com/gofiber/fiber/v3"
 )
 
 type Apps struct {
@@ -30,19 +47,59 @@ func InitializeApps() *Apps {
 	// security
 	security := security.NewSecurity(config, rdb, logger)
 
+	// payment gateway
+	paymentProvider := payment.NewProvider(&config.Payment, logger)
+
+	// mailer
+	mailer := mailer.NewMailer(&config.Mail, logger)
+
+	// sms
+	smsSender := sms.NewSender(&config.Sms, logger)
+
+	// oauth provider (google, github)
+	oauthProviders := oauth.NewProviders(config)
+
+	// passkey (webauthn relying party)
+	webAuthn := passkey.NewWebAuthn(&config.WebAuthn, logger)
+
+	// unit of work
+	uow := uow.NewUnitOfWork(db)
+
 	// repository
 	userRepo := ur.NewUserRepository(db)
+	mfaRepo := ur.NewMfaRepository(db)
+	passkeyRepo := ur.NewPasskeyRepository(db)
+	orgRepo := or.NewOrgRepository(db)
+	resourceRepo := br.NewResourceRepository(db)
+	bookingRepo := br.NewBookingRepository(db)
+	holdRepo := br.NewHoldRepository(rdb)
+	waitlistRepo := br.NewWaitlistRepository(db)
+	ratePlanRepo := br.NewRatePlanRepository(db)
+	couponRepo := br.NewCouponRepository(db)
+	paymentRepo := br.NewPaymentRepository(db)
+	bookingEventRepo := br.NewBookingEventRepository(db)
+	availabilityCache := br.NewAvailabilityCache(rdb)
 
 	// usecase
-	userUsecase := userUsecase.NewUserUseCase(userRepo, security, logger)
-	authUsecase := authUsecase.NewAuthUsecase(userUsecase, security, logger)
+	userUsecase := userUsecase.NewUserUseCase(userRepo, mfaRepo, passkeyRepo, security, webAuthn, mailer, smsSender, config, logger)
+	authUsecase := authUsecase.NewAuthUsecase(userUsecase, security, oauthProviders, logger)
+	orgUsecase := orgUsecase.NewOrgUsecase(orgRepo, userRepo, security, logger)
+	resourceUsecase := bookingUsecase.NewResourceUsecase(resourceRepo, bookingRepo, holdRepo, waitlistRepo, ratePlanRepo, availabilityCache, uow, logger)
+	couponUsecase := bookingUsecase.NewCouponUsecase(couponRepo, uow, logger)
+	paymentUsecase := bookingUsecase.NewPaymentUsecase(paymentRepo, bookingRepo, bookingEventRepo, paymentProvider, uow, logger)
+	bookingUsecase := bookingUsecase.NewBookingUsecase(bookingRepo, resourceRepo, holdRepo, waitlistRepo, ratePlanRepo, couponRepo, bookingEventRepo, paymentRepo, paymentProvider, availabilityCache, uow, config, logger)
 
 	// middleware
-	middlewares := middleware.NewMiddlewares(security, rdb, config, logger)
+	middlewares := middleware.NewMiddlewares(security, rdb, orgRepo, config, logger)
 
 	// handler
 	userHandler := userHandler.NewUserHandler(userUsecase, middlewares, logger)
 	authHandler := authHandler.NewAuthHandler(authUsecase, middlewares, logger, config)
+	orgHandler := orgHandler.NewOrgHandler(orgUsecase, middlewares, logger)
+	resourceHandler := bookingHandler.NewResourceHandler(resourceUsecase, middlewares, logger)
+	couponHandler := bookingHandler.NewCouponHandler(couponUsecase, middlewares, logger)
+	paymentHandler := bookingHandler.NewPaymentHandler(paymentUsecase, middlewares, logger)
+	bookingHandler := bookingHandler.NewBookingHandler(bookingUsecase, middlewares, logger)
 
 	// server
 	srv := server.NewFiber(&config.Gateway, logger)
@@ -53,6 +110,21 @@ func InitializeApps() *Apps {
 	// register routes
 	authHandler.RegisterRoutes(v1.Group("/auth"))
 	userHandler.RegisterRoutes(v1.Group("/users"))
+	userHandler.RegisterAdminRoutes(v1.Group("/admin/users"))
+	paymentHandler.RegisterRoutes(v1.Group("/payments"))
+
+	// route per org: org dari header X-Org / subdomain / org aktif session, atau eksplisit lewat /orgs/:org/...
+	orgHandler.RegisterRoutes(v1.Group("/orgs"))
+	for _, base := range []fiber.Router{v1, v1.Group("/orgs/:org")} {
+		resourceHandler.RegisterRoutes(base.Group("/resources"))
+		bookingHandler.RegisterRoutes(base.Group("/bookings"))
+		bookingHandler.RegisterMeRoutes(base.Group("/me"))
+		couponHandler.RegisterRoutes(base.Group("/coupons"))
+	}
+
+	// background job, mati bersama proses
+	go scheduler.Every(context.Background(), config.App.WaitlistSweepInterval, logger, "expire waitlist offers", bookingUsecase.ExpireWaitlistOffers)
+	go scheduler.Every(context.Background(), config.App.PaymentSweepInterval, logger, "reconcile payments", paymentUsecase.ReconcilePayments)
 
 	return &Apps{
 		Config: config,
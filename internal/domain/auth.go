@@ -2,9 +2,17 @@ package domain
 
 import "context"
 
+const (
+	ClientTypeWeb    = "web"    // session cookie
+	ClientTypeMobile = "mobile" // bearer token (jwt)
+
+	TokenTypeBearer = "Bearer"
+)
+
 type LoginDTO struct {
-	Email    string `json:"email" validate:"required,email" message:"Valid email is required"`
-	Password string `json:"password" validate:"required,min=6,max=150" message:"Password is required and minimum length is 6"`
+	Email      string `json:"email" validate:"required,email" message:"Valid email is required"`
+	Password   string `json:"password" validate:"required,min=6,max=150" message:"Password is required and minimum length is 6"`
+	ClientType string `json:"client_type" validate:"omitempty,oneof=web mobile" message:"Client type must be web or mobile"`
 
 	// device info
 	Device    string `json:"device"`
@@ -23,9 +31,80 @@ type RegisterDTO struct {
 	Password   string `json:"password" validate:"required,min=6,max=150" message:"Password is required and minimum length is 6"`
 }
 
+type VerifyEmailQuery struct {
+	Token string `query:"token" validate:"required" message:"Token is required"`
+}
+
+type ResendVerificationDTO struct {
+	Email string `json:"email" validate:"required,email" message:"Valid email is required"`
+}
+
+type ForgotPasswordDTO struct {
+	Email string `json:"email" validate:"required,email" message:"Valid email is required"`
+}
+
+type ResetPasswordDTO struct {
+	Email    string `json:"email" validate:"required,email" message:"Valid email is required"`
+	Token    string `json:"token" validate:"required" message:"Token is required"`
+	Password string `json:"password" validate:"required,min=6,max=150" message:"Password is required and minimum length is 6"`
+}
+
+type PhoneOtpRequestDTO struct {
+	Phone string `json:"phone" validate:"required,e164" message:"Phone must be in E.164 format, e.g: +6281234567890"`
+}
+
+// PhoneLoginDTO nomor yang belum terdaftar otomatis dibuatkan akun, name opsional untuk akun baru
+type PhoneLoginDTO struct {
+	Phone      string `json:"phone" validate:"required,e164" message:"Phone must be in E.164 format, e.g: +6281234567890"`
+	Code       string `json:"code" validate:"required,len=6,numeric" message:"Code must be 6 digits"`
+	Name       string `json:"name" validate:"omitempty,min=2,max=100" message:"Name minimum length is 2"`
+	ClientType string `json:"client_type" validate:"omitempty,oneof=web mobile" message:"Client type must be web or mobile"`
+
+	// device info
+	Device    string `json:"-"`
+	IpAddress string `json:"-"`
+	UserAgent string `json:"-"`
+}
+
+type RefreshTokenDTO struct {
+	RefreshToken string `json:"refresh_token" validate:"required" message:"Refresh token is required"`
+}
+
+type AccessToken struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"` // detik
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+type LoginResult struct {
+	User         *UserWithIdentity `json:"user,omitempty"`
+	SessionToken string            `json:"-"`               // diisi kalau client web (cookie)
+	AccessToken  *AccessToken      `json:"token,omitempty"` // diisi kalau client mobile
+
+	// 2fa aktif: belum ada session, lanjutkan dengan POST /auth/mfa/verify
+	MfaRequired bool   `json:"mfa_required,omitempty"`
+	MfaToken    string `json:"mfa_token,omitempty"`
+}
+
 type AuthUsecase interface {
 	RegisterUser(ctx context.Context, req *RegisterDTO) (res *UserWithIdentity, err error)
-	Login(ctx context.Context, req *LoginDTO) (res *UserWithIdentity, token string, err error)
+	Login(ctx context.Context, req *LoginDTO) (res *LoginResult, err error)
+	VerifyEmail(ctx context.Context, token string) (*UserWithIdentity, error)
+	ResendVerification(ctx context.Context, req *ResendVerificationDTO) error
+	RequestPhoneOtp(ctx context.Context, req *PhoneOtpRequestDTO) error
+	LoginWithPhone(ctx context.Context, req *PhoneLoginDTO) (*LoginResult, error)
+	CompleteMfaLogin(ctx context.Context, req *MfaLoginDTO) (*LoginResult, error)
+	BeginPasskeyLogin(ctx context.Context) (*PasskeyCeremony, error)
+	FinishPasskeyLogin(ctx context.Context, req *PasskeyLoginDTO) (*LoginResult, error)
+	ForgotPassword(ctx context.Context, req *ForgotPasswordDTO) error
+	ResetPassword(ctx context.Context, req *ResetPasswordDTO) error
+	ConfirmEmailChange(ctx context.Context, token string) (*UserWithIdentity, error)
+	OAuthStart(ctx context.Context, provider string) (redirectURL string, nonce string, err error)
+	OAuthCallback(ctx context.Context, provider string, q *OAuthCallbackQuery) (*LoginResult, error)
+	OAuthLinkStart(ctx context.Context, userID, provider string) (redirectURL string, nonce string, err error)
 	GetAllActiveSessions(ctx context.Context, userId string) (sessions []SessionWithExpiry, err error)
 	Logout(ctx context.Context, userID string, token string) error
+	RefreshToken(ctx context.Context, req *RefreshTokenDTO) (res *AccessToken, err error)
+	RevokeTokenFamily(ctx context.Context, userID string, familyID string) error
 }
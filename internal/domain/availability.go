@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type AvailabilityQuery struct {
+	From      string `query:"from" validate:"required,datetime=2006-01-02" message:"From is required, e.g: 2025-01-02"`
+	To        string `query:"to" validate:"required,datetime=2006-01-02" message:"To is required, e.g: 2025-01-08"`
+	Duration  int    `query:"duration" validate:"required,min=1,max=1440" message:"Duration is required in minutes (1-1440)"`
+	PartySize int    `query:"party_size" validate:"min=0,max=10000" message:"Party size must be between 1 and 10000"`
+}
+
+type Slot struct {
+	StartTime      time.Time `json:"start_time"`
+	EndTime        time.Time `json:"end_time"`
+	RemainingSeats *int      `json:"remaining_seats,omitempty"` // hanya untuk resource shared
+}
+
+// AvailabilityCache - cache hasil availability search per resource,
+// Invalidate dipanggil setiap ada perubahan reservasi / jadwal di resource tsb.
+// hold / tawaran waitlist / batas bayar yang lewat gak invalidate apa-apa, jadi Set dikasih expiresAt
+// (waktu paling awal salah satunya lepas) supaya cache gak lebih lama dari itu. zero = TTL default
+type AvailabilityCache interface {
+	Get(ctx context.Context, resourceID string, q *AvailabilityQuery) ([]Slot, bool, error)
+	Set(ctx context.Context, resourceID string, q *AvailabilityQuery, slots []Slot, expiresAt time.Time) error
+	Invalidate(ctx context.Context, resourceID string) error
+}
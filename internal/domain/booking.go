@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	ReservationStatusPending   = "pending" // menunggu pembayaran
+	ReservationStatusConfirmed = "confirmed"
+	ReservationStatusCancelled = "cancelled"
+	// status lain + aturan perpindahannya di booking_state.go
+)
+
+type Reservation struct {
+	ID          string     `json:"id" db:"id"`
+	OrgID       string     `json:"org_id" db:"org_id"`
+	ResourceID  string     `json:"resource_id" db:"resource_id"`
+	UserID      string     `json:"user_id" db:"user_id"`
+	StartTime   time.Time  `json:"start_time" db:"start_time"`
+	EndTime     time.Time  `json:"end_time" db:"end_time"`
+	Status      string     `json:"status" db:"status"`
+	PartySize   int        `json:"party_size" db:"party_size"`
+	Notes       *string    `json:"notes,omitempty" db:"notes"`
+	CancelledAt *time.Time `json:"cancelled_at,omitempty" db:"cancelled_at"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+
+	// booking berulang
+	SeriesID        *string    `json:"series_id,omitempty" db:"series_id"`
+	OccurrenceStart *time.Time `json:"occurrence_start,omitempty" db:"occurrence_start"` // jadwal asli sebelum dipindah
+
+	// snapshot harga saat booking dibuat, nil untuk reservasi lama
+	Price *Quote `json:"price,omitempty" db:"price_snapshot"`
+	// batas bayar reservasi pending, lewat dari ini reservasi expired dan slot nya dilepas
+	PaymentDueAt *time.Time `json:"payment_due_at,omitempty" db:"payment_due_at"`
+	// snapshot policy pembatalan resource saat booking dibuat, nil = refund penuh
+	CancellationPolicy *CancellationPolicy `json:"cancellation_policy,omitempty" db:"cancellation_policy"`
+
+	// diisi saat reservasi berbayar baru dibuat, client_secret nya dipakai untuk bayar
+	Payment *Payment `json:"payment,omitempty" db:"-"`
+	// diisi saat reservasi yang sudah dibayar dibatalkan
+	Refund *Refund `json:"refund,omitempty" db:"-"`
+}
+
+// PaymentOverdue reservasi pending yang sudah lewat batas bayar, dianggap gak memakai slot lagi
+func (r *Reservation) PaymentOverdue(now time.Time) bool {
+	return r.Status == ReservationStatusPending && r.PaymentDueAt != nil && !r.PaymentDueAt.After(now)
+}
+
+// SlotUnavailableError - detail window yang bentrok, errors.Is(err, ErrSlotUnavailable) tetap true.
+// untuk resource shared diisi sisa kursi di slot yang diminta
+type SlotUnavailableError struct {
+	ResourceID     string    `json:"resource_id"`
+	StartTime      time.Time `json:"start_time"`
+	EndTime        time.Time `json:"end_time"`
+	RemainingSeats *int      `json:"remaining_seats,omitempty"`
+}
+
+func (e *SlotUnavailableError) Error() string {
+	return ErrSlotUnavailable.Error()
+}
+
+func (e *SlotUnavailableError) Is(target error) bool {
+	return target == ErrSlotUnavailable
+}
+
+type CreateBookingDTO struct {
+	ID         string    `json:"-"`
+	UserID     string    `json:"-"`
+	ResourceID string    `json:"resource_id" validate:"required,uuid" message:"Resource id is required"`
+	StartTime  time.Time `json:"start_time" validate:"required" message:"Start time is required, e.g: 2025-01-02T09:00:00+07:00"`
+	EndTime    time.Time `json:"end_time" validate:"required" message:"End time is required, e.g: 2025-01-02T10:00:00+07:00"`
+	PartySize  int       `json:"party_size" validate:"min=0,max=10000" message:"Party size must be between 1 and 10000"`
+	CouponCode string    `json:"coupon_code" validate:"max=50" message:"Coupon code maximum length is 50"`
+	Notes      string    `json:"notes" validate:"max=500" message:"Notes maximum length is 500"`
+
+	// diisi kalau reservasi adalah occurrence dari booking series
+	SeriesID        *string    `json:"-"`
+	OccurrenceStart *time.Time `json:"-"`
+
+	// dihitung ulang di server saat create, harga dari client gak pernah dipakai
+	Price *Quote `json:"-"`
+	// pending kalau ada yang harus dibayar, selain itu langsung confirmed
+	Status       string     `json:"-"`
+	PaymentDueAt *time.Time `json:"-"`
+}
+
+type BookingUsecase interface {
+	Create(ctx context.Context, req *CreateBookingDTO) (*Reservation, error)
+	GetByID(ctx context.Context, userID, id string) (*Reservation, error)
+	ListByUser(ctx context.Context, userID string, q *PaginationQuery) ([]Reservation, error)
+	Cancel(ctx context.Context, userID, id string, req *CancelBookingDTO) (*Reservation, error)
+	Move(ctx context.Context, userID, id string, req *MoveBookingDTO) ([]Reservation, error)
+	Quote(ctx context.Context, req *QuoteDTO) (*Quote, error)
+	CreateSeries(ctx context.Context, req *CreateSeriesDTO) (*CreateSeriesResult, error)
+	GetSeries(ctx context.Context, userID, id string) (*BookingSeries, error)
+	CreateHold(ctx context.Context, req *CreateHoldDTO) (*Hold, error)
+	ConfirmHold(ctx context.Context, userID, holdID string, req *ConfirmHoldDTO) (*Reservation, error)
+	ReleaseHold(ctx context.Context, userID, holdID string) error
+	JoinWaitlist(ctx context.Context, req *JoinWaitlistDTO) (*WaitlistEntry, error)
+	ListWaitlist(ctx context.Context, userID string, q *PaginationQuery) ([]WaitlistEntry, error)
+	LeaveWaitlist(ctx context.Context, userID, id string) error
+	AcceptWaitlistOffer(ctx context.Context, userID, id string) (*Reservation, error)
+	UpdateStatus(ctx context.Context, userID, id string, req *UpdateBookingStatusDTO) (*Reservation, error)
+	ListEvents(ctx context.Context, userID, id string) ([]BookingEvent, error)
+	// ExpireWaitlistOffers dijalankan berkala (bukan dari request), tawaran yang lewat waktu
+	// ditandai expired lalu antrian berikutnya dipromosikan (promosi yang gagal dicoba lagi di sweep berikutnya)
+	ExpireWaitlistOffers(ctx context.Context) error
+}
+
+type BookingRepository interface {
+	Create(ctx context.Context, tx *sqlx.Tx, req *CreateBookingDTO) (*Reservation, error)
+	GetByID(ctx context.Context, id string) (*Reservation, error)
+	GetByIDForUpdate(ctx context.Context, tx *sqlx.Tx, id string) (*Reservation, error)
+	ListByUser(ctx context.Context, userID string, limit, offset int) ([]Reservation, error)
+	ListOverlapping(ctx context.Context, tx *sqlx.Tx, resourceID string, start, end time.Time, excludeIDs []string) ([]Reservation, error)
+	ListActiveByResource(ctx context.Context, resourceID string, from, to time.Time) ([]Reservation, error)
+	UpdateStatus(ctx context.Context, tx *sqlx.Tx, id, status string) (*Reservation, error)
+	UpdateTime(ctx context.Context, tx *sqlx.Tx, id string, start, end time.Time) (*Reservation, error)
+
+	// booking series
+	CreateSeries(ctx context.Context, tx *sqlx.Tx, series *BookingSeries) (*BookingSeries, error)
+	GetSeriesByID(ctx context.Context, id string) (*BookingSeries, error)
+	UpdateSeriesRule(ctx context.Context, tx *sqlx.Tx, id, rrule string) error
+	ListBySeries(ctx context.Context, seriesID string) ([]Reservation, error)
+	ListFollowingForUpdate(ctx context.Context, tx *sqlx.Tx, seriesID string, from time.Time) ([]Reservation, error)
+}
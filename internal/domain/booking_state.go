@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// status reservasi selain pending, confirmed & cancelled (lihat booking.go)
+const (
+	ReservationStatusCheckedIn = "checked_in"
+	ReservationStatusCompleted = "completed"
+	ReservationStatusNoShow    = "no_show"
+	ReservationStatusExpired   = "expired" // pembayaran gak masuk sampai batas waktu
+)
+
+// reservationTransitions - perpindahan status yang sah, status yang gak ada di key adalah status akhir
+var reservationTransitions = map[string][]string{
+	ReservationStatusPending:   {ReservationStatusConfirmed, ReservationStatusCancelled, ReservationStatusExpired},
+	ReservationStatusConfirmed: {ReservationStatusCheckedIn, ReservationStatusCancelled, ReservationStatusNoShow},
+	ReservationStatusCheckedIn: {ReservationStatusCompleted},
+}
+
+// InvalidTransitionError - errors.Is(err, ErrInvalidTransition) tetap true
+type InvalidTransitionError struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+func (e *InvalidTransitionError) Error() string {
+	return fmt.Sprintf("%s: %s -> %s", ErrInvalidTransition.Error(), e.From, e.To)
+}
+
+func (e *InvalidTransitionError) Is(target error) bool {
+	return target == ErrInvalidTransition
+}
+
+// ValidateTransition cek perpindahan status reservasi, from kosong = reservasi baru (termasuk dari hold,
+// hold cuma ada di redis jadi bukan status reservasi)
+func ValidateTransition(from, to string) error {
+	if from == "" && (to == ReservationStatusPending || to == ReservationStatusConfirmed) {
+		return nil
+	}
+	for _, allowed := range reservationTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return &InvalidTransitionError{From: from, To: to}
+}
+
+// jenis aktor perubahan status reservasi
+const (
+	ActorTypeUser   = "user"
+	ActorTypeSystem = "system" // promosi waitlist, sweep, webhook pembayaran, expire otomatis
+)
+
+// BookingActor - siapa yang mengubah status, selalu dikirim eksplisit oleh pemanggil. user di ctx belum tentu
+// aktornya (e.g. promosi waitlist jalan di request cancel user lain)
+type BookingActor struct {
+	ID   *string
+	Type string
+}
+
+var SystemActor = BookingActor{Type: ActorTypeSystem}
+
+func UserActor(userID string) BookingActor {
+	return BookingActor{ID: &userID, Type: ActorTypeUser}
+}
+
+type BookingEvent struct {
+	ID            string    `json:"id" db:"id"`
+	ReservationID string    `json:"reservation_id" db:"reservation_id"`
+	ActorID       *string   `json:"actor_id,omitempty" db:"actor_id"` // nil = sistem
+	ActorType     string    `json:"actor_type" db:"actor_type"`
+	FromStatus    *string   `json:"from_status,omitempty" db:"from_status"`
+	ToStatus      string    `json:"to_status" db:"to_status"`
+	Reason        *string   `json:"reason,omitempty" db:"reason"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// UpdateBookingStatusDTO - dipakai pemilik resource untuk check-in / selesai / no-show
+type UpdateBookingStatusDTO struct {
+	Status string `json:"status" validate:"required,oneof=checked_in completed no_show" message:"Status must be checked_in, completed or no_show"`
+	Reason string `json:"reason" validate:"max=255" message:"Reason maximum length is 255"`
+}
+
+type BookingEventRepository interface {
+	Create(ctx context.Context, tx *sqlx.Tx, event *BookingEvent) error
+	ListByReservation(ctx context.Context, reservationID string) ([]BookingEvent, error)
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateTransition(t *testing.T) {
+	// from kosong = reservasi baru, "held" bukan status reservasi
+	statuses := []string{
+		"",
+		"held",
+		ReservationStatusPending,
+		ReservationStatusConfirmed,
+		ReservationStatusCheckedIn,
+		ReservationStatusCompleted,
+		ReservationStatusCancelled,
+		ReservationStatusNoShow,
+		ReservationStatusExpired,
+	}
+
+	allowed := map[[2]string]bool{
+		{"", ReservationStatusPending}:                           true,
+		{"", ReservationStatusConfirmed}:                         true,
+		{ReservationStatusPending, ReservationStatusConfirmed}:   true,
+		{ReservationStatusPending, ReservationStatusCancelled}:   true,
+		{ReservationStatusPending, ReservationStatusExpired}:     true,
+		{ReservationStatusConfirmed, ReservationStatusCheckedIn}: true,
+		{ReservationStatusConfirmed, ReservationStatusCancelled}: true,
+		{ReservationStatusConfirmed, ReservationStatusNoShow}:    true,
+		{ReservationStatusCheckedIn, ReservationStatusCompleted}: true,
+	}
+
+	for _, from := range statuses {
+		for _, to := range statuses[1:] {
+			want := allowed[[2]string{from, to}]
+			t.Run(from+"->"+to, func(t *testing.T) {
+				err := ValidateTransition(from, to)
+				if want {
+					if err != nil {
+						t.Fatalf("expected transition to be allowed, got %v", err)
+					}
+					return
+				}
+
+				if !errors.Is(err, ErrInvalidTransition) {
+					t.Fatalf("expected ErrInvalidTransition, got %v", err)
+				}
+				var transitionErr *InvalidTransitionError
+				if !errors.As(err, &transitionErr) || transitionErr.From != from || transitionErr.To != to {
+					t.Fatalf("expected InvalidTransitionError{%q, %q}, got %#v", from, to, err)
+				}
+			})
+		}
+	}
+}
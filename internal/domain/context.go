@@ -1,6 +1,28 @@
 package domain
 
+import "context"
+
 var (
 	SessionCtxKey      = "session"
 	SessionTokenCtxKey = "session_token"
+	TokenFamilyCtxKey  = "token_family" // refresh token family dari claim sid (login via jwt)
 )
+
+// SessionFromContext session user yang login. ctx dari c.RequestCtx() sudah membawa Locals fiber,
+// jadi usecase bisa tahu siapa aktornya tanpa parameter tambahan
+func SessionFromContext(ctx context.Context) (*Session, bool) {
+	session, ok := ctx.Value(SessionCtxKey).(*Session)
+	return session, ok && session != nil
+}
+
+// AuthRefFromContext penanda "login" yang sedang dipakai: token session (web) atau
+// refresh token family (mobile). dipakai untuk menandai re-autentikasi per login, bukan per user
+func AuthRefFromContext(ctx context.Context) (string, bool) {
+	if token, ok := ctx.Value(SessionTokenCtxKey).(string); ok && token != "" {
+		return token, true
+	}
+	if familyID, ok := ctx.Value(TokenFamilyCtxKey).(string); ok && familyID != "" {
+		return "family:" + familyID, true
+	}
+	return "", false
+}
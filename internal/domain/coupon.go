@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	CouponTypePercent = "percent"
+	CouponTypeFixed   = "fixed"
+)
+
+// LineItemDiscount - potongan kupon di quote, amount nya negatif
+const LineItemDiscount = "discount"
+
+type Coupon struct {
+	ID                    string     `json:"id" db:"id"`
+	OrgID                 string     `json:"org_id" db:"org_id"`
+	Code                  string     `json:"code" db:"code"`
+	DiscountType          string     `json:"discount_type" db:"discount_type"`
+	PercentOff            *int       `json:"percent_off,omitempty" db:"percent_off"`
+	AmountOff             *int64     `json:"amount_off,omitempty" db:"amount_off"` // minor unit
+	Currency              *string    `json:"currency,omitempty" db:"currency"`
+	MinSpend              int64      `json:"min_spend" db:"min_spend"`
+	MaxRedemptions        *int       `json:"max_redemptions,omitempty" db:"max_redemptions"`
+	MaxRedemptionsPerUser *int       `json:"max_redemptions_per_user,omitempty" db:"max_redemptions_per_user"`
+	TimesRedeemed         int        `json:"times_redeemed" db:"times_redeemed"`
+	ValidFrom             *time.Time `json:"valid_from,omitempty" db:"valid_from"`
+	ValidUntil            *time.Time `json:"valid_until,omitempty" db:"valid_until"`
+	IsActive              bool       `json:"is_active" db:"is_active"`
+	CreatedBy             string     `json:"created_by" db:"created_by"`
+	CreatedAt             time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt             time.Time  `json:"updated_at" db:"updated_at"`
+
+	ResourceIDs []string `json:"resource_ids" db:"-"` // kosong = semua resource
+}
+
+type CreateCouponDTO struct {
+	ID                    string     `json:"-"`
+	CreatedBy             string     `json:"-"`
+	Code                  string     `json:"code" validate:"required,alphanum,min=3,max=50" message:"Code is required, 3-50 alphanumeric characters"`
+	DiscountType          string     `json:"discount_type" validate:"required,oneof=percent fixed" message:"Discount type must be percent or fixed"`
+	PercentOff            *int       `json:"percent_off" validate:"required_if=DiscountType percent,omitempty,min=1,max=100" message:"Percent off is required for percent coupon (1-100)"`
+	AmountOff             *int64     `json:"amount_off" validate:"required_if=DiscountType fixed,omitempty,min=1" message:"Amount off is required for fixed coupon"`
+	Currency              string     `json:"currency" validate:"required_if=DiscountType fixed,omitempty,iso4217" message:"Currency is required for fixed coupon, e.g: IDR"`
+	MinSpend              int64      `json:"min_spend" validate:"min=0" message:"Min spend must not be negative"`
+	MaxRedemptions        *int       `json:"max_redemptions" validate:"omitempty,min=1" message:"Max redemptions minimum is 1"`
+	MaxRedemptionsPerUser *int       `json:"max_redemptions_per_user" validate:"omitempty,min=1" message:"Max redemptions per user minimum is 1"`
+	ValidFrom             *time.Time `json:"valid_from"`
+	ValidUntil            *time.Time `json:"valid_until"`
+	ResourceIDs           []string   `json:"resource_ids" validate:"dive,uuid" message:"Resource ids must be valid uuid"`
+}
+
+type CouponRedemption struct {
+	ID            string `db:"id"`
+	CouponID      string `db:"coupon_id"`
+	UserID        string `db:"user_id"`
+	ReservationID string `db:"reservation_id"`
+	Amount        int64  `db:"amount"`
+}
+
+type CouponUsecase interface {
+	Create(ctx context.Context, req *CreateCouponDTO) (*Coupon, error)
+	List(ctx context.Context, q *PaginationQuery) ([]Coupon, error)
+}
+
+type CouponRepository interface {
+	Create(ctx context.Context, tx *sqlx.Tx, req *CreateCouponDTO) (*Coupon, error)
+	List(ctx context.Context, limit, offset int) ([]Coupon, error)
+	GetByCode(ctx context.Context, code string) (*Coupon, error)
+	GetByCodeForUpdate(ctx context.Context, tx *sqlx.Tx, code string) (*Coupon, error)
+	CountUserRedemptions(ctx context.Context, tx *sqlx.Tx, couponID, userID string) (int, error)
+	Redeem(ctx context.Context, tx *sqlx.Tx, redemption *CouponRedemption) error
+}
@@ -17,11 +17,67 @@ var (
 	// service error
 	ErrUserNotFound      = errors.New("user not found")
 	ErrUserAlreadyExists = errors.New("user already exists")
+	ErrAccountDisabled   = errors.New("account is disabled")
+
+	// booking error
+	ErrResourceNotFound      = errors.New("resource not found")
+	ErrBookingNotFound       = errors.New("booking not found")
+	ErrInvalidBookingTime    = errors.New("invalid booking time")
+	ErrOutsideOpeningHours   = errors.New("booking is outside resource opening hours")
+	ErrSlotUnavailable       = errors.New("slot is not available")
+	ErrBookingNotCancellable = errors.New("booking can not be cancelled")
+	ErrBookingNotModifiable  = errors.New("booking can not be changed")
+	ErrHoldNotFound          = errors.New("hold not found or already expired")
+	ErrSeriesNotFound        = errors.New("booking series not found")
+	ErrInvalidRecurrence     = errors.New("invalid recurrence rule")
+	ErrSeriesConflict        = errors.New("some occurrences are not available")
+	ErrWaitlistNotFound      = errors.New("waitlist entry not found")
+	ErrAlreadyWaitlisted     = errors.New("already on the waitlist for this slot")
+	ErrSlotStillAvailable    = errors.New("slot is still available, book it directly")
+	ErrWaitlistOfferExpired  = errors.New("waitlist offer has expired")
+	ErrPartySizeTooLarge     = errors.New("party size exceeds resource capacity")
+	ErrRatePlanNotFound      = errors.New("rate plan not found")
+	ErrInvalidTransition     = errors.New("invalid booking status transition")
+
+	// organization error
+	ErrOrgRequired       = errors.New("organization is required")
+	ErrOrgNotFound       = errors.New("organization not found")
+	ErrOrgSlugExists     = errors.New("organization slug already exists")
+	ErrOrgMemberNotFound = errors.New("organization member not found")
+	ErrLastOrgAdmin      = errors.New("organization must have at least one admin")
+
+	// coupon error
+	ErrCouponNotFound      = errors.New("coupon not found")
+	ErrCouponCodeExists    = errors.New("coupon code already exists")
+	ErrCouponExpired       = errors.New("coupon is expired or not yet valid")
+	ErrCouponExhausted     = errors.New("coupon usage limit has been reached")
+	ErrCouponNotApplicable = errors.New("coupon is not applicable to this booking")
+
+	// payment error
+	ErrPaymentNotFound         = errors.New("payment not found")
+	ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
 
 	// jwt error
 	ErrInvalidToken = errors.New("invalid token")
 
 	// auth error
-	ErrInvalidCredentials = errors.New("invalid credentials")
-	ErrUnauthorized       = errors.New("unauthorized")
+	ErrInvalidCredentials       = errors.New("invalid credentials")
+	ErrInvalidVerificationToken = errors.New("verification token is invalid or expired")
+	ErrInvalidResetToken        = errors.New("reset token is invalid or expired")
+	ErrOAuthProviderNotFound    = errors.New("oauth provider not found")
+	ErrInvalidOAuthState        = errors.New("oauth state is invalid or expired")
+	ErrOAuthFailed              = errors.New("failed to authenticate with provider")
+	ErrReauthRequired           = errors.New("recent authentication required")
+	ErrIdentityAlreadyLinked    = errors.New("login method is already linked")
+	ErrLastIdentity             = errors.New("cannot remove the last usable login method")
+	ErrIdentityNotFound         = errors.New("login method not found")
+	ErrInvalidOtp               = errors.New("invalid or expired code")
+	ErrInvalidMfaChallenge      = errors.New("mfa challenge is invalid or expired")
+	ErrInvalidMfaCode           = errors.New("invalid two-factor code")
+	ErrMfaAlreadyEnabled        = errors.New("two-factor authentication is already enabled")
+	ErrMfaNotEnabled            = errors.New("two-factor authentication is not enabled")
+	ErrInvalidPasskeyChallenge  = errors.New("passkey challenge is invalid or expired")
+	ErrPasskeyFailed            = errors.New("passkey verification failed")
+	ErrPasskeyAlreadyRegistered = errors.New("passkey is already registered")
+	ErrUnauthorized             = errors.New("unauthorized")
 )
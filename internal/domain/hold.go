@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Hold - slot yang ditahan sementara selama checkout, disimpan di redis dengan TTL (APP_BOOKING_HOLD_TTL)
+type Hold struct {
+	ID         string    `json:"id"`
+	OrgID      string    `json:"org_id"`
+	ResourceID string    `json:"resource_id"`
+	UserID     string    `json:"user_id"`
+	StartTime  time.Time `json:"start_time"`
+	EndTime    time.Time `json:"end_time"`
+	PartySize  int       `json:"party_size"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// ToRedisMap - waktu disimpan sebagai unix timestamp
+func (h *Hold) ToRedisMap() map[string]interface{} {
+	return map[string]interface{}{
+		"id":          h.ID,
+		"org_id":      h.OrgID,
+		"resource_id": h.ResourceID,
+		"user_id":     h.UserID,
+		"start_time":  h.StartTime.Unix(),
+		"end_time":    h.EndTime.Unix(),
+		"party_size":  h.PartySize,
+		"expires_at":  h.ExpiresAt.Unix(),
+	}
+}
+
+type CreateHoldDTO struct {
+	UserID     string    `json:"-"`
+	ResourceID string    `json:"resource_id" validate:"required,uuid" message:"Resource id is required"`
+	StartTime  time.Time `json:"start_time" validate:"required" message:"Start time is required, e.g: 2025-01-02T09:00:00+07:00"`
+	EndTime    time.Time `json:"end_time" validate:"required" message:"End time is required, e.g: 2025-01-02T10:00:00+07:00"`
+	PartySize  int       `json:"party_size" validate:"min=0,max=10000" message:"Party size must be between 1 and 10000"`
+}
+
+type ConfirmHoldDTO struct {
+	CouponCode string `json:"coupon_code" validate:"max=50" message:"Coupon code maximum length is 50"`
+	Notes      string `json:"notes" validate:"max=500" message:"Notes maximum length is 500"`
+}
+
+type HoldRepository interface {
+	Create(ctx context.Context, hold *Hold, ttl time.Duration) error
+	GetByID(ctx context.Context, id string) (*Hold, error)
+	Delete(ctx context.Context, hold *Hold) error
+	// Consume hapus hold kalau masih ada, ErrHoldNotFound kalau sudah dipakai / dilepas / expired
+	Consume(ctx context.Context, hold *Hold) error
+	ListActiveByResource(ctx context.Context, resourceID string) ([]Hold, error)
+}
@@ -5,3 +5,8 @@ type HttpResponse struct {
 	Message any  `json:"message,omitempty"`
 	Data    any  `json:"data,omitempty"`
 }
+
+type PaginationQuery struct {
+	Limit  int `query:"limit"`
+	Offset int `query:"offset"`
+}
@@ -0,0 +1,14 @@
+package domain
+
+import "context"
+
+type Email struct {
+	To      string
+	Subject string
+	Body    string // plain text
+}
+
+// Mailer - pengirim email, implementasi nya di pkg/mailer (smtp / log)
+type Mailer interface {
+	Send(ctx context.Context, msg *Email) error
+}
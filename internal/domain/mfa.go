@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+const RecoveryCodeCount = 10
+
+type UserMfa struct {
+	UserID     string     `json:"user_id" db:"user_id"`
+	TotpSecret string     `json:"-" db:"totp_secret"` // terenkripsi
+	Enabled    bool       `json:"enabled" db:"enabled"`
+	EnabledAt  *time.Time `json:"enabled_at" db:"enabled_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// MfaChallenge - login yang password nya sudah benar tapi belum masukkan kode 2fa
+// (key: mfa_challenge:<sha256 token>), isinya cukup untuk melanjutkan login tanpa password lagi
+type MfaChallenge struct {
+	IdentityID string `redis:"identity_id"`
+	ClientType string `redis:"client_type"`
+	Device     string `redis:"device"`
+	UserAgent  string `redis:"user_agent"`
+	IpAddress  string `redis:"ip_address"`
+}
+
+func (m *MfaChallenge) ToRedisMap() map[string]interface{} {
+	return map[string]interface{}{
+		"identity_id": m.IdentityID,
+		"client_type": m.ClientType,
+		"device":      m.Device,
+		"user_agent":  m.UserAgent,
+		"ip_address":  m.IpAddress,
+	}
+}
+
+type TotpEnrollment struct {
+	Secret     string `json:"secret"`
+	OtpauthURI string `json:"otpauth_uri"`
+}
+
+type ConfirmTotpDTO struct {
+	Code string `json:"code" validate:"required,len=6,numeric" message:"Code must be 6 digits"`
+}
+
+// MfaCodeDTO salah satu wajib diisi: kode authenticator atau recovery code
+type MfaCodeDTO struct {
+	Code         string `json:"code" validate:"omitempty,len=6,numeric" message:"Code must be 6 digits"`
+	RecoveryCode string `json:"recovery_code" validate:"required_without=Code" message:"Code or recovery code is required"`
+}
+
+type MfaLoginDTO struct {
+	MfaToken string `json:"mfa_token" validate:"required" message:"Mfa token is required"`
+	MfaCodeDTO
+}
+
+type RecoveryCodes struct {
+	Codes []string `json:"recovery_codes"` // cuma ditampilkan sekali
+}
+
+type MfaRepository interface {
+	GetByUserID(ctx context.Context, userID string) (*UserMfa, error)
+	// Upsert simpan secret baru (belum aktif), gagal kalau 2fa user sudah aktif
+	Upsert(ctx context.Context, userID, encryptedSecret string) error
+	// Enable aktifkan 2fa sekaligus ganti semua recovery code
+	Enable(ctx context.Context, userID string, recoveryCodeHashes []string) error
+	Disable(ctx context.Context, userID string) error
+	ReplaceRecoveryCodes(ctx context.Context, userID string, recoveryCodeHashes []string) error
+	// UseRecoveryCode tandai terpakai, false kalau gak ada / sudah pernah dipakai
+	UseRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error)
+}
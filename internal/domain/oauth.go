@@ -0,0 +1,35 @@
+package domain
+
+import "context"
+
+// OAuthUserInfo - profil user dari provider yang sudah dinormalisasi
+type OAuthUserInfo struct {
+	Provider      string
+	ProviderID    string // sub (oidc) / id user github
+	Email         string
+	EmailVerified bool
+	Name          string
+	ImageURL      string
+}
+
+// OAuthProvider - authorization code flow + PKCE (S256)
+type OAuthProvider interface {
+	Name() string
+	AuthCodeURL(state, codeChallenge string) string
+	// Exchange tukar code ke access token lalu ambil profil user
+	Exchange(ctx context.Context, code, codeVerifier string) (*OAuthUserInfo, error)
+}
+
+type OAuthCallbackQuery struct {
+	Code  string `query:"code"`
+	State string `query:"state"`
+	Error string `query:"error"` // diisi provider kalau user menolak akses
+	Nonce string `query:"-"`     // dari cookie oauth_nonce, harus cocok dengan yang disimpan bersama state
+
+	SessionToken string `query:"-"` // cookie session browser kalau ada, dicek di flow tautkan akun
+
+	// device info
+	Device    string `query:"-"`
+	IpAddress string `query:"-"`
+	UserAgent string `query:"-"`
+}
@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// OrgHeader org bisa dipilih lewat header (slug atau id), selain lewat path /orgs/:org dan subdomain
+const OrgHeader = "X-Org"
+
+var OrgCtxKey = "org"
+
+// Organization - tenant. resource, booking, kupon dan pembayaran selalu milik satu org
+type Organization struct {
+	ID        string    `json:"id" db:"id"`
+	Slug      string    `json:"slug" db:"slug"`
+	Name      string    `json:"name" db:"name"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// OrgMember role per org (staff, resource_owner, admin). user yang bukan member tetap bisa booking sebagai RoleUser
+type OrgMember struct {
+	OrgID     string    `json:"org_id" db:"org_id"`
+	UserID    string    `json:"user_id" db:"user_id"`
+	Name      string    `json:"name" db:"name"`
+	Role      string    `json:"role" db:"role"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// OrgMembership org yang diikuti user beserta role nya
+type OrgMembership struct {
+	Organization
+	Role string `json:"role" db:"role"`
+}
+
+// OrgContext org aktif untuk request ini, diisi middleware ResolveOrg.
+// Role = role efektif di org tsb, dipakai RequirePermission menggantikan role global
+type OrgContext struct {
+	OrgID string
+	Slug  string
+	Role  string
+}
+
+type CreateOrgDTO struct {
+	Slug string `json:"slug" validate:"required,min=2,max=63,hostname_rfc1123,lowercase" message:"Slug is required, lowercase letters, numbers and dash, maximum length is 63"`
+	Name string `json:"name" validate:"required,min=2,max=100" message:"Name is required and minimum length is 2"`
+
+	// user pembuat otomatis jadi admin org
+	CreatedBy string `json:"-"`
+}
+
+type UpsertOrgMemberDTO struct {
+	Role string `json:"role" validate:"required,oneof=staff resource_owner admin" message:"Role must be one of staff, resource_owner, admin"`
+
+	OrgID  string `json:"-"`
+	UserID string `json:"-"`
+}
+
+type SwitchOrgDTO struct {
+	Org          string `json:"-"` // slug atau id dari path
+	UserID       string `json:"-"`
+	SessionToken string `json:"-"` // session yang sedang dipakai (web)
+	FamilyID     string `json:"-"` // refresh token family yang sedang dipakai (mobile)
+}
+
+// SwitchOrgResult client mobile dapat access token baru yang membawa org aktif
+type SwitchOrgResult struct {
+	Org         *OrgMembership `json:"org"`
+	AccessToken *AccessToken   `json:"token,omitempty"`
+}
+
+// OrgFromContext org aktif request. ctx dari c.RequestCtx() sudah membawa Locals fiber
+func OrgFromContext(ctx context.Context) (*OrgContext, bool) {
+	org, ok := ctx.Value(OrgCtxKey).(*OrgContext)
+	return org, ok && org != nil
+}
+
+// WithOrg dipakai proses yang gak lewat ResolveOrg (e.g: webhook pembayaran), org diambil dari data nya sendiri
+func WithOrg(ctx context.Context, org *OrgContext) context.Context {
+	return context.WithValue(ctx, OrgCtxKey, org)
+}
+
+// RoleFromContext role efektif aktor: role di org aktif kalau ada, selain itu role global session
+func RoleFromContext(ctx context.Context) string {
+	if org, ok := OrgFromContext(ctx); ok {
+		return org.Role
+	}
+	if session, ok := SessionFromContext(ctx); ok {
+		return session.Role
+	}
+	return ""
+}
+
+// EffectiveOrgRole admin global dianggap admin di semua org, member pakai role member nya,
+// selain itu cuma user biasa (pelanggan)
+func EffectiveOrgRole(globalRole string, member *OrgMember) string {
+	if globalRole == RoleAdmin {
+		return RoleAdmin
+	}
+	if member != nil {
+		return member.Role
+	}
+	return RoleUser
+}
+
+type OrgUsecase interface {
+	Create(ctx context.Context, req *CreateOrgDTO) (*Organization, error)
+	ListMine(ctx context.Context, userID string) ([]OrgMembership, error)
+	ListMembers(ctx context.Context, orgID string) ([]OrgMember, error)
+	UpsertMember(ctx context.Context, req *UpsertOrgMemberDTO) (*OrgMember, error)
+	RemoveMember(ctx context.Context, orgID, userID string) error
+	Switch(ctx context.Context, req *SwitchOrgDTO) (*SwitchOrgResult, error)
+}
+
+type OrgRepository interface {
+	Create(ctx context.Context, org *Organization, adminID string) (*Organization, error)
+	// GetByRef cari org berdasarkan id atau slug
+	GetByRef(ctx context.Context, ref string) (*Organization, error)
+	GetMember(ctx context.Context, orgID, userID string) (*OrgMember, error)
+	ListByUser(ctx context.Context, userID string) ([]OrgMembership, error)
+	ListMembers(ctx context.Context, orgID string) ([]OrgMember, error)
+	UpsertMember(ctx context.Context, orgID, userID, role string) (*OrgMember, error)
+	// DeleteMember gagal dengan ErrLastOrgAdmin kalau yang dihapus admin terakhir
+	DeleteMember(ctx context.Context, orgID, userID string) error
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+const (
+	PasskeyCeremonyRegister = "register"
+	PasskeyCeremonyLogin    = "login"
+)
+
+type PasskeyCredential struct {
+	ID              []byte     `json:"id" db:"id"` // credential id dari authenticator
+	UserID          string     `json:"user_id" db:"user_id"`
+	IdentityID      string     `json:"identity_id" db:"identity_id"`
+	Name            string     `json:"name" db:"name"`
+	PublicKey       []byte     `json:"-" db:"public_key"`
+	AttestationType string     `json:"-" db:"attestation_type"`
+	AAGUID          []byte     `json:"-" db:"aaguid"`
+	SignCount       int64      `json:"-" db:"sign_count"`
+	Transports      string     `json:"-" db:"transports"` // dipisah koma
+	BackupEligible  bool       `json:"backup_eligible" db:"backup_eligible"`
+	BackupState     bool       `json:"backup_state" db:"backup_state"`
+	LastUsedAt      *time.Time `json:"last_used_at" db:"last_used_at"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+}
+
+// PasskeyCeremony options diteruskan apa adanya ke navigator.credentials.create / get,
+// challenge_token dikirim balik di langkah finish
+type PasskeyCeremony struct {
+	ChallengeToken string `json:"challenge_token"`
+	Options        any    `json:"options"`
+}
+
+type PasskeyRegisterDTO struct {
+	ChallengeToken string          `json:"challenge_token" validate:"required" message:"Challenge token is required"`
+	Name           string          `json:"name" validate:"omitempty,max=100" message:"Name maximum length is 100"`
+	Credential     json.RawMessage `json:"credential" validate:"required" message:"Credential is required"` // PublicKeyCredential dari browser
+
+	UserID string `json:"-"`
+}
+
+type PasskeyLoginDTO struct {
+	ChallengeToken string          `json:"challenge_token" validate:"required" message:"Challenge token is required"`
+	Credential     json.RawMessage `json:"credential" validate:"required" message:"Credential is required"`
+	ClientType     string          `json:"client_type" validate:"omitempty,oneof=web mobile" message:"Client type must be web or mobile"`
+
+	// device info
+	Device    string `json:"-"`
+	IpAddress string `json:"-"`
+	UserAgent string `json:"-"`
+}
+
+type PasskeyRepository interface {
+	GetByID(ctx context.Context, credentialID []byte) (*PasskeyCredential, error)
+	ListByUserID(ctx context.Context, userID string) ([]PasskeyCredential, error)
+	// Create sekaligus buat identity passkey user kalau belum ada, IdentityID diisi dari situ
+	Create(ctx context.Context, credential *PasskeyCredential) error
+	// UpdateSignCount false kalau counter gak naik (authenticator yang counter nya dipakai), kemungkinan clone / replay
+	UpdateSignCount(ctx context.Context, credentialID []byte, signCount int64, backupState bool) (bool, error)
+}
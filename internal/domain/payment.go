@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	PaymentStatusPending    = "pending"
+	PaymentStatusAuthorized = "authorized" // dana di-hold, capture ke provider belum berhasil
+	PaymentStatusSucceeded  = "succeeded"
+	PaymentStatusFailed     = "failed"
+	PaymentStatusRefunded   = "refunded"
+	PaymentStatusPartially  = "partially_refunded"
+)
+
+// event webhook yang dikenali, provider lain harus menerjemahkan event nya ke tipe ini
+const (
+	PaymentEventAuthorized = "payment.authorized" // dana di-hold, perlu di-capture
+	PaymentEventSucceeded  = "payment.succeeded"  // dana sudah masuk
+	PaymentEventFailed     = "payment.failed"
+)
+
+const PaymentProviderFake = "fake"
+
+// PaymentSignatureHeader - header yang berisi signature webhook
+const PaymentSignatureHeader = "X-Payment-Signature"
+
+type Payment struct {
+	ID             string    `json:"id" db:"id"`
+	OrgID          string    `json:"org_id" db:"org_id"`
+	ReservationID  string    `json:"reservation_id" db:"reservation_id"`
+	UserID         string    `json:"user_id" db:"user_id"`
+	Provider       string    `json:"provider" db:"provider"`
+	ProviderRef    *string   `json:"provider_ref" db:"provider_ref"` // nil = intent belum dibuat di provider
+	ClientSecret   *string   `json:"client_secret,omitempty" db:"client_secret"`
+	Amount         int64     `json:"amount" db:"amount"`
+	Currency       string    `json:"currency" db:"currency"`
+	Status         string    `json:"status" db:"status"`
+	RefundedAmount int64     `json:"refunded_amount" db:"refunded_amount"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// PaymentIntent - hasil create intent di provider
+type PaymentIntent struct {
+	ID           string
+	ClientSecret string
+}
+
+// PaymentEvent - isi webhook yang sudah diverifikasi
+type PaymentEvent struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	IntentID string `json:"intent_id"`
+}
+
+// PaymentProvider - abstraksi payment gateway. reference = id reservasi.
+// CreateIntent / Capture / Refund selalu dipanggil di luar transaksi db dengan idempotency key id row yang sudah di-commit,
+// jadi aman di-retry
+type PaymentProvider interface {
+	Name() string
+	CreateIntent(ctx context.Context, amount int64, currency, reference, idempotencyKey string) (*PaymentIntent, error)
+	Capture(ctx context.Context, intentID, idempotencyKey string) error
+	Refund(ctx context.Context, intentID string, amount int64, idempotencyKey string) error
+	VerifyWebhook(payload []byte, signature string) (*PaymentEvent, error)
+}
+
+type PaymentUsecase interface {
+	HandleWebhook(ctx context.Context, payload []byte, signature string) error
+	GetByReservation(ctx context.Context, userID, reservationID string) (*Payment, error)
+	ReconcilePayments(ctx context.Context) error
+}
+
+type PaymentRepository interface {
+	Create(ctx context.Context, tx *sqlx.Tx, payment *Payment) (*Payment, error)
+	GetByProviderRef(ctx context.Context, provider, providerRef string) (*Payment, error)
+	GetByIDForUpdate(ctx context.Context, tx *sqlx.Tx, id string) (*Payment, error)
+	GetLatestByReservation(ctx context.Context, reservationID string) (*Payment, error)
+	ListAuthorized(ctx context.Context, olderThan time.Duration, limit int) ([]Payment, error)
+	ListWithoutIntent(ctx context.Context, olderThan time.Duration, limit int) ([]Payment, error)
+	SetIntent(ctx context.Context, id, providerRef, clientSecret string) (*Payment, error)
+	UpdateStatus(ctx context.Context, tx *sqlx.Tx, id, status string, refundedAmount int64) (*Payment, error)
+	CreateRefund(ctx context.Context, tx *sqlx.Tx, refund *Refund) (*Refund, error)
+	ListPendingRefunds(ctx context.Context, olderThan time.Duration, limit int) ([]Refund, error)
+	UpdateRefundStatus(ctx context.Context, id, status string) error
+}
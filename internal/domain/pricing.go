@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// BasisPoint 10000 = 1x, multiplier disimpan dalam basis point supaya hitungan harga tetap integer
+const BasisPoint = 10000
+
+// jenis line item di quote
+const (
+	LineItemTime          = "time"           // durasi x hourly rate x multiplier
+	LineItemDaily         = "daily"          // total per hari dibatasi daily rate
+	LineItemSeat          = "seat"           // per orang
+	LineItemMinimumCharge = "minimum_charge" // tambahan supaya total gak kurang dari minimum charge
+)
+
+// RatePlan - harga per resource, semua nominal dalam minor unit currency (e.g. sen untuk USD)
+type RatePlan struct {
+	ID                  string    `json:"id" db:"id"`
+	ResourceID          string    `json:"resource_id" db:"resource_id"`
+	Currency            string    `json:"currency" db:"currency"`
+	HourlyRate          int64     `json:"hourly_rate" db:"hourly_rate"`
+	DailyRate           int64     `json:"daily_rate" db:"daily_rate"` // 0 = tanpa batas harian
+	PerSeatPrice        int64     `json:"per_seat_price" db:"per_seat_price"`
+	MinimumCharge       int64     `json:"minimum_charge" db:"minimum_charge"`
+	WeekdayMultiplierBp int       `json:"weekday_multiplier_bp" db:"weekday_multiplier_bp"`
+	WeekendMultiplierBp int       `json:"weekend_multiplier_bp" db:"weekend_multiplier_bp"`
+	CreatedAt           time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at" db:"updated_at"`
+
+	Peaks []RatePlanPeak `json:"peaks" db:"-"`
+}
+
+// RatePlanPeak - multiplier di jam tertentu, waktu dalam timezone resource
+type RatePlanPeak struct {
+	ID           string `json:"id" db:"id"`
+	RatePlanID   string `json:"rate_plan_id" db:"rate_plan_id"`
+	DayOfWeek    *int   `json:"day_of_week,omitempty" db:"day_of_week"` // nil = setiap hari
+	StartTime    string `json:"start_time" db:"start_time"`             // HH:MM
+	EndTime      string `json:"end_time" db:"end_time"`                 // HH:MM
+	MultiplierBp int    `json:"multiplier_bp" db:"multiplier_bp"`
+}
+
+type UpsertRatePlanDTO struct {
+	ID                  string            `json:"-"`
+	ResourceID          string            `json:"-"`
+	Currency            string            `json:"currency" validate:"required,iso4217" message:"Currency must be a valid ISO 4217 code, e.g: IDR"`
+	HourlyRate          int64             `json:"hourly_rate" validate:"min=0" message:"Hourly rate must not be negative"`
+	DailyRate           int64             `json:"daily_rate" validate:"min=0" message:"Daily rate must not be negative"`
+	PerSeatPrice        int64             `json:"per_seat_price" validate:"min=0" message:"Per seat price must not be negative"`
+	MinimumCharge       int64             `json:"minimum_charge" validate:"min=0" message:"Minimum charge must not be negative"`
+	WeekdayMultiplierBp int               `json:"weekday_multiplier_bp" validate:"min=0,max=100000" message:"Weekday multiplier must be between 1 and 100000 basis point"`
+	WeekendMultiplierBp int               `json:"weekend_multiplier_bp" validate:"min=0,max=100000" message:"Weekend multiplier must be between 1 and 100000 basis point"`
+	Peaks               []RatePlanPeakDTO `json:"peaks" validate:"dive" message:"Peaks are not valid"`
+}
+
+type RatePlanPeakDTO struct {
+	ID           string `json:"-"`
+	DayOfWeek    *int   `json:"day_of_week" validate:"omitempty,min=0,max=6" message:"Day of week must be between 0 (sunday) and 6 (saturday)"`
+	StartTime    string `json:"start_time" validate:"required,datetime=15:04" message:"Start time is required, e.g: 18:00"`
+	EndTime      string `json:"end_time" validate:"required,datetime=15:04" message:"End time is required, e.g: 21:00"`
+	MultiplierBp int    `json:"multiplier_bp" validate:"required,min=1,max=100000" message:"Multiplier is required in basis point, e.g: 15000 for 1.5x"`
+}
+
+type QuoteDTO struct {
+	UserID     string    `json:"-"`
+	ResourceID string    `json:"resource_id" validate:"required,uuid" message:"Resource id is required"`
+	StartTime  time.Time `json:"start_time" validate:"required" message:"Start time is required, e.g: 2025-01-02T09:00:00+07:00"`
+	EndTime    time.Time `json:"end_time" validate:"required" message:"End time is required, e.g: 2025-01-02T10:00:00+07:00"`
+	PartySize  int       `json:"party_size" validate:"min=0,max=10000" message:"Party size must be between 1 and 10000"`
+	CouponCode string    `json:"coupon_code" validate:"max=50" message:"Coupon code maximum length is 50"`
+}
+
+type QuoteLineItem struct {
+	Type         string `json:"type"`
+	Description  string `json:"description"`
+	Quantity     int    `json:"quantity"` // menit untuk time, orang untuk seat, 1 untuk lainnya
+	UnitAmount   int64  `json:"unit_amount"`
+	MultiplierBp int    `json:"multiplier_bp,omitempty"`
+	Amount       int64  `json:"amount"`
+}
+
+// Quote - rincian harga. disimpan juga sebagai snapshot di reservasi (kolom jsonb price_snapshot)
+type Quote struct {
+	ResourceID string          `json:"resource_id"`
+	StartTime  time.Time       `json:"start_time"`
+	EndTime    time.Time       `json:"end_time"`
+	PartySize  int             `json:"party_size"`
+	Currency   string          `json:"currency"`
+	LineItems  []QuoteLineItem `json:"line_items"`
+	Subtotal   int64           `json:"subtotal"`
+	Discount   int64           `json:"discount"`
+	CouponCode string          `json:"coupon_code,omitempty"`
+	Total      int64           `json:"total"`
+}
+
+// Value - simpan quote ke kolom jsonb
+func (q Quote) Value() (driver.Value, error) {
+	b, err := json.Marshal(q)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan - baca quote dari kolom jsonb
+func (q *Quote) Scan(src any) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, q)
+	case string:
+		return json.Unmarshal([]byte(v), q)
+	default:
+		return errors.New("unsupported type for quote")
+	}
+}
+
+type RatePlanRepository interface {
+	Upsert(ctx context.Context, tx *sqlx.Tx, req *UpsertRatePlanDTO) (*RatePlan, error)
+	GetByResource(ctx context.Context, resourceID string) (*RatePlan, error)
+}
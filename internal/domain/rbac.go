@@ -0,0 +1,53 @@
+package domain
+
+type Permission string
+
+const (
+	PermBookingCreate  Permission = "booking:create"  // booking, hold, waitlist untuk diri sendiri
+	PermResourceManage Permission = "resource:manage" // buat resource, atur jadwal / harga / kebijakan resource milik sendiri
+	PermCouponManage   Permission = "coupon:manage"
+	PermUserRead       Permission = "user:read"
+	PermUserManage     Permission = "user:manage" // ganti role, nonaktifkan user
+	PermOrgManage      Permission = "org:manage"  // tanpa org aktif: buat org baru. di dalam org: kelola member
+)
+
+// rolePermissions satu-satunya tempat hak akses tiap role didefinisikan, dipakai untuk role global
+// maupun role di org (lihat EffectiveOrgRole).
+// kepemilikan data (resource milik siapa, booking milik siapa) tetap dicek di usecase
+var rolePermissions = map[string][]Permission{
+	RoleUser: {
+		PermBookingCreate,
+	},
+	RoleStaff: {
+		PermBookingCreate,
+		PermCouponManage,
+		PermUserRead,
+	},
+	RoleResourceOwner: {
+		PermBookingCreate,
+		PermResourceManage,
+	},
+	RoleAdmin: {
+		PermBookingCreate,
+		PermResourceManage,
+		PermCouponManage,
+		PermUserRead,
+		PermUserManage,
+		PermOrgManage,
+	},
+}
+
+// ValidRole role yang ada di matrix, role lain (typo / data lama) gak punya hak akses apapun
+func ValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+func HasPermission(role string, perm Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
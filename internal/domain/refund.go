@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const (
+	RefundReasonCancellation = "cancellation" // dibatalkan user, nominal sesuai policy
+	RefundReasonLatePayment  = "late_payment" // pembayaran masuk setelah reservasi batal, refund penuh
+)
+
+const (
+	RefundStatusPending   = "pending"   // sudah di-commit, belum berhasil dikirim ke provider
+	RefundStatusSucceeded = "succeeded" // dana sudah dikembalikan provider (atau nominal nya 0)
+)
+
+// CancellationPolicy - tier refund berdasarkan jarak waktu pembatalan ke jam mulai.
+// tier dengan hours_before terbesar yang terpenuhi yang dipakai, gak ada yang terpenuhi = gak ada refund
+type CancellationPolicy struct {
+	Tiers []CancellationTier `json:"tiers"`
+}
+
+type CancellationTier struct {
+	HoursBefore int `json:"hours_before" validate:"min=0,max=8760" message:"Hours before must be between 0 and 8760"`
+	RefundBp    int `json:"refund_bp" validate:"min=0,max=10000" message:"Refund bp must be between 0 and 10000"`
+}
+
+// Value - simpan policy sebagai jsonb
+func (p CancellationPolicy) Value() (driver.Value, error) {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan - baca policy dari kolom jsonb
+func (p *CancellationPolicy) Scan(src any) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, p)
+	case string:
+		return json.Unmarshal([]byte(v), p)
+	default:
+		return errors.New("unsupported type for cancellation policy")
+	}
+}
+
+type SetCancellationPolicyDTO struct {
+	ResourceID string             `json:"-"`
+	Tiers      []CancellationTier `json:"tiers" validate:"max=10,dive" message:"Tiers maximum is 10"` // kosong = hapus policy (refund penuh)
+}
+
+type Refund struct {
+	ID             string    `json:"id" db:"id"`
+	PaymentID      string    `json:"payment_id" db:"payment_id"`
+	ReservationID  string    `json:"reservation_id" db:"reservation_id"`
+	Amount         int64     `json:"amount" db:"amount"`
+	RetainedAmount int64     `json:"retained_amount" db:"retained_amount"`
+	RefundBp       int       `json:"refund_bp" db:"refund_bp"`
+	Reason         string    `json:"reason" db:"reason"`
+	Status         string    `json:"status" db:"status"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+
+	// dari payment nya, dipakai saat kirim refund ke provider
+	OrgID       string `json:"-" db:"org_id"`
+	ProviderRef string `json:"-" db:"provider_ref"`
+}
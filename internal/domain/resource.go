@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type Resource struct {
+	ID                  string              `json:"id" db:"id"`
+	OrgID               string              `json:"org_id" db:"org_id"`
+	OwnerID             string              `json:"owner_id" db:"owner_id"`
+	Name                string              `json:"name" db:"name"`
+	Description         *string             `json:"description,omitempty" db:"description"`
+	Timezone            string              `json:"timezone" db:"timezone"` // IANA, e.g. Asia/Jakarta
+	BufferMinutes       int                 `json:"buffer_minutes" db:"buffer_minutes"`
+	SlotIntervalMinutes int                 `json:"slot_interval_minutes" db:"slot_interval_minutes"`
+	Capacity            int                 `json:"capacity" db:"capacity"`           // 1 = exclusive, > 1 = shared per kursi
+	WaitlistMode        string              `json:"waitlist_mode" db:"waitlist_mode"` // offer, auto
+	CancellationPolicy  *CancellationPolicy `json:"cancellation_policy,omitempty" db:"cancellation_policy"`
+	IsActive            bool                `json:"is_active" db:"is_active"`
+	CreatedAt           time.Time           `json:"created_at" db:"created_at"`
+	UpdatedAt           time.Time           `json:"updated_at" db:"updated_at"`
+
+	Rules      []AvailabilityRule      `json:"rules,omitempty" db:"-"`
+	Exceptions []AvailabilityException `json:"exceptions,omitempty" db:"-"`
+}
+
+// AvailabilityRule - jam buka mingguan, waktu dalam timezone resource
+type AvailabilityRule struct {
+	ID         string `json:"id" db:"id"`
+	ResourceID string `json:"resource_id" db:"resource_id"`
+	DayOfWeek  int    `json:"day_of_week" db:"day_of_week"` // 0 = minggu, sama dengan time.Weekday
+	StartTime  string `json:"start_time" db:"start_time"`   // HH:MM
+	EndTime    string `json:"end_time" db:"end_time"`       // HH:MM
+}
+
+// AvailabilityException - tutup di tanggal tertentu (libur / maintenance), waktu dalam timezone resource
+type AvailabilityException struct {
+	ID         string  `json:"id" db:"id"`
+	ResourceID string  `json:"resource_id" db:"resource_id"`
+	Date       string  `json:"date" db:"date"`                       // YYYY-MM-DD
+	StartTime  *string `json:"start_time,omitempty" db:"start_time"` // HH:MM, nil = tutup seharian
+	EndTime    *string `json:"end_time,omitempty" db:"end_time"`     // HH:MM
+	Reason     *string `json:"reason,omitempty" db:"reason"`
+}
+
+type CreateResourceDTO struct {
+	ID                  string                `json:"-"`
+	OwnerID             string                `json:"-"`
+	Name                string                `json:"name" validate:"required,min=2,max=100" message:"Name is required and minimum length is 2"`
+	Description         string                `json:"description" validate:"max=1000" message:"Description maximum length is 1000"`
+	Timezone            string                `json:"timezone" validate:"omitempty,timezone" message:"Timezone must be a valid IANA timezone, e.g: Asia/Jakarta"`
+	BufferMinutes       int                   `json:"buffer_minutes" validate:"min=0,max=1440" message:"Buffer minutes must be between 0 and 1440"`
+	SlotIntervalMinutes int                   `json:"slot_interval_minutes" validate:"min=0,max=1440" message:"Slot interval minutes maximum is 1440"`
+	Capacity            int                   `json:"capacity" validate:"min=0,max=10000" message:"Capacity must be between 1 and 10000"`
+	WaitlistMode        string                `json:"waitlist_mode" validate:"omitempty,oneof=offer auto" message:"Waitlist mode must be offer or auto"`
+	Rules               []AvailabilityRuleDTO `json:"rules" validate:"dive" message:"Rules are not valid"`
+}
+
+type AvailabilityRuleDTO struct {
+	ID        string `json:"-"`
+	DayOfWeek int    `json:"day_of_week" validate:"min=0,max=6" message:"Day of week must be between 0 (sunday) and 6 (saturday)"`
+	StartTime string `json:"start_time" validate:"required,datetime=15:04" message:"Start time is required, e.g: 09:00"`
+	EndTime   string `json:"end_time" validate:"required,datetime=15:04" message:"End time is required, e.g: 17:00"`
+}
+
+// IsShared resource dengan kapasitas lebih dari 1 kursi (kelas, tur, event)
+func (r *Resource) IsShared() bool {
+	return r.Capacity > 1
+}
+
+// ManagedBy pemilik resource, atau admin org aktif yang boleh kelola semua resource di org nya
+func (r *Resource) ManagedBy(ctx context.Context, userID string) bool {
+	return r.OwnerID == userID || RoleFromContext(ctx) == RoleAdmin
+}
+
+type CreateAvailabilityExceptionDTO struct {
+	ID         string `json:"-"`
+	ResourceID string `json:"-"`
+	Date       string `json:"date" validate:"required,datetime=2006-01-02" message:"Date is required, e.g: 2025-12-25"`
+	StartTime  string `json:"start_time" validate:"required_with=EndTime,omitempty,datetime=15:04" message:"Start time must be like 09:00 and filled together with end time"`
+	EndTime    string `json:"end_time" validate:"required_with=StartTime,omitempty,datetime=15:04" message:"End time must be like 17:00 and filled together with start time"`
+	Reason     string `json:"reason" validate:"max=255" message:"Reason maximum length is 255"`
+}
+
+type ResourceUsecase interface {
+	Create(ctx context.Context, req *CreateResourceDTO) (*Resource, error)
+	GetByID(ctx context.Context, id string) (*Resource, error)
+	List(ctx context.Context, q *PaginationQuery) ([]Resource, error)
+	AddException(ctx context.Context, userID string, req *CreateAvailabilityExceptionDTO) (*AvailabilityException, error)
+	GetAvailability(ctx context.Context, resourceID string, q *AvailabilityQuery) ([]Slot, error)
+	SetRatePlan(ctx context.Context, userID string, req *UpsertRatePlanDTO) (*RatePlan, error)
+	GetRatePlan(ctx context.Context, resourceID string) (*RatePlan, error)
+	SetCancellationPolicy(ctx context.Context, userID string, req *SetCancellationPolicyDTO) (*Resource, error)
+}
+
+type ResourceRepository interface {
+	Create(ctx context.Context, tx *sqlx.Tx, req *CreateResourceDTO) (*Resource, error)
+	GetByID(ctx context.Context, id string) (*Resource, error)
+	GetByIDForUpdate(ctx context.Context, tx *sqlx.Tx, id string) (*Resource, error)
+	List(ctx context.Context, limit, offset int) ([]Resource, error)
+	GetRules(ctx context.Context, resourceID string) ([]AvailabilityRule, error)
+	CreateException(ctx context.Context, req *CreateAvailabilityExceptionDTO) (*AvailabilityException, error)
+	GetExceptions(ctx context.Context, resourceID string, fromDate, toDate string) ([]AvailabilityException, error)
+	SetCancellationPolicy(ctx context.Context, resourceID string, policy *CancellationPolicy) (*Resource, error)
+}
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"time"
+)
+
+const (
+	ScopeThis      = "this"      // hanya occurrence ini
+	ScopeFollowing = "following" // occurrence ini dan setelahnya
+)
+
+// BookingSeries - booking berulang, occurrence nya disimpan sebagai Reservation dengan SeriesID
+type BookingSeries struct {
+	ID              string    `json:"id" db:"id"`
+	OrgID           string    `json:"org_id" db:"org_id"`
+	ResourceID      string    `json:"resource_id" db:"resource_id"`
+	UserID          string    `json:"user_id" db:"user_id"`
+	RRule           string    `json:"rrule" db:"rrule"`
+	StartTime       time.Time `json:"start_time" db:"start_time"`
+	DurationMinutes int       `json:"duration_minutes" db:"duration_minutes"`
+	Timezone        string    `json:"timezone" db:"timezone"`
+	Notes           *string   `json:"notes,omitempty" db:"notes"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+
+	Occurrences []Reservation `json:"occurrences" db:"-"`
+}
+
+// OccurrenceConflict - occurrence yang gagal dibuat / dipindah, dilaporkan per tanggal
+type OccurrenceConflict struct {
+	Date      string    `json:"date"` // YYYY-MM-DD, timezone resource
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	Reason    string    `json:"reason"`
+}
+
+// SeriesConflictError - errors.Is(err, ErrSeriesConflict) tetap true, Conflicts dikirim ke client
+type SeriesConflictError struct {
+	Conflicts []OccurrenceConflict `json:"conflicts"`
+}
+
+func (e *SeriesConflictError) Error() string {
+	return ErrSeriesConflict.Error()
+}
+
+func (e *SeriesConflictError) Is(target error) bool {
+	return target == ErrSeriesConflict
+}
+
+type CreateSeriesDTO struct {
+	ID         string    `json:"-"`
+	UserID     string    `json:"-"`
+	ResourceID string    `json:"resource_id" validate:"required,uuid" message:"Resource id is required"`
+	StartTime  time.Time `json:"start_time" validate:"required" message:"Start time of first occurrence is required, e.g: 2025-01-06T09:00:00+07:00"`
+	EndTime    time.Time `json:"end_time" validate:"required" message:"End time of first occurrence is required, e.g: 2025-01-06T10:00:00+07:00"`
+	RRule      string    `json:"rrule" validate:"required,max=500" message:"RRule is required, e.g: FREQ=WEEKLY;COUNT=10"`
+	PartySize  int       `json:"party_size" validate:"min=0,max=10000" message:"Party size must be between 1 and 10000"`
+	Notes      string    `json:"notes" validate:"max=500" message:"Notes maximum length is 500"`
+	// true: occurrence yang bentrok dilewati, false: semua gagal kalau ada yang bentrok
+	SkipConflicts bool `json:"skip_conflicts"`
+}
+
+type CreateSeriesResult struct {
+	Series    *BookingSeries       `json:"series"`
+	Conflicts []OccurrenceConflict `json:"conflicts"`
+}
+
+type CancelBookingDTO struct {
+	Scope  string `json:"scope" validate:"omitempty,oneof=this following" message:"Scope must be this or following"`
+	Reason string `json:"reason" validate:"max=255" message:"Reason maximum length is 255"`
+}
+
+type MoveBookingDTO struct {
+	StartTime time.Time `json:"start_time" validate:"required" message:"Start time is required, e.g: 2025-01-02T09:00:00+07:00"`
+	EndTime   time.Time `json:"end_time" validate:"required" message:"End time is required, e.g: 2025-01-02T10:00:00+07:00"`
+	Scope     string    `json:"scope" validate:"omitempty,oneof=this following" message:"Scope must be this or following"`
+}
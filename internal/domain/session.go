@@ -12,6 +12,7 @@ type Session struct {
 	ProviderID string `redis:"provider_id"`
 	Phone      string `redis:"phone"`
 	Verified   string `redis:"verified"`
+	OrgID      string `redis:"org_id"` // org aktif, diganti lewat POST /orgs/:org/switch
 
 	// Device info
 	Device    string `redis:"device"`     // e.g. "iPhone 15 Pro", "MacBook", "Chrome on Windows"
@@ -31,6 +32,7 @@ func (s *Session) ToRedisMap() map[string]interface{} {
 		"provider_id": s.ProviderID,
 		"phone":       s.Phone,
 		"verified":    s.Verified,
+		"org_id":      s.OrgID,
 		"device":      s.Device,
 		"user_agent":  s.UserAgent,
 		"ip_address":  s.IpAddress,
@@ -42,3 +44,25 @@ type SessionWithExpiry struct {
 	ExpireTime time.Time `json:"expire_time"`
 	Token      string    `json:"token,omitempty"`
 }
+
+// RefreshToken - data refresh token yang disimpan di redis (key: refresh_token:<sha256 token>)
+type RefreshToken struct {
+	UserID     string `redis:"userID"`
+	IdentityID string `redis:"identity_id"`
+	FamilyID   string `redis:"family_id"`
+	Device     string `redis:"device"`
+	UserAgent  string `redis:"user_agent"`
+	IpAddress  string `redis:"ip_address"`
+	RotatedAt  string `redis:"rotated_at"` // terisi kalau token sudah pernah ditukar
+}
+
+func (r *RefreshToken) ToRedisMap() map[string]interface{} {
+	return map[string]interface{}{
+		"userID":      r.UserID,
+		"identity_id": r.IdentityID,
+		"family_id":   r.FamilyID,
+		"device":      r.Device,
+		"user_agent":  r.UserAgent,
+		"ip_address":  r.IpAddress,
+	}
+}
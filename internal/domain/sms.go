@@ -0,0 +1,8 @@
+package domain
+
+import "context"
+
+// SmsSender - pengirim sms, implementasi nya di pkg/sms. to dalam format E.164
+type SmsSender interface {
+	Send(ctx context.Context, to, message string) error
+}
@@ -5,6 +5,14 @@ import (
 	"time"
 )
 
+// role user, hak akses tiap role ada di rbac.go
+const (
+	RoleUser          = "user"
+	RoleStaff         = "staff"          // operasional: kelola kupon, lihat data user
+	RoleResourceOwner = "resource_owner" // pemilik resource yang bisa dibooking
+	RoleAdmin         = "admin"
+)
+
 type User struct {
 	ID        string    `db:"id"`
 	Name      string    `db:"name"`
@@ -12,6 +20,13 @@ type User struct {
 	Role      string    `db:"role"`
 	CreatedAt time.Time `db:"created_at"`
 	UpdatedAt time.Time `db:"updated_at"`
+
+	// DisabledAt diisi admin, user yang dinonaktifkan gak bisa login dan session nya ditolak
+	DisabledAt *time.Time `db:"disabled_at"`
+}
+
+func (u *User) Disabled() bool {
+	return u.DisabledAt != nil
 }
 
 type UserWithIdentity struct {
@@ -19,13 +34,139 @@ type UserWithIdentity struct {
 	UserIdentity UserIdentity
 }
 
+type ChangePasswordDTO struct {
+	CurrentPassword     string `json:"current_password" validate:"required" message:"Current password is required"`
+	NewPassword         string `json:"new_password" validate:"required,min=6,max=150" message:"New password is required and minimum length is 6"`
+	LogoutOtherSessions bool   `json:"logout_other_sessions"`
+
+	UserID       string `json:"-"`
+	SessionToken string `json:"-"` // session yang sedang dipakai (web)
+	FamilyID     string `json:"-"` // refresh token family yang sedang dipakai (mobile)
+}
+
+type ChangeEmailDTO struct {
+	Email    string `json:"email" validate:"required,email" message:"Valid email is required"`
+	Password string `json:"password" validate:"required" message:"Password is required"`
+
+	UserID string `json:"-"`
+}
+
+// ReauthDTO konfirmasi ulang password sebelum aksi sensitif
+type ReauthDTO struct {
+	Password string `json:"password" validate:"required" message:"Password is required"`
+}
+
+// LinkLocalIdentityDTO tambah login email + password untuk user yang selama ini login sosial
+type LinkLocalIdentityDTO struct {
+	Email    string `json:"email" validate:"required,email" message:"Valid email is required"`
+	Password string `json:"password" validate:"required,min=6,max=150" message:"Password is required and minimum length is 6"`
+
+	UserID string `json:"-"`
+}
+
+// ChangeRoleDTO dipakai admin, UserID = user yang diganti role nya
+type ChangeRoleDTO struct {
+	Role string `json:"role" validate:"required,oneof=user staff resource_owner admin" message:"Role must be one of user, staff, resource_owner, admin"`
+
+	UserID string `json:"-"`
+}
+
+// AdminUserQuery filter GET /admin/users. search = awalan nama atau email
+type AdminUserQuery struct {
+	Search      string `query:"search" validate:"max=100" message:"Search maximum length is 100"`
+	Role        string `query:"role" validate:"omitempty,oneof=user staff resource_owner admin" message:"Role must be one of user, staff, resource_owner, admin"`
+	Verified    string `query:"verified" validate:"omitempty,oneof=true false" message:"Verified must be true or false"`
+	CreatedFrom string `query:"created_from" validate:"omitempty,datetime=2006-01-02" message:"Created from must be a date, e.g: 2025-01-02"`
+	CreatedTo   string `query:"created_to" validate:"omitempty,datetime=2006-01-02" message:"Created to must be a date, e.g: 2025-01-08"`
+	Limit       int    `query:"limit"`
+	Offset      int    `query:"offset"`
+}
+
+// AdminUser data user untuk support. email = email identity pertama, verified = ada identity yang terverifikasi
+type AdminUser struct {
+	ID         string     `json:"id" db:"id"`
+	Name       string     `json:"name" db:"name"`
+	ImageURL   *string    `json:"image_url" db:"image_url"`
+	Role       string     `json:"role" db:"role"`
+	Email      *string    `json:"email" db:"email"`
+	Verified   bool       `json:"verified" db:"verified"`
+	DisabledAt *time.Time `json:"disabled_at" db:"disabled_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+type AdminUserDetail struct {
+	AdminUser
+	Identities []UserIdentity `json:"identities"`
+}
+
+// AdminUserFilter hasil parsing AdminUserQuery, dipakai repository
+type AdminUserFilter struct {
+	Search      string
+	Role        string
+	Verified    *bool
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time // eksklusif, sudah ditambah 1 hari dari tanggal di query
+	Limit       int
+	Offset      int
+}
+
 type UserUsecase interface {
 	GetByEmail(ctx context.Context, email string) (*UserWithIdentity, error)
+	GetByIdentityID(ctx context.Context, identityID string) (*UserWithIdentity, error)
 	RegisterUser(ctx context.Context, req *RegisterDTO) (res *UserWithIdentity, err error)
-	Login(ctx context.Context, req *LoginDTO) (res *UserWithIdentity, token string, err error)
+	Login(ctx context.Context, req *LoginDTO) (res *LoginResult, err error)
+	VerifyEmail(ctx context.Context, token string) (*UserWithIdentity, error)
+	ResendVerification(ctx context.Context, req *ResendVerificationDTO) error
+	RequestPhoneOtp(ctx context.Context, req *PhoneOtpRequestDTO) error
+	LoginWithPhone(ctx context.Context, req *PhoneLoginDTO) (*LoginResult, error)
+	CompleteMfaLogin(ctx context.Context, req *MfaLoginDTO) (*LoginResult, error)
+	EnrollTotp(ctx context.Context, userID string) (*TotpEnrollment, error)
+	ConfirmTotp(ctx context.Context, userID string, req *ConfirmTotpDTO) (*RecoveryCodes, error)
+	DisableMfa(ctx context.Context, userID string, req *MfaCodeDTO) error
+	RegenerateRecoveryCodes(ctx context.Context, userID string, req *MfaCodeDTO) (*RecoveryCodes, error)
+	ForgotPassword(ctx context.Context, req *ForgotPasswordDTO) error
+	ResetPassword(ctx context.Context, req *ResetPasswordDTO) error
+	ChangePassword(ctx context.Context, req *ChangePasswordDTO) error
+	RequestEmailChange(ctx context.Context, req *ChangeEmailDTO) error
+	ConfirmEmailChange(ctx context.Context, token string) (*UserWithIdentity, error)
+	LoginWithOAuth(ctx context.Context, info *OAuthUserInfo, device, userAgent, ipAddress string) (*LoginResult, error)
+	ListIdentities(ctx context.Context, userID string) ([]UserIdentity, error)
+	Reauthenticate(ctx context.Context, userID string, req *ReauthDTO) error
+	RequireRecentAuth(ctx context.Context) error
+	LinkLocalIdentity(ctx context.Context, req *LinkLocalIdentityDTO) (*UserWithIdentity, error)
+	LinkOAuthIdentity(ctx context.Context, userID string, info *OAuthUserInfo) (*UserWithIdentity, error)
+	UnlinkIdentity(ctx context.Context, userID, identityID string) error
+	ChangeRole(ctx context.Context, req *ChangeRoleDTO) (*User, error)
+	ListUsers(ctx context.Context, q *AdminUserQuery) ([]AdminUser, error)
+	GetUserDetail(ctx context.Context, userID string) (*AdminUserDetail, error)
+	SetUserDisabled(ctx context.Context, userID string, disabled bool) (*AdminUser, error)
+	ForceLogout(ctx context.Context, userID string) error
+	BeginPasskeyRegistration(ctx context.Context, userID string) (*PasskeyCeremony, error)
+	FinishPasskeyRegistration(ctx context.Context, req *PasskeyRegisterDTO) (*PasskeyCredential, error)
+	BeginPasskeyLogin(ctx context.Context) (*PasskeyCeremony, error)
+	FinishPasskeyLogin(ctx context.Context, req *PasskeyLoginDTO) (*LoginResult, error)
 }
 
 type UserRepository interface {
 	GetByEmail(ctx context.Context, email string) (*UserWithIdentity, error)
+	GetByIdentityID(ctx context.Context, identityID string) (*UserWithIdentity, error)
+	GetByUserIDAndProvider(ctx context.Context, userID, provider string) (*UserWithIdentity, error)
+	GetByProviderID(ctx context.Context, provider, providerID string) (*UserWithIdentity, error)
+	GetByVerifiedEmail(ctx context.Context, email string) (*UserWithIdentity, error)
+	ExistsByEmail(ctx context.Context, email string) (bool, error)
 	RegisterUser(ctx context.Context, req *RegisterDTO) (*UserWithIdentity, error)
+	CreateWithIdentity(ctx context.Context, user *User, identity *UserIdentity) (*UserWithIdentity, error)
+	CreateIdentity(ctx context.Context, identity *UserIdentity) (*UserWithIdentity, error)
+	ListIdentities(ctx context.Context, userID string) ([]UserIdentity, error)
+	// DeleteIdentity gagal dengan ErrLastIdentity kalau setelah dihapus gak ada identity yang Usable
+	DeleteIdentity(ctx context.Context, userID, identityID string) (*UserIdentity, error)
+	UpdateEmail(ctx context.Context, identityID, email string) (*UserWithIdentity, error)
+	SetIdentityVerified(ctx context.Context, identityID string) (*UserWithIdentity, error)
+	UpdatePassword(ctx context.Context, identityID, passwordHash string) error
+	UpdateRole(ctx context.Context, userID, role string) (*User, error)
+	List(ctx context.Context, filter *AdminUserFilter) ([]AdminUser, error)
+	GetAdminUser(ctx context.Context, userID string) (*AdminUser, error)
+	// SetDisabled disabled_at yang sudah terisi gak ditimpa, supaya waktu pertama kali dinonaktifkan tetap tercatat
+	SetDisabled(ctx context.Context, userID string, disabled bool) (*AdminUser, error)
 }
@@ -2,6 +2,14 @@ package domain
 
 import "time"
 
+const (
+	ProviderLocal   = "local" // login email + password
+	ProviderGoogle  = "google"
+	ProviderGitHub  = "github"
+	ProviderPhone   = "phone"   // otp sms, provider_id = nomor E.164
+	ProviderPasskey = "passkey" // webauthn, provider_id = user id. credential nya di tabel passkey_credentials
+)
+
 type UserIdentity struct {
 	ID           string    `json:"id" db:"id"`
 	UserID       string    `json:"user_id" db:"user_id"`
@@ -14,3 +22,11 @@ type UserIdentity struct {
 	CreatedAt    time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
 }
+
+// Usable identity yang masih bisa dipakai login. local tanpa password (belum pernah set) gak dihitung
+func (i *UserIdentity) Usable() bool {
+	if i.Provider == ProviderLocal {
+		return i.PasswordHash != nil
+	}
+	return true
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	WaitlistStatusWaiting  = "waiting"
+	WaitlistStatusOffered  = "offered"  // slot kosong, menunggu di-accept sebelum offer_expires_at
+	WaitlistStatusPromoted = "promoted" // sudah jadi reservasi
+	WaitlistStatusLeft     = "left"
+	WaitlistStatusExpired  = "expired"
+)
+
+// mode promosi waitlist per resource
+const (
+	WaitlistModeOffer = "offer" // user pertama dapat tawaran dengan batas waktu (APP_WAITLIST_OFFER_TTL)
+	WaitlistModeAuto  = "auto"  // user pertama langsung dibuatkan reservasi
+)
+
+type WaitlistEntry struct {
+	ID             string     `json:"id" db:"id"`
+	OrgID          string     `json:"org_id" db:"org_id"`
+	ResourceID     string     `json:"resource_id" db:"resource_id"`
+	UserID         string     `json:"user_id" db:"user_id"`
+	StartTime      time.Time  `json:"start_time" db:"start_time"`
+	EndTime        time.Time  `json:"end_time" db:"end_time"`
+	PartySize      int        `json:"party_size" db:"party_size"`
+	Status         string     `json:"status" db:"status"`
+	Notes          *string    `json:"notes,omitempty" db:"notes"`
+	OfferExpiresAt *time.Time `json:"offer_expires_at,omitempty" db:"offer_expires_at"`
+	ReservationID  *string    `json:"reservation_id,omitempty" db:"reservation_id"`
+	PromoteAfter   *time.Time `json:"-" db:"promote_after"` // tawaran sudah expired, antrian berikutnya belum dipromosikan
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+
+	// posisi di antrian slot yang sama, 0 kalau sudah gak waiting
+	Position int `json:"position,omitempty" db:"position"`
+}
+
+type JoinWaitlistDTO struct {
+	ID         string    `json:"-"`
+	UserID     string    `json:"-"`
+	ResourceID string    `json:"resource_id" validate:"required,uuid" message:"Resource id is required"`
+	StartTime  time.Time `json:"start_time" validate:"required" message:"Start time is required, e.g: 2025-01-02T09:00:00+07:00"`
+	EndTime    time.Time `json:"end_time" validate:"required" message:"End time is required, e.g: 2025-01-02T10:00:00+07:00"`
+	PartySize  int       `json:"party_size" validate:"min=0,max=10000" message:"Party size must be between 1 and 10000"`
+	Notes      string    `json:"notes" validate:"max=500" message:"Notes maximum length is 500"`
+}
+
+type WaitlistRepository interface {
+	Create(ctx context.Context, tx *sqlx.Tx, req *JoinWaitlistDTO) (*WaitlistEntry, error)
+	GetByID(ctx context.Context, id string) (*WaitlistEntry, error)
+	GetByIDForUpdate(ctx context.Context, tx *sqlx.Tx, id string) (*WaitlistEntry, error)
+	ListByUser(ctx context.Context, userID string, limit, offset int) ([]WaitlistEntry, error)
+	ListWaitingForUpdate(ctx context.Context, tx *sqlx.Tx, resourceID string, from, to time.Time) ([]WaitlistEntry, error)
+	ListOverlappingOffers(ctx context.Context, tx *sqlx.Tx, resourceID string, start, end time.Time, excludeID string) ([]WaitlistEntry, error)
+	ListActiveOffers(ctx context.Context, resourceID string, from, to time.Time) ([]WaitlistEntry, error)
+	// ListLapsedOffers gak di-scope org, cuma untuk job sweep
+	ListLapsedOffers(ctx context.Context, limit int) ([]WaitlistEntry, error)
+	// ListPendingPromotions gak di-scope org, cuma untuk job sweep
+	ListPendingPromotions(ctx context.Context, limit int) ([]WaitlistEntry, error)
+	SetPromoteAfter(ctx context.Context, tx *sqlx.Tx, id string, promoteAfter *time.Time) error
+	UpdateStatus(ctx context.Context, tx *sqlx.Tx, id, status string, offerExpiresAt *time.Time, reservationID *string) (*WaitlistEntry, error)
+}
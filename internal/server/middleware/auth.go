@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"strings"
 	"time"
 
 	"booking/internal/domain"
@@ -11,18 +12,31 @@ import (
 
 func (m *Middleware) Auth() fiber.Handler {
 	return func(c fiber.Ctx) error {
-		// var token string
-
 		// bisa pakai jwt atau session
-		if c.Get("Authorization") != "" {
-			m.log.Error(nil, "JWT NOT IMPLEMENTED YET")
-			return c.Status(fiber.StatusInternalServerError).JSON(domain.HttpResponse{
-				Success: false,
-				Message: domain.ErrInternalServerError,
-			})
+		if authHeader := c.Get("Authorization"); authHeader != "" {
+			token, ok := strings.CutPrefix(authHeader, domain.TokenTypeBearer+" ")
+			if !ok || token == "" {
+				return utils.ErrorResponse(c, domain.ErrInvalidToken, nil)
+			}
+			session, familyID, err := m.security.ParseAccessToken(token)
+			if err != nil {
+				return utils.ErrorResponse(c, domain.ErrInvalidToken, nil)
+			}
+			// family di-revoke (logout / reuse refresh token) → access token nya ikut mati
+			active, err := m.security.FamilyActive(c.RequestCtx(), familyID)
+			if err != nil {
+				return utils.ErrorResponse(c, domain.ErrInternalServerError, nil)
+			}
+			if !active {
+				return utils.ErrorResponse(c, domain.ErrInvalidToken, nil)
+			}
+			if err := m.checkRole(c, session); err != nil {
+				return utils.ErrorResponse(c, err, nil)
+			}
+			c.Locals(domain.SessionCtxKey, session)
+			c.Locals(domain.TokenFamilyCtxKey, familyID)
 		} else if string(c.Request().Header.Cookie("session")) != "" {
 			sessionToken := c.Request().Header.Cookie("session")
-			m.log.Info("session token: " + string(sessionToken))
 			session, refreshed, err := m.security.GetSession(c.RequestCtx(), string(sessionToken))
 			if err != nil {
 				return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
@@ -45,3 +59,15 @@ func (m *Middleware) Auth() fiber.Handler {
 		return c.Next()
 	}
 }
+
+// checkRole access token jwt yang terbit sebelum role diganti admin ditolak, client refresh untuk dapat role baru
+func (m *Middleware) checkRole(c fiber.Ctx, session *domain.Session) error {
+	role, err := m.security.ChangedRole(c.RequestCtx(), session.UserID)
+	if err != nil {
+		return domain.ErrInternalServerError
+	}
+	if role != "" && role != session.Role {
+		return domain.ErrInvalidToken
+	}
+	return nil
+}
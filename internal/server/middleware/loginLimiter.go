@@ -18,18 +18,51 @@ func (m *Middleware) LoginLimiter() fiber.Handler {
 			return err // Error akan di-handle oleh error handler fiber
 		}
 
-		// Check ban
-		delay, err := m.security.CheckBan(c.RequestCtx(), dto.Email)
-		if err != nil {
-			m.log.Error(err, "failed to check ban in redis")
-			return utils.ErrorResponse(c, domain.ErrInternalServerError, nil)
+		return m.checkBan(c, dto.Email)
+	}
+}
+
+// PasswordResetLimiter pakai counter & ban yang sama dengan login, jadi tebak token reset
+// dan tebak password sama-sama dihitung sebagai percobaan gagal untuk email tsb
+func (m *Middleware) PasswordResetLimiter() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		var dto domain.ResetPasswordDTO
+
+		if err := c.Bind().Body(&dto); err != nil {
+			m.log.Error(err, "failed to bind reset password dto")
+			return err
 		}
 
-		if delay > 0 {
-			data := fmt.Sprintf("too many attempts, please try again after %s", delay.String())
-			return utils.ErrorResponse(c, domain.ErrToomanyrequest, data)
+		return m.checkBan(c, dto.Email)
+	}
+}
+
+// PhoneLoginLimiter ban per nomor, counter nya sama dengan login email
+func (m *Middleware) PhoneLoginLimiter() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		var dto domain.PhoneLoginDTO
+
+		if err := c.Bind().Body(&dto); err != nil {
+			m.log.Error(err, "failed to bind phone login dto")
+			return err
 		}
 
-		return c.Next()
+		return m.checkBan(c, dto.Phone)
 	}
 }
+
+func (m *Middleware) checkBan(c fiber.Ctx, email string) error {
+	// Check ban
+	delay, err := m.security.CheckBan(c.RequestCtx(), email)
+	if err != nil {
+		m.log.Error(err, "failed to check ban in redis")
+		return utils.ErrorResponse(c, domain.ErrInternalServerError, nil)
+	}
+
+	if delay > 0 {
+		data := fmt.Sprintf("too many attempts, please try again after %s", delay.String())
+		return utils.ErrorResponse(c, domain.ErrToomanyrequest, data)
+	}
+
+	return c.Next()
+}
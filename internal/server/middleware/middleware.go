@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"booking/internal/domain"
 	"booking/pkg/config"
 	"booking/pkg/logger"
 	"booking/pkg/security"
@@ -11,10 +12,11 @@ import (
 type Middleware struct {
 	security *security.Security
 	rdb      *redis.Client
+	orgs     domain.OrgRepository
 	config   *config.Config
 	log      logger.Logger
 }
 
-func NewMiddlewares(security *security.Security, rdb *redis.Client, config *config.Config, log logger.Logger) *Middleware {
-	return &Middleware{security: security, rdb: rdb, config: config, log: log}
+func NewMiddlewares(security *security.Security, rdb *redis.Client, orgs domain.OrgRepository, config *config.Config, log logger.Logger) *Middleware {
+	return &Middleware{security: security, rdb: rdb, orgs: orgs, config: config, log: log}
 }
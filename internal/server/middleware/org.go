@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"booking/internal/domain"
+	"booking/pkg/utils"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// ResolveOrg tentukan org request ini, urutan: path /orgs/:org, header X-Org, subdomain <slug>.APP_WEB_DOMAIN,
+// lalu org aktif di session. dipasang setelah Auth() (kalau route nya butuh login) supaya role member bisa dihitung.
+// repository booking menolak query tanpa org, jadi route yang lupa pasang middleware ini gagal, bukan bocor lintas org
+func (m *Middleware) ResolveOrg() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		session, _ := c.Locals(domain.SessionCtxKey).(*domain.Session)
+
+		ref := m.orgRef(c, session)
+		if ref == "" {
+			return utils.ErrorResponse(c, domain.ErrOrgRequired, nil)
+		}
+
+		org, err := m.orgs.GetByRef(c.RequestCtx(), ref)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return utils.ErrorResponse(c, domain.ErrOrgNotFound, nil)
+			}
+			m.log.Error(err, "failed to get organization")
+			return utils.ErrorResponse(c, domain.ErrInternalServerError, nil)
+		}
+
+		orgCtx := &domain.OrgContext{OrgID: org.ID, Slug: org.Slug, Role: domain.RoleUser}
+		if session != nil {
+			member, err := m.orgs.GetMember(c.RequestCtx(), org.ID, session.UserID)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				m.log.Error(err, "failed to get organization member")
+				return utils.ErrorResponse(c, domain.ErrInternalServerError, nil)
+			}
+			orgCtx.Role = domain.EffectiveOrgRole(session.Role, member)
+		}
+
+		c.Locals(domain.OrgCtxKey, orgCtx)
+		return c.Next()
+	}
+}
+
+func (m *Middleware) orgRef(c fiber.Ctx, session *domain.Session) string {
+	if ref := c.Params("org"); ref != "" {
+		return ref
+	}
+	if ref := c.Get(domain.OrgHeader); ref != "" {
+		return ref
+	}
+	// cuma satu level subdomain, api.<domain> / www.<domain> bukan org
+	if sub, ok := strings.CutSuffix(c.Hostname(), "."+m.config.App.WebDomain); ok && sub != "" && !strings.Contains(sub, ".") && sub != "www" && sub != "api" {
+		return sub
+	}
+	if session != nil {
+		return session.OrgID
+	}
+	return ""
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"slices"
+
+	"booking/internal/domain"
+	"booking/pkg/utils"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// RequireRole dipasang setelah Auth(). lolos kalau role session salah satu dari roles
+func (m *Middleware) RequireRole(roles ...string) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		session, ok := c.Locals(domain.SessionCtxKey).(*domain.Session)
+		if !ok || session == nil {
+			return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+		}
+		if !slices.Contains(roles, session.Role) {
+			return utils.ErrorResponse(c, domain.ErrForbiden, nil)
+		}
+		return c.Next()
+	}
+}
+
+// RequirePermission dipasang setelah Auth() (dan ResolveOrg() untuk route per org). role harus punya semua permission,
+// kalau ada org aktif yang dipakai role di org tsb, bukan role global
+func (m *Middleware) RequirePermission(perms ...domain.Permission) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		session, ok := c.Locals(domain.SessionCtxKey).(*domain.Session)
+		if !ok || session == nil {
+			return utils.ErrorResponse(c, domain.ErrUnauthorized, nil)
+		}
+		role := session.Role
+		if org, ok := c.Locals(domain.OrgCtxKey).(*domain.OrgContext); ok && org != nil {
+			role = org.Role
+		}
+		for _, perm := range perms {
+			if !domain.HasPermission(role, perm) {
+				return utils.ErrorResponse(c, domain.ErrForbiden, nil)
+			}
+		}
+		return c.Next()
+	}
+}
@@ -70,7 +70,7 @@ func NewFiber(
 		AllowOrigins:     []string{"http://localhost:3000"},
 		AllowCredentials: true,
 		AllowMethods:     []string{"GET", "POST", "HEAD", "PUT", "DELETE", "PATCH", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Requested-With"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Requested-With", domain.OrgHeader},
 		ExposeHeaders:    []string{"Set-Cookie"},
 	}))
 	// global middleware
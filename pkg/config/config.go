@@ -5,6 +5,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -16,14 +17,93 @@ type Config struct {
 	UserService UserServiceConfig
 	Redis       RedisConfig
 	Database    DatabaseConfig
+	JWT         JWTConfig
+	Payment     PaymentConfig
+	Mail        MailConfig
+	Sms         SmsConfig
+	OAuth       OAuthConfig
+	Mfa         MfaConfig
+	WebAuthn    WebAuthnConfig
 }
 
 type App struct {
-	Env             string
-	WebDomain       string
-	AuthSessionTtl  time.Duration
-	AuthSessionsTtl time.Duration
-	AuthExetendTtl  time.Duration
+	Env                   string
+	WebDomain             string
+	AuthSessionTtl        time.Duration
+	AuthSessionsTtl       time.Duration
+	AuthExetendTtl        time.Duration
+	BookingHoldTtl        time.Duration
+	WaitlistOfferTtl      time.Duration
+	WaitlistSweepInterval time.Duration // jeda job yang meng-expire tawaran waitlist, 0 = job mati
+	DefaultCurrency       string        // currency quote untuk resource yang belum punya rate plan
+	PaymentTtl            time.Duration // batas bayar reservasi pending
+	PaymentSweepInterval  time.Duration // jeda job yang mengulang pembuatan intent / capture / refund ke provider yang belum berhasil, 0 = job mati
+
+	PublicURL           string        // base url api, dipakai untuk link di email
+	EmailVerifyTtl      time.Duration // masa berlaku link verifikasi email
+	EmailResendCooldown time.Duration // jeda minimal kirim ulang email verifikasi / reset password per email
+	PasswordResetTtl    time.Duration // masa berlaku link reset password
+	PasswordResetURL    string        // halaman reset password di frontend, token & email dikirim sebagai query
+	ReauthTtl           time.Duration // jendela "baru login" untuk aksi sensitif (tautkan / lepas login method)
+	PhoneOtpTtl         time.Duration // masa berlaku kode otp sms
+	PhoneOtpCooldown    time.Duration // jeda minimal kirim ulang otp per nomor
+}
+
+type JWTConfig struct {
+	Issuer          string
+	ActiveKid       string            // kid yang dipakai untuk sign token baru
+	Keys            map[string]string // kid -> secret, key lama tetap disimpan supaya token lama masih valid
+	AccessTokenTtl  time.Duration
+	RefreshTokenTtl time.Duration
+}
+
+type PaymentConfig struct {
+	Provider      string // fake
+	WebhookSecret string // secret HMAC signature webhook
+}
+
+type MailConfig struct {
+	Driver       string // log, smtp
+	From         string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUser     string
+	SMTPPassword string
+}
+
+type MfaConfig struct {
+	EncryptionKey string        // base64 32 byte, kunci AES-256-GCM untuk secret totp
+	Issuer        string        // nama aplikasi di authenticator
+	ChallengeTtl  time.Duration // batas waktu masukkan kode 2fa setelah password benar
+}
+
+// WebAuthnConfig relying party passkey. RPID harus domain (atau parent domain) dari origin frontend
+type WebAuthnConfig struct {
+	RPID          string
+	RPDisplayName string
+	RPOrigins     []string      // origin frontend yang boleh memanggil navigator.credentials
+	ChallengeTtl  time.Duration // masa berlaku challenge registrasi / login passkey
+}
+
+type SmsConfig struct {
+	Driver string // log
+}
+
+type OAuthConfig struct {
+	StateTtl   time.Duration // masa berlaku state + code verifier PKCE di redis
+	SuccessURL string        // redirect setelah login sosial berhasil, kosong = balas json
+	Google     OAuthProviderConfig
+	GitHub     OAuthProviderConfig
+}
+
+// OAuthProviderConfig provider aktif kalau ClientID diisi. endpoint bisa diganti untuk fake server
+type OAuthProviderConfig struct {
+	ClientID     string
+	ClientSecret string
+	AuthURL      string
+	TokenURL     string
+	UserInfoURL  string
+	EmailsURL    string // github: email user ada di endpoint terpisah
 }
 
 type GatewayConfig struct {
@@ -64,11 +144,26 @@ func LoadConfig() *Config {
 
 	return &Config{
 		App: App{
-			Env:             getEnv("APP_ENV", "dev"),
-			WebDomain:       getEnv("APP_WEB_DOMAIN", "localhost"),
-			AuthSessionTtl:  getEnvDuration("APP_AUTH_SESSION_TTL", 24*time.Hour),
-			AuthSessionsTtl: getEnvDuration("APP_AUTH_SESSIONS_TTL", 7*24*time.Hour),
-			AuthExetendTtl:  getEnvDuration("APP_AUTH_EXTEND_TTL", 30*time.Minute),
+			Env:                   getEnv("APP_ENV", "dev"),
+			WebDomain:             getEnv("APP_WEB_DOMAIN", "localhost"),
+			AuthSessionTtl:        getEnvDuration("APP_AUTH_SESSION_TTL", 24*time.Hour),
+			AuthSessionsTtl:       getEnvDuration("APP_AUTH_SESSIONS_TTL", 7*24*time.Hour),
+			AuthExetendTtl:        getEnvDuration("APP_AUTH_EXTEND_TTL", 30*time.Minute),
+			BookingHoldTtl:        getEnvDuration("APP_BOOKING_HOLD_TTL", 10*time.Minute),
+			WaitlistOfferTtl:      getEnvDuration("APP_WAITLIST_OFFER_TTL", 30*time.Minute),
+			WaitlistSweepInterval: getEnvDuration("APP_WAITLIST_SWEEP_INTERVAL", 1*time.Minute),
+			DefaultCurrency:       getEnv("APP_DEFAULT_CURRENCY", "IDR"),
+			PaymentTtl:            getEnvDuration("APP_PAYMENT_TTL", 30*time.Minute),
+			PaymentSweepInterval:  getEnvDuration("APP_PAYMENT_SWEEP_INTERVAL", 1*time.Minute),
+
+			PublicURL:           getEnv("APP_PUBLIC_URL", "http://localhost:8080"),
+			EmailVerifyTtl:      getEnvDuration("APP_EMAIL_VERIFY_TTL", 24*time.Hour),
+			EmailResendCooldown: getEnvDuration("APP_EMAIL_RESEND_COOLDOWN", 1*time.Minute),
+			PasswordResetTtl:    getEnvDuration("APP_PASSWORD_RESET_TTL", 30*time.Minute),
+			PasswordResetURL:    getEnv("APP_PASSWORD_RESET_URL", "http://localhost:3000/reset-password"),
+			ReauthTtl:           getEnvDuration("APP_REAUTH_TTL", 5*time.Minute),
+			PhoneOtpTtl:         getEnvDuration("APP_PHONE_OTP_TTL", 5*time.Minute),
+			PhoneOtpCooldown:    getEnvDuration("APP_PHONE_OTP_COOLDOWN", 1*time.Minute),
 		},
 		Gateway: GatewayConfig{
 			Port: getEnv("GATEWAY_PORT", "8080"),
@@ -94,6 +189,58 @@ func LoadConfig() *Config {
 			ConnMaxLifetime: getEnvDuration("DB_CONN_MAX_LIFETIME", 1*time.Hour),
 			ConnMaxIdleTime: getEnvDuration("DB_CONN_MAX_IDLE_TIME", 15*time.Minute),
 		},
+		JWT: JWTConfig{
+			Issuer:          getEnv("JWT_ISSUER", "booking"),
+			ActiveKid:       getEnv("JWT_ACTIVE_KID", ""),
+			Keys:            getEnvMap("JWT_KEYS"),
+			AccessTokenTtl:  getEnvDuration("JWT_ACCESS_TOKEN_TTL", 15*time.Minute),
+			RefreshTokenTtl: getEnvDuration("JWT_REFRESH_TOKEN_TTL", 30*24*time.Hour),
+		},
+		Mail: MailConfig{
+			Driver:       getEnv("MAIL_DRIVER", "log"),
+			From:         getEnv("MAIL_FROM", "no-reply@localhost"),
+			SMTPHost:     getEnv("SMTP_HOST", "localhost"),
+			SMTPPort:     getEnv("SMTP_PORT", "587"),
+			SMTPUser:     getEnv("SMTP_USER", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		},
+		Mfa: MfaConfig{
+			EncryptionKey: getEnv("MFA_ENCRYPTION_KEY", ""),
+			Issuer:        getEnv("MFA_ISSUER", "Booking"),
+			ChallengeTtl:  getEnvDuration("MFA_CHALLENGE_TTL", 5*time.Minute),
+		},
+		WebAuthn: WebAuthnConfig{
+			RPID:          getEnv("WEBAUTHN_RP_ID", "localhost"),
+			RPDisplayName: getEnv("WEBAUTHN_RP_NAME", "Booking"),
+			RPOrigins:     getEnvList("WEBAUTHN_RP_ORIGINS", []string{"http://localhost:3000"}),
+			ChallengeTtl:  getEnvDuration("WEBAUTHN_CHALLENGE_TTL", 5*time.Minute),
+		},
+		Sms: SmsConfig{
+			Driver: getEnv("SMS_DRIVER", "log"),
+		},
+		OAuth: OAuthConfig{
+			StateTtl:   getEnvDuration("OAUTH_STATE_TTL", 10*time.Minute),
+			SuccessURL: getEnv("OAUTH_SUCCESS_URL", ""),
+			Google: OAuthProviderConfig{
+				ClientID:     getEnv("OAUTH_GOOGLE_CLIENT_ID", ""),
+				ClientSecret: getEnv("OAUTH_GOOGLE_CLIENT_SECRET", ""),
+				AuthURL:      getEnv("OAUTH_GOOGLE_AUTH_URL", "https://accounts.google.com/o/oauth2/v2/auth"),
+				TokenURL:     getEnv("OAUTH_GOOGLE_TOKEN_URL", "https://oauth2.googleapis.com/token"),
+				UserInfoURL:  getEnv("OAUTH_GOOGLE_USERINFO_URL", "https://openidconnect.googleapis.com/v1/userinfo"),
+			},
+			GitHub: OAuthProviderConfig{
+				ClientID:     getEnv("OAUTH_GITHUB_CLIENT_ID", ""),
+				ClientSecret: getEnv("OAUTH_GITHUB_CLIENT_SECRET", ""),
+				AuthURL:      getEnv("OAUTH_GITHUB_AUTH_URL", "https://github.com/login/oauth/authorize"),
+				TokenURL:     getEnv("OAUTH_GITHUB_TOKEN_URL", "https://github.com/login/oauth/access_token"),
+				UserInfoURL:  getEnv("OAUTH_GITHUB_USERINFO_URL", "https://api.github.com/user"),
+				EmailsURL:    getEnv("OAUTH_GITHUB_EMAILS_URL", "https://api.github.com/user/emails"),
+			},
+		},
+		Payment: PaymentConfig{
+			Provider:      getEnv("PAYMENT_PROVIDER", "fake"),
+			WebhookSecret: getEnv("PAYMENT_WEBHOOK_SECRET", ""),
+		},
 	}
 }
 
@@ -124,3 +271,35 @@ func getEnvDuration(key string, fallback time.Duration) time.Duration {
 	}
 	return fallback
 }
+
+// getEnvMap returns environment variable with format "key1:value1,key2:value2" as map
+func getEnvMap(key string) map[string]string {
+	res := make(map[string]string)
+	v := os.Getenv(key)
+	if v == "" {
+		return res
+	}
+	for _, pair := range strings.Split(v, ",") {
+		k, val, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok || k == "" || val == "" {
+			continue
+		}
+		res[k] = val
+	}
+	return res
+}
+
+// getEnvList returns environment variable with format "a,b,c" as slice or fallback value
+func getEnvList(key string, fallback []string) []string {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	var res []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			res = append(res, item)
+		}
+	}
+	return res
+}
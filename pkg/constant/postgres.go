@@ -6,6 +6,7 @@ const (
 	PgErrUniqueViolation     = "23505" // duplicate key
 	PgErrForeignKeyViolation = "23503" // foreign key violation
 	PgErrNotNullViolation    = "23502" // null value in column that disallows nulls
+	PgErrExclusionViolation  = "23P01" // exclusion constraint violation (e.g., overlapping tstzrange)
 	// PgErrCheckViolation      = "23514" // check constraint violation
 
 	// Transaction errors
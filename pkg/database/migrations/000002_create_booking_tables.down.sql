@@ -0,0 +1,12 @@
+-- Drop index dulu sebelum drop tabel
+DROP INDEX IF EXISTS idx_reservations_user;
+DROP INDEX IF EXISTS idx_reservations_resource_time;
+DROP INDEX IF EXISTS idx_availability_rules_resource;
+DROP INDEX IF EXISTS idx_resources_owner;
+
+-- Drop tabel child dulu (karena ada FK ke resources)
+DROP TABLE IF EXISTS reservations;
+DROP TABLE IF EXISTS availability_rules;
+
+-- Baru drop tabel parent
+DROP TABLE IF EXISTS resources;
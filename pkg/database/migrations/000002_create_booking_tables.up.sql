@@ -0,0 +1,50 @@
+CREATE TABLE IF NOT EXISTS resources (
+  id              UUID PRIMARY KEY,
+  owner_id        UUID NOT NULL,
+  name            VARCHAR(100) NOT NULL,
+  description     TEXT,
+  timezone        VARCHAR(64) NOT NULL DEFAULT 'UTC', -- IANA timezone, jam buka dihitung di timezone ini
+  is_active       BOOLEAN NOT NULL DEFAULT TRUE,
+  created_at      TIMESTAMP NOT NULL DEFAULT now(),
+  updated_at      TIMESTAMP NOT NULL DEFAULT now(),
+
+  FOREIGN KEY(owner_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS availability_rules (
+  id              UUID PRIMARY KEY,
+  resource_id     UUID NOT NULL,
+  day_of_week     SMALLINT NOT NULL,      -- 0 = minggu ... 6 = sabtu
+  start_time      TIME NOT NULL,
+  end_time        TIME NOT NULL,
+  created_at      TIMESTAMP NOT NULL DEFAULT now(),
+
+  CONSTRAINT chk_availability_day CHECK (day_of_week BETWEEN 0 AND 6),
+  CONSTRAINT chk_availability_time CHECK (start_time < end_time),
+
+  FOREIGN KEY(resource_id) REFERENCES resources(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS reservations (
+  id              UUID PRIMARY KEY,
+  resource_id     UUID NOT NULL,
+  user_id         UUID NOT NULL,
+  start_time      TIMESTAMPTZ NOT NULL,
+  end_time        TIMESTAMPTZ NOT NULL,
+  status          VARCHAR(20) NOT NULL DEFAULT 'confirmed', -- 'confirmed', 'cancelled'
+  notes           VARCHAR(500),
+  cancelled_at    TIMESTAMPTZ,
+  created_at      TIMESTAMP NOT NULL DEFAULT now(),
+  updated_at      TIMESTAMP NOT NULL DEFAULT now(),
+
+  CONSTRAINT chk_reservation_time CHECK (start_time < end_time),
+
+  FOREIGN KEY(resource_id) REFERENCES resources(id) ON DELETE CASCADE,
+  FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Index tambahan untuk query
+CREATE INDEX idx_resources_owner ON resources(owner_id);
+CREATE INDEX idx_availability_rules_resource ON availability_rules(resource_id, day_of_week);
+CREATE INDEX idx_reservations_resource_time ON reservations(resource_id, start_time, end_time);
+CREATE INDEX idx_reservations_user ON reservations(user_id, start_time DESC);
@@ -0,0 +1,2 @@
+ALTER TABLE reservations DROP CONSTRAINT IF EXISTS excl_reservations_resource_period;
+ALTER TABLE reservations DROP COLUMN IF EXISTS period;
@@ -0,0 +1,13 @@
+-- btree_gist dibutuhkan supaya kolom uuid (resource_id) bisa dipakai di exclusion constraint GiST
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+-- period [start_time, end_time), otomatis ikut start_time & end_time
+ALTER TABLE reservations
+  ADD COLUMN period TSTZRANGE GENERATED ALWAYS AS (tstzrange(start_time, end_time, '[)')) STORED;
+
+-- constraint: 1 resource tidak boleh punya 2 reservasi aktif yang waktunya overlap
+-- (race condition antar request di-handle postgres, bukan di aplikasi)
+ALTER TABLE reservations
+  ADD CONSTRAINT excl_reservations_resource_period
+  EXCLUDE USING gist (resource_id WITH =, period WITH &&)
+  WHERE (status <> 'cancelled');
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_availability_exceptions_resource_date;
+DROP TABLE IF EXISTS availability_exceptions;
+
+ALTER TABLE resources
+  DROP CONSTRAINT IF EXISTS chk_resources_slot_interval,
+  DROP CONSTRAINT IF EXISTS chk_resources_buffer,
+  DROP COLUMN IF EXISTS slot_interval_minutes,
+  DROP COLUMN IF EXISTS buffer_minutes;
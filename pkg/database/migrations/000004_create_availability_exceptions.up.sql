@@ -0,0 +1,24 @@
+ALTER TABLE resources
+  ADD COLUMN buffer_minutes        INT NOT NULL DEFAULT 0,  -- jeda minimal antar booking
+  ADD COLUMN slot_interval_minutes INT NOT NULL DEFAULT 15, -- jarak antar jam mulai slot di availability search
+  ADD CONSTRAINT chk_resources_buffer CHECK (buffer_minutes >= 0),
+  ADD CONSTRAINT chk_resources_slot_interval CHECK (slot_interval_minutes > 0);
+
+-- penutupan di tanggal tertentu (libur, maintenance), override jam buka mingguan
+CREATE TABLE IF NOT EXISTS availability_exceptions (
+  id              UUID PRIMARY KEY,
+  resource_id     UUID NOT NULL,
+  date            DATE NOT NULL,          -- tanggal di timezone resource
+  start_time      TIME,                   -- NULL = tutup seharian
+  end_time        TIME,
+  reason          VARCHAR(255),
+  created_at      TIMESTAMP NOT NULL DEFAULT now(),
+
+  CONSTRAINT chk_availability_exception_time CHECK (
+    (start_time IS NULL AND end_time IS NULL) OR (start_time IS NOT NULL AND end_time IS NOT NULL AND start_time < end_time)
+  ),
+
+  FOREIGN KEY(resource_id) REFERENCES resources(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_availability_exceptions_resource_date ON availability_exceptions(resource_id, date);
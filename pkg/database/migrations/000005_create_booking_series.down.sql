@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_reservations_series;
+DROP INDEX IF EXISTS idx_booking_series_user;
+
+ALTER TABLE reservations
+  DROP COLUMN IF EXISTS occurrence_start,
+  DROP COLUMN IF EXISTS series_id;
+
+DROP TABLE IF EXISTS booking_series;
@@ -0,0 +1,26 @@
+-- booking berulang (RFC 5545 RRULE). occurrence nya di-expand jadi baris reservations,
+-- jadi cek bentrok tetap dijaga exclusion constraint dan list booking gak perlu expand ulang
+CREATE TABLE IF NOT EXISTS booking_series (
+  id                UUID PRIMARY KEY,
+  resource_id       UUID NOT NULL,
+  user_id           UUID NOT NULL,
+  rrule             VARCHAR(500) NOT NULL,  -- tanpa DTSTART, e.g. FREQ=WEEKLY;COUNT=10;BYDAY=MO
+  start_time        TIMESTAMPTZ NOT NULL,   -- DTSTART (occurrence pertama)
+  duration_minutes  INT NOT NULL,
+  timezone          VARCHAR(64) NOT NULL,   -- timezone saat expand rrule (timezone resource)
+  notes             VARCHAR(500),
+  created_at        TIMESTAMP NOT NULL DEFAULT now(),
+  updated_at        TIMESTAMP NOT NULL DEFAULT now(),
+
+  CONSTRAINT chk_booking_series_duration CHECK (duration_minutes > 0),
+
+  FOREIGN KEY(resource_id) REFERENCES resources(id) ON DELETE CASCADE,
+  FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+ALTER TABLE reservations
+  ADD COLUMN series_id        UUID REFERENCES booking_series(id) ON DELETE CASCADE,
+  ADD COLUMN occurrence_start TIMESTAMPTZ; -- jadwal asli occurrence (RECURRENCE-ID), tetap walau occurrence dipindah
+
+CREATE INDEX idx_booking_series_user ON booking_series(user_id);
+CREATE INDEX idx_reservations_series ON reservations(series_id, occurrence_start);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS waitlist_entries;
+
+ALTER TABLE resources
+  DROP CONSTRAINT IF EXISTS chk_resources_waitlist_mode,
+  DROP COLUMN IF EXISTS waitlist_mode;
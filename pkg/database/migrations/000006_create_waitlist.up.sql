@@ -0,0 +1,33 @@
+-- offer = user pertama di antrian dapat tawaran yang harus di-accept sebelum expired
+-- auto  = user pertama langsung dibuatkan reservasi
+ALTER TABLE resources
+  ADD COLUMN waitlist_mode VARCHAR(20) NOT NULL DEFAULT 'offer',
+  ADD CONSTRAINT chk_resources_waitlist_mode CHECK (waitlist_mode IN ('offer', 'auto'));
+
+-- antrian untuk slot yang penuh, urut berdasarkan created_at (id uuidv7 sebagai tie breaker)
+CREATE TABLE IF NOT EXISTS waitlist_entries (
+  id                UUID PRIMARY KEY,
+  resource_id       UUID NOT NULL,
+  user_id           UUID NOT NULL,
+  start_time        TIMESTAMPTZ NOT NULL,
+  end_time          TIMESTAMPTZ NOT NULL,
+  status            VARCHAR(20) NOT NULL DEFAULT 'waiting', -- waiting, offered, promoted, left, expired
+  notes             VARCHAR(500),
+  offer_expires_at  TIMESTAMPTZ,   -- diisi saat status offered
+  reservation_id    UUID,          -- diisi saat status promoted
+  created_at        TIMESTAMP NOT NULL DEFAULT now(),
+  updated_at        TIMESTAMP NOT NULL DEFAULT now(),
+
+  CONSTRAINT chk_waitlist_time CHECK (end_time > start_time),
+  CONSTRAINT chk_waitlist_status CHECK (status IN ('waiting', 'offered', 'promoted', 'left', 'expired')),
+
+  FOREIGN KEY(resource_id) REFERENCES resources(id) ON DELETE CASCADE,
+  FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+  FOREIGN KEY(reservation_id) REFERENCES reservations(id) ON DELETE SET NULL
+);
+
+-- satu user cuma boleh antri sekali untuk slot yang sama
+CREATE UNIQUE INDEX uq_waitlist_active_slot ON waitlist_entries(resource_id, user_id, start_time, end_time)
+  WHERE status IN ('waiting', 'offered');
+CREATE INDEX idx_waitlist_resource_queue ON waitlist_entries(resource_id, status, created_at);
+CREATE INDEX idx_waitlist_user ON waitlist_entries(user_id, created_at);
@@ -0,0 +1,18 @@
+ALTER TABLE waitlist_entries
+  DROP CONSTRAINT IF EXISTS chk_waitlist_party_size,
+  DROP COLUMN IF EXISTS party_size;
+
+ALTER TABLE reservations DROP CONSTRAINT IF EXISTS excl_reservations_resource_period;
+ALTER TABLE reservations
+  ADD CONSTRAINT excl_reservations_resource_period
+  EXCLUDE USING gist (resource_id WITH =, period WITH &&)
+  WHERE (status <> 'cancelled');
+
+ALTER TABLE reservations
+  DROP CONSTRAINT IF EXISTS chk_reservations_party_size,
+  DROP COLUMN IF EXISTS exclusive,
+  DROP COLUMN IF EXISTS party_size;
+
+ALTER TABLE resources
+  DROP CONSTRAINT IF EXISTS chk_resources_capacity,
+  DROP COLUMN IF EXISTS capacity;
@@ -0,0 +1,22 @@
+-- capacity 1 = exclusive (satu booking per slot), > 1 = shared (kelas, tur, event) dihitung per kursi
+ALTER TABLE resources
+  ADD COLUMN capacity INT NOT NULL DEFAULT 1,
+  ADD CONSTRAINT chk_resources_capacity CHECK (capacity >= 1);
+
+-- exclusive di-copy dari resource saat insert, karena partial constraint cuma bisa baca kolom tabel sendiri
+ALTER TABLE reservations
+  ADD COLUMN party_size INT NOT NULL DEFAULT 1,
+  ADD COLUMN exclusive  BOOLEAN NOT NULL DEFAULT TRUE,
+  ADD CONSTRAINT chk_reservations_party_size CHECK (party_size >= 1);
+
+-- exclusion constraint cuma berlaku untuk resource exclusive. resource shared dijaga
+-- lewat lock row resource (SELECT ... FOR UPDATE) + hitung kursi terpakai di aplikasi
+ALTER TABLE reservations DROP CONSTRAINT excl_reservations_resource_period;
+ALTER TABLE reservations
+  ADD CONSTRAINT excl_reservations_resource_period
+  EXCLUDE USING gist (resource_id WITH =, period WITH &&)
+  WHERE (status <> 'cancelled' AND exclusive);
+
+ALTER TABLE waitlist_entries
+  ADD COLUMN party_size INT NOT NULL DEFAULT 1,
+  ADD CONSTRAINT chk_waitlist_party_size CHECK (party_size >= 1);
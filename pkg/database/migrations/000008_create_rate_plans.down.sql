@@ -0,0 +1,7 @@
+ALTER TABLE reservations
+  DROP COLUMN IF EXISTS price_snapshot,
+  DROP COLUMN IF EXISTS price_currency,
+  DROP COLUMN IF EXISTS price_amount;
+
+DROP TABLE IF EXISTS rate_plan_peaks;
+DROP TABLE IF EXISTS rate_plans;
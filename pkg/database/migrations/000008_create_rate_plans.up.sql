@@ -0,0 +1,46 @@
+-- harga per resource. semua nominal dalam minor unit (sen / rupiah) sesuai currency,
+-- multiplier dalam basis point (10000 = 1x) supaya hitungan tetap integer
+CREATE TABLE IF NOT EXISTS rate_plans (
+  id                     UUID PRIMARY KEY,
+  resource_id            UUID NOT NULL UNIQUE,
+  currency               CHAR(3) NOT NULL,             -- ISO 4217, e.g. IDR, USD
+  hourly_rate            BIGINT NOT NULL DEFAULT 0,
+  daily_rate             BIGINT NOT NULL DEFAULT 0,    -- batas maksimal per hari kalender, 0 = tanpa batas
+  per_seat_price         BIGINT NOT NULL DEFAULT 0,    -- per orang per booking
+  minimum_charge         BIGINT NOT NULL DEFAULT 0,
+  weekday_multiplier_bp  INT NOT NULL DEFAULT 10000,
+  weekend_multiplier_bp  INT NOT NULL DEFAULT 10000,   -- sabtu & minggu
+  created_at             TIMESTAMP NOT NULL DEFAULT now(),
+  updated_at             TIMESTAMP NOT NULL DEFAULT now(),
+
+  CONSTRAINT chk_rate_plans_amount CHECK (
+    hourly_rate >= 0 AND daily_rate >= 0 AND per_seat_price >= 0 AND minimum_charge >= 0
+  ),
+  CONSTRAINT chk_rate_plans_multiplier CHECK (weekday_multiplier_bp > 0 AND weekend_multiplier_bp > 0),
+
+  FOREIGN KEY(resource_id) REFERENCES resources(id) ON DELETE CASCADE
+);
+
+-- multiplier jam tertentu (peak / off-peak), waktu dalam timezone resource
+CREATE TABLE IF NOT EXISTS rate_plan_peaks (
+  id              UUID PRIMARY KEY,
+  rate_plan_id    UUID NOT NULL,
+  day_of_week     SMALLINT,               -- NULL = setiap hari, 0 = minggu ... 6 = sabtu
+  start_time      TIME NOT NULL,
+  end_time        TIME NOT NULL,
+  multiplier_bp   INT NOT NULL,
+
+  CONSTRAINT chk_rate_plan_peaks_day CHECK (day_of_week IS NULL OR day_of_week BETWEEN 0 AND 6),
+  CONSTRAINT chk_rate_plan_peaks_time CHECK (start_time < end_time),
+  CONSTRAINT chk_rate_plan_peaks_multiplier CHECK (multiplier_bp > 0),
+
+  FOREIGN KEY(rate_plan_id) REFERENCES rate_plans(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_rate_plan_peaks_plan ON rate_plan_peaks(rate_plan_id);
+
+-- snapshot harga saat booking dibuat, gak berubah walau rate plan diganti
+ALTER TABLE reservations
+  ADD COLUMN price_amount   BIGINT NOT NULL DEFAULT 0,
+  ADD COLUMN price_currency CHAR(3),
+  ADD COLUMN price_snapshot JSONB;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS coupon_redemptions;
+DROP TABLE IF EXISTS coupon_resources;
+DROP TABLE IF EXISTS coupons;
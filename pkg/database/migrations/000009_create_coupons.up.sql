@@ -0,0 +1,57 @@
+CREATE TABLE IF NOT EXISTS coupons (
+  id                        UUID PRIMARY KEY,
+  code                      VARCHAR(50) NOT NULL,    -- disimpan uppercase
+  discount_type             VARCHAR(20) NOT NULL,    -- percent, fixed
+  percent_off               INT,                     -- 1-100, untuk percent
+  amount_off                BIGINT,                  -- minor unit, untuk fixed
+  currency                  CHAR(3),                 -- wajib untuk fixed, juga dipakai untuk min_spend
+  min_spend                 BIGINT NOT NULL DEFAULT 0,
+  max_redemptions           INT,                     -- NULL = tanpa batas
+  max_redemptions_per_user  INT,                     -- NULL = tanpa batas
+  times_redeemed            INT NOT NULL DEFAULT 0,
+  valid_from                TIMESTAMPTZ,
+  valid_until               TIMESTAMPTZ,
+  is_active                 BOOLEAN NOT NULL DEFAULT TRUE,
+  created_by                UUID NOT NULL,
+  created_at                TIMESTAMP NOT NULL DEFAULT now(),
+  updated_at                TIMESTAMP NOT NULL DEFAULT now(),
+
+  CONSTRAINT uq_coupons_code UNIQUE (code),
+  CONSTRAINT chk_coupons_discount CHECK (
+    (discount_type = 'percent' AND percent_off BETWEEN 1 AND 100) OR
+    (discount_type = 'fixed' AND amount_off > 0 AND currency IS NOT NULL)
+  ),
+  CONSTRAINT chk_coupons_limits CHECK (
+    (max_redemptions IS NULL OR max_redemptions > 0) AND
+    (max_redemptions_per_user IS NULL OR max_redemptions_per_user > 0) AND
+    min_spend >= 0
+  ),
+  CONSTRAINT chk_coupons_validity CHECK (valid_from IS NULL OR valid_until IS NULL OR valid_from < valid_until),
+
+  FOREIGN KEY(created_by) REFERENCES users(id)
+);
+
+-- scope resource, kosong = berlaku untuk semua resource
+CREATE TABLE IF NOT EXISTS coupon_resources (
+  coupon_id       UUID NOT NULL,
+  resource_id     UUID NOT NULL,
+
+  PRIMARY KEY (coupon_id, resource_id),
+  FOREIGN KEY(coupon_id) REFERENCES coupons(id) ON DELETE CASCADE,
+  FOREIGN KEY(resource_id) REFERENCES resources(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS coupon_redemptions (
+  id              UUID PRIMARY KEY,
+  coupon_id       UUID NOT NULL,
+  user_id         UUID NOT NULL,
+  reservation_id  UUID NOT NULL,
+  amount          BIGINT NOT NULL,       -- potongan yang didapat, minor unit currency reservasi
+  created_at      TIMESTAMP NOT NULL DEFAULT now(),
+
+  FOREIGN KEY(coupon_id) REFERENCES coupons(id) ON DELETE CASCADE,
+  FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+  FOREIGN KEY(reservation_id) REFERENCES reservations(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_coupon_redemptions_coupon_user ON coupon_redemptions(coupon_id, user_id);
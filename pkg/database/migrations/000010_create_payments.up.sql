@@ -0,0 +1,23 @@
+-- reservasi berbayar dibuat 'pending' dulu, jadi 'confirmed' setelah webhook pembayaran masuk
+CREATE TABLE IF NOT EXISTS payments (
+  id              UUID PRIMARY KEY,
+  reservation_id  UUID NOT NULL,
+  user_id         UUID NOT NULL,
+  provider        VARCHAR(30) NOT NULL,    -- fake, ...
+  provider_ref    VARCHAR(255) NOT NULL,   -- id payment intent di provider
+  client_secret   VARCHAR(255),            -- dipakai client untuk menyelesaikan pembayaran
+  amount          BIGINT NOT NULL,         -- minor unit
+  currency        CHAR(3) NOT NULL,
+  status          VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, succeeded, failed, refunded
+  refunded_amount BIGINT NOT NULL DEFAULT 0,
+  created_at      TIMESTAMP NOT NULL DEFAULT now(),
+  updated_at      TIMESTAMP NOT NULL DEFAULT now(),
+
+  CONSTRAINT uq_payments_provider_ref UNIQUE (provider, provider_ref),
+  CONSTRAINT chk_payments_amount CHECK (amount > 0 AND refunded_amount BETWEEN 0 AND amount),
+
+  FOREIGN KEY(reservation_id) REFERENCES reservations(id) ON DELETE CASCADE,
+  FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_payments_reservation ON payments(reservation_id, created_at DESC);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS refunds;
+
+ALTER TABLE reservations
+  DROP COLUMN IF EXISTS cancellation_policy;
+
+ALTER TABLE resources
+  DROP COLUMN IF EXISTS cancellation_policy;
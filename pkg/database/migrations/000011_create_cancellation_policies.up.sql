@@ -0,0 +1,25 @@
+-- tier refund, e.g: {"tiers": [{"hours_before": 48, "refund_bp": 10000}, {"hours_before": 0, "refund_bp": 5000}]}
+ALTER TABLE resources
+  ADD COLUMN IF NOT EXISTS cancellation_policy JSONB;
+
+-- snapshot policy resource saat booking dibuat, perubahan policy gak berlaku surut
+ALTER TABLE reservations
+  ADD COLUMN IF NOT EXISTS cancellation_policy JSONB;
+
+CREATE TABLE IF NOT EXISTS refunds (
+  id              UUID PRIMARY KEY,
+  payment_id      UUID NOT NULL,
+  reservation_id  UUID NOT NULL,
+  amount          BIGINT NOT NULL,        -- yang dikembalikan, minor unit
+  retained_amount BIGINT NOT NULL,        -- yang ditahan sesuai policy
+  refund_bp       INT NOT NULL,           -- persentase refund dalam basis point
+  reason          VARCHAR(30) NOT NULL,   -- cancellation, late_payment
+  created_at      TIMESTAMP NOT NULL DEFAULT now(),
+
+  CONSTRAINT chk_refunds_amount CHECK (amount >= 0 AND retained_amount >= 0 AND refund_bp BETWEEN 0 AND 10000),
+
+  FOREIGN KEY(payment_id) REFERENCES payments(id) ON DELETE CASCADE,
+  FOREIGN KEY(reservation_id) REFERENCES reservations(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_refunds_reservation ON refunds(reservation_id);
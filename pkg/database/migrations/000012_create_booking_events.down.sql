@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS booking_events;
+
+ALTER TABLE reservations DROP CONSTRAINT excl_reservations_resource_period;
+ALTER TABLE reservations
+  ADD CONSTRAINT excl_reservations_resource_period
+  EXCLUDE USING gist (resource_id WITH =, period WITH &&)
+  WHERE (status <> 'cancelled' AND exclusive);
+
+ALTER TABLE reservations
+  DROP COLUMN IF EXISTS payment_due_at,
+  DROP CONSTRAINT IF EXISTS chk_reservation_status;
@@ -0,0 +1,28 @@
+ALTER TABLE reservations
+  ADD CONSTRAINT chk_reservation_status CHECK (
+    status IN ('pending', 'held', 'confirmed', 'checked_in', 'completed', 'cancelled', 'no_show', 'expired')
+  ),
+  ADD COLUMN IF NOT EXISTS payment_due_at TIMESTAMPTZ; -- batas bayar reservasi pending, lewat = expired
+
+-- reservasi expired juga melepas slot nya
+ALTER TABLE reservations DROP CONSTRAINT excl_reservations_resource_period;
+ALTER TABLE reservations
+  ADD CONSTRAINT excl_reservations_resource_period
+  EXCLUDE USING gist (resource_id WITH =, period WITH &&)
+  WHERE (status NOT IN ('cancelled', 'expired') AND exclusive);
+
+-- audit setiap perubahan status reservasi
+CREATE TABLE IF NOT EXISTS booking_events (
+  id              UUID PRIMARY KEY,
+  reservation_id  UUID NOT NULL,
+  actor_id        UUID,                   -- NULL = sistem (webhook, promosi waitlist)
+  from_status     VARCHAR(20),            -- NULL = reservasi baru dibuat
+  to_status       VARCHAR(20) NOT NULL,
+  reason          VARCHAR(255),
+  created_at      TIMESTAMP NOT NULL DEFAULT now(),
+
+  FOREIGN KEY(reservation_id) REFERENCES reservations(id) ON DELETE CASCADE,
+  FOREIGN KEY(actor_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_booking_events_reservation ON booking_events(reservation_id, created_at);
@@ -0,0 +1,23 @@
+-- 2fa totp, satu secret per user
+CREATE TABLE IF NOT EXISTS user_mfa (
+  user_id         UUID PRIMARY KEY,
+  totp_secret     TEXT NOT NULL,          -- terenkripsi AES-GCM (MFA_ENCRYPTION_KEY), base64
+  enabled         BOOLEAN NOT NULL DEFAULT FALSE, -- true setelah enrollment dikonfirmasi dengan kode pertama
+  enabled_at      TIMESTAMPTZ,
+  created_at      TIMESTAMP NOT NULL DEFAULT now(),
+  updated_at      TIMESTAMP NOT NULL DEFAULT now(),
+
+  FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- recovery code sekali pakai, yang disimpan cuma hash nya
+CREATE TABLE IF NOT EXISTS user_recovery_codes (
+  id              UUID PRIMARY KEY,
+  user_id         UUID NOT NULL,
+  code_hash       VARCHAR(64) NOT NULL,   -- sha256 hex
+  used_at         TIMESTAMPTZ,
+  created_at      TIMESTAMP NOT NULL DEFAULT now(),
+
+  CONSTRAINT uq_user_recovery_code UNIQUE (user_id, code_hash),
+  FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,22 @@
+-- passkey (webauthn), satu user bisa punya banyak. semua credential nempel ke identity provider 'passkey'
+-- milik user, jadi lepas identity passkey = hapus semua passkey nya
+CREATE TABLE IF NOT EXISTS passkey_credentials (
+  id                BYTEA PRIMARY KEY,        -- credential id dari authenticator
+  user_id           UUID NOT NULL,
+  identity_id       UUID NOT NULL,
+  name              VARCHAR(100) NOT NULL,    -- label dari user, e.g. "iPhone", "YubiKey"
+  public_key        BYTEA NOT NULL,           -- COSE public key
+  attestation_type  VARCHAR(50) NOT NULL,
+  aaguid            BYTEA,
+  sign_count        BIGINT NOT NULL DEFAULT 0, -- counter terakhir, turun / gak naik = kemungkinan authenticator di-clone
+  transports        VARCHAR(255) NOT NULL DEFAULT '', -- dipisah koma: usb, nfc, ble, internal, hybrid
+  backup_eligible   BOOLEAN NOT NULL DEFAULT FALSE,
+  backup_state      BOOLEAN NOT NULL DEFAULT FALSE,
+  last_used_at      TIMESTAMPTZ,
+  created_at        TIMESTAMP NOT NULL DEFAULT now(),
+
+  FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+  FOREIGN KEY(identity_id) REFERENCES user_identities(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_passkey_credentials_user ON passkey_credentials(user_id);
@@ -0,0 +1,3 @@
+ALTER TABLE users DROP CONSTRAINT IF EXISTS chk_users_role;
+
+UPDATE users SET role = 'user' WHERE role IN ('staff', 'resource_owner');
@@ -0,0 +1,8 @@
+-- role yang valid sesuai matrix hak akses (internal/domain/rbac.go)
+UPDATE users SET role = 'user' WHERE role NOT IN ('user', 'staff', 'resource_owner', 'admin');
+
+-- user yang sudah punya resource tetap bisa kelola resource nya
+UPDATE users SET role = 'resource_owner', updated_at = now()
+WHERE role = 'user' AND id IN (SELECT owner_id FROM resources);
+
+ALTER TABLE users ADD CONSTRAINT chk_users_role CHECK (role IN ('user', 'staff', 'resource_owner', 'admin'));
@@ -0,0 +1,25 @@
+DROP INDEX IF EXISTS idx_waitlist_entries_org_user;
+DROP INDEX IF EXISTS idx_reservations_org_user;
+DROP INDEX IF EXISTS idx_resources_org;
+
+ALTER TABLE payments DROP COLUMN IF EXISTS org_id;
+ALTER TABLE coupon_resources DROP COLUMN IF EXISTS org_id;
+ALTER TABLE waitlist_entries DROP COLUMN IF EXISTS org_id;
+ALTER TABLE booking_series DROP COLUMN IF EXISTS org_id;
+ALTER TABLE reservations DROP CONSTRAINT IF EXISTS uq_reservations_id_org;
+ALTER TABLE reservations DROP COLUMN IF EXISTS org_id;
+
+-- kode yang sama di org berbeda harus dibedakan dulu sebelum unik global lagi
+UPDATE coupons c SET code = left(c.code, 41) || '-' || upper(left(c.org_id::text, 8))
+WHERE EXISTS (SELECT 1 FROM coupons o WHERE o.code = c.code AND o.id <> c.id)
+  AND c.org_id <> '00000000-0000-0000-0000-000000000001';
+ALTER TABLE coupons DROP CONSTRAINT IF EXISTS uq_coupons_id_org;
+ALTER TABLE coupons DROP CONSTRAINT IF EXISTS uq_coupons_org_code;
+ALTER TABLE coupons DROP COLUMN IF EXISTS org_id;
+ALTER TABLE coupons ADD CONSTRAINT uq_coupons_code UNIQUE (code);
+
+ALTER TABLE resources DROP CONSTRAINT IF EXISTS uq_resources_id_org;
+ALTER TABLE resources DROP COLUMN IF EXISTS org_id;
+
+DROP TABLE IF EXISTS organization_members;
+DROP TABLE IF EXISTS organizations;
@@ -0,0 +1,92 @@
+-- multi-tenant: resource, booking, kupon dan pembayaran milik satu org.
+-- org_id ditaruh langsung di tabel yang di-query per org, tabel anak (rules, exception, rate plan, event, refund)
+-- ikut org resource / reservasi induknya
+CREATE TABLE IF NOT EXISTS organizations (
+  id              UUID PRIMARY KEY,
+  slug            VARCHAR(63) NOT NULL,    -- dipakai di subdomain, path /orgs/:org dan header X-Org
+  name            VARCHAR(100) NOT NULL,
+  created_at      TIMESTAMP NOT NULL DEFAULT now(),
+  updated_at      TIMESTAMP NOT NULL DEFAULT now(),
+
+  CONSTRAINT uq_organizations_slug UNIQUE (slug)
+);
+
+-- role per org, user yang bukan member tetap bisa booking sebagai pelanggan
+CREATE TABLE IF NOT EXISTS organization_members (
+  org_id          UUID NOT NULL,
+  user_id         UUID NOT NULL,
+  role            VARCHAR(20) NOT NULL,
+  created_at      TIMESTAMP NOT NULL DEFAULT now(),
+  updated_at      TIMESTAMP NOT NULL DEFAULT now(),
+
+  PRIMARY KEY (org_id, user_id),
+  CONSTRAINT chk_organization_members_role CHECK (role IN ('staff', 'resource_owner', 'admin')),
+
+  FOREIGN KEY(org_id) REFERENCES organizations(id) ON DELETE CASCADE,
+  FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_organization_members_user ON organization_members(user_id);
+
+-- semua data lama masuk ke org default
+INSERT INTO organizations (id, slug, name) VALUES ('00000000-0000-0000-0000-000000000001', 'default', 'Default');
+
+-- staff & pemilik resource lama jadi member org default dengan role yang sama
+INSERT INTO organization_members (org_id, user_id, role)
+SELECT '00000000-0000-0000-0000-000000000001', id, role FROM users WHERE role IN ('staff', 'resource_owner');
+
+ALTER TABLE resources ADD COLUMN org_id UUID;
+ALTER TABLE reservations ADD COLUMN org_id UUID;
+ALTER TABLE booking_series ADD COLUMN org_id UUID;
+ALTER TABLE waitlist_entries ADD COLUMN org_id UUID;
+ALTER TABLE coupons ADD COLUMN org_id UUID;
+ALTER TABLE coupon_resources ADD COLUMN org_id UUID;
+ALTER TABLE payments ADD COLUMN org_id UUID;
+
+UPDATE resources SET org_id = '00000000-0000-0000-0000-000000000001';
+UPDATE reservations SET org_id = '00000000-0000-0000-0000-000000000001';
+UPDATE booking_series SET org_id = '00000000-0000-0000-0000-000000000001';
+UPDATE waitlist_entries SET org_id = '00000000-0000-0000-0000-000000000001';
+UPDATE coupons SET org_id = '00000000-0000-0000-0000-000000000001';
+UPDATE coupon_resources SET org_id = '00000000-0000-0000-0000-000000000001';
+UPDATE payments SET org_id = '00000000-0000-0000-0000-000000000001';
+
+ALTER TABLE resources
+  ALTER COLUMN org_id SET NOT NULL,
+  ADD CONSTRAINT fk_resources_org FOREIGN KEY(org_id) REFERENCES organizations(id) ON DELETE CASCADE,
+  ADD CONSTRAINT uq_resources_id_org UNIQUE (id, org_id);
+
+-- FK komposit (id induk, org_id): database menolak data yang nyambung ke induk milik org lain
+ALTER TABLE reservations
+  ALTER COLUMN org_id SET NOT NULL,
+  ADD CONSTRAINT fk_reservations_resource_org FOREIGN KEY(resource_id, org_id) REFERENCES resources(id, org_id) ON DELETE CASCADE,
+  ADD CONSTRAINT uq_reservations_id_org UNIQUE (id, org_id);
+
+ALTER TABLE booking_series
+  ALTER COLUMN org_id SET NOT NULL,
+  ADD CONSTRAINT fk_booking_series_resource_org FOREIGN KEY(resource_id, org_id) REFERENCES resources(id, org_id) ON DELETE CASCADE;
+
+ALTER TABLE waitlist_entries
+  ALTER COLUMN org_id SET NOT NULL,
+  ADD CONSTRAINT fk_waitlist_entries_resource_org FOREIGN KEY(resource_id, org_id) REFERENCES resources(id, org_id) ON DELETE CASCADE;
+
+-- kode kupon unik per org
+ALTER TABLE coupons
+  ALTER COLUMN org_id SET NOT NULL,
+  DROP CONSTRAINT uq_coupons_code,
+  ADD CONSTRAINT uq_coupons_org_code UNIQUE (org_id, code),
+  ADD CONSTRAINT uq_coupons_id_org UNIQUE (id, org_id),
+  ADD CONSTRAINT fk_coupons_org FOREIGN KEY(org_id) REFERENCES organizations(id) ON DELETE CASCADE;
+
+ALTER TABLE coupon_resources
+  ALTER COLUMN org_id SET NOT NULL,
+  ADD CONSTRAINT fk_coupon_resources_coupon_org FOREIGN KEY(coupon_id, org_id) REFERENCES coupons(id, org_id) ON DELETE CASCADE,
+  ADD CONSTRAINT fk_coupon_resources_resource_org FOREIGN KEY(resource_id, org_id) REFERENCES resources(id, org_id) ON DELETE CASCADE;
+
+ALTER TABLE payments
+  ALTER COLUMN org_id SET NOT NULL,
+  ADD CONSTRAINT fk_payments_reservation_org FOREIGN KEY(reservation_id, org_id) REFERENCES reservations(id, org_id) ON DELETE CASCADE;
+
+CREATE INDEX idx_resources_org ON resources(org_id, created_at DESC);
+CREATE INDEX idx_reservations_org_user ON reservations(org_id, user_id, start_time DESC);
+CREATE INDEX idx_waitlist_entries_org_user ON waitlist_entries(org_id, user_id);
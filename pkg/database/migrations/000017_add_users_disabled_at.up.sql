@@ -0,0 +1,4 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS disabled_at TIMESTAMP;
+
+-- daftar user di admin diurutkan / difilter berdasarkan waktu daftar
+CREATE INDEX IF NOT EXISTS idx_users_created_at ON users(created_at);
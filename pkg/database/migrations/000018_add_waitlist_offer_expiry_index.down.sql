@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_waitlist_offer_expiry;
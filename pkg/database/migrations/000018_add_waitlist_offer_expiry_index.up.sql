@@ -0,0 +1,3 @@
+-- job sweep cari tawaran waitlist yang sudah lewat waktu
+CREATE INDEX IF NOT EXISTS idx_waitlist_offer_expiry ON waitlist_entries(offer_expires_at)
+  WHERE status = 'offered';
@@ -0,0 +1,5 @@
+ALTER TABLE reservations DROP CONSTRAINT IF EXISTS chk_reservation_status;
+ALTER TABLE reservations
+  ADD CONSTRAINT chk_reservation_status CHECK (
+    status IN ('pending', 'held', 'confirmed', 'checked_in', 'completed', 'cancelled', 'no_show', 'expired')
+  );
@@ -0,0 +1,9 @@
+-- hold cuma disimpan di redis, reservasi gak pernah berstatus held
+UPDATE booking_events SET from_status = NULL, reason = COALESCE(reason, 'hold confirmed')
+  WHERE from_status = 'held';
+
+ALTER TABLE reservations DROP CONSTRAINT IF EXISTS chk_reservation_status;
+ALTER TABLE reservations
+  ADD CONSTRAINT chk_reservation_status CHECK (
+    status IN ('pending', 'confirmed', 'checked_in', 'completed', 'cancelled', 'no_show', 'expired')
+  );
@@ -0,0 +1 @@
+ALTER TABLE booking_events DROP COLUMN IF EXISTS actor_type;
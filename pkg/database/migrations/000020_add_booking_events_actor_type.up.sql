@@ -0,0 +1,6 @@
+-- aktor event dikirim eksplisit, sistem (promosi waitlist, sweep, webhook) dibedakan dari user yang dihapus
+ALTER TABLE booking_events ADD COLUMN IF NOT EXISTS actor_type VARCHAR(20);
+UPDATE booking_events SET actor_type = CASE WHEN actor_id IS NULL THEN 'system' ELSE 'user' END;
+ALTER TABLE booking_events
+  ALTER COLUMN actor_type SET NOT NULL,
+  ADD CONSTRAINT chk_booking_events_actor_type CHECK (actor_type IN ('user', 'system'));
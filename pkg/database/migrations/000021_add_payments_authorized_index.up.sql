@@ -0,0 +1,3 @@
+-- status authorized: dana di-hold, capture dilakukan setelah commit. job sweep mengulang capture yang belum berhasil
+CREATE INDEX IF NOT EXISTS idx_payments_authorized ON payments(updated_at)
+  WHERE status = 'authorized';
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_refunds_pending;
+ALTER TABLE refunds DROP COLUMN IF EXISTS status;
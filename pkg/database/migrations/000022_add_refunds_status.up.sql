@@ -0,0 +1,8 @@
+-- refund dicatat pending di transaksi cancel / webhook, dikirim ke provider setelah commit.
+-- refund lama sudah dikirim di dalam transaksi, jadi dianggap succeeded
+ALTER TABLE refunds ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'succeeded';
+ALTER TABLE refunds
+  ALTER COLUMN status SET DEFAULT 'pending',
+  ADD CONSTRAINT chk_refunds_status CHECK (status IN ('pending', 'succeeded'));
+CREATE INDEX IF NOT EXISTS idx_refunds_pending ON refunds(created_at)
+  WHERE status = 'pending';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_payments_without_intent;
+-- payment yang belum sempat punya intent gak bisa dibayar lagi, ditandai failed supaya NOT NULL bisa dipasang ulang
+UPDATE payments SET provider_ref = 'missing_' || id, status = 'failed' WHERE provider_ref IS NULL;
+ALTER TABLE payments ALTER COLUMN provider_ref SET NOT NULL;
@@ -0,0 +1,5 @@
+-- payment dibuat bersama reservasi, intent nya dibuat di provider setelah commit. sebelum itu provider_ref masih kosong,
+-- job sweep membuat intent yang belum berhasil
+ALTER TABLE payments ALTER COLUMN provider_ref DROP NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_payments_without_intent ON payments(created_at)
+  WHERE provider_ref IS NULL;
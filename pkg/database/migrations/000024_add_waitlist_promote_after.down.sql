@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_waitlist_promote_after;
+ALTER TABLE waitlist_entries DROP COLUMN IF EXISTS promote_after;
@@ -0,0 +1,5 @@
+-- tawaran expired di-commit terpisah dari promosi antrian berikutnya. promote_after terisi = promosi belum berhasil,
+-- job sweep mencoba lagi urut dari yang paling lama
+ALTER TABLE waitlist_entries ADD COLUMN IF NOT EXISTS promote_after TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS idx_waitlist_promote_after ON waitlist_entries(promote_after)
+  WHERE promote_after IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_user_identities_email_lower;
+DROP INDEX IF EXISTS idx_users_name_lower;
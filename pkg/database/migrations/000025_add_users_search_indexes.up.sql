@@ -0,0 +1,4 @@
+-- pencarian admin user pakai awalan case insensitive: lower(...) LIKE 'abc%'.
+-- text_pattern_ops supaya LIKE awalan bisa pakai index apapun collation database nya
+CREATE INDEX IF NOT EXISTS idx_users_name_lower ON users(lower(name) text_pattern_ops);
+CREATE INDEX IF NOT EXISTS idx_user_identities_email_lower ON user_identities(lower(email) text_pattern_ops);
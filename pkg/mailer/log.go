@@ -0,0 +1,23 @@
+package mailer
+
+import (
+	"context"
+
+	"booking/internal/domain"
+	"booking/pkg/logger"
+)
+
+// logMailer untuk dev, email gak dikirim tapi ditulis ke log (termasuk link verifikasi nya)
+type logMailer struct {
+	from string
+	log  logger.Logger
+}
+
+func NewLogMailer(from string, log logger.Logger) *logMailer {
+	return &logMailer{from: from, log: log}
+}
+
+func (m *logMailer) Send(ctx context.Context, msg *domain.Email) error {
+	m.log.Infof("mail from=%s to=%s subject=%q\n%s", m.from, msg.To, msg.Subject, msg.Body)
+	return nil
+}
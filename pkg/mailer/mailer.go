@@ -0,0 +1,27 @@
+package mailer
+
+import (
+	"fmt"
+
+	"booking/internal/domain"
+	"booking/pkg/config"
+	"booking/pkg/logger"
+)
+
+const (
+	DriverLog  = "log"
+	DriverSMTP = "smtp"
+)
+
+// NewMailer pilih implementasi sesuai MAIL_DRIVER
+func NewMailer(cfg *config.MailConfig, log logger.Logger) domain.Mailer {
+	switch cfg.Driver {
+	case DriverLog:
+		return NewLogMailer(cfg.From, log)
+	case DriverSMTP:
+		return NewSMTPMailer(cfg)
+	default:
+		log.Fatal(fmt.Errorf("unknown mail driver %q", cfg.Driver), "invalid mail config")
+		return nil
+	}
+}
@@ -0,0 +1,67 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"booking/internal/domain"
+)
+
+// githubProvider oauth2 biasa (bukan oidc). email di /user bisa kosong kalau di-private,
+// jadi email primary + status verified nya diambil dari /user/emails
+type githubProvider struct {
+	oauthClient
+}
+
+func (p *githubProvider) Name() string {
+	return domain.ProviderGitHub
+}
+
+func (p *githubProvider) Exchange(ctx context.Context, code, codeVerifier string) (*domain.OAuthUserInfo, error) {
+	accessToken, err := p.exchangeCode(ctx, code, codeVerifier)
+	if err != nil {
+		return nil, err
+	}
+
+	var user struct {
+		ID        int64  `json:"id"`
+		Login     string `json:"login"`
+		Name      string `json:"name"`
+		AvatarURL string `json:"avatar_url"`
+	}
+	if err := p.getJSON(ctx, p.config.UserInfoURL, accessToken, &user); err != nil {
+		return nil, err
+	}
+	if user.ID == 0 {
+		return nil, fmt.Errorf("github user without id")
+	}
+
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err := p.getJSON(ctx, p.config.EmailsURL, accessToken, &emails); err != nil {
+		return nil, err
+	}
+
+	info := &domain.OAuthUserInfo{
+		Provider:   domain.ProviderGitHub,
+		ProviderID: strconv.FormatInt(user.ID, 10),
+		Name:       user.Name,
+		ImageURL:   user.AvatarURL,
+	}
+	if info.Name == "" {
+		info.Name = user.Login
+	}
+	for _, e := range emails {
+		if e.Primary {
+			info.Email = e.Email
+			info.EmailVerified = e.Verified
+			break
+		}
+	}
+
+	return info, nil
+}
@@ -0,0 +1,48 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+
+	"booking/internal/domain"
+)
+
+// googleProvider OIDC, profil diambil dari userinfo endpoint pakai access token
+// jadi id_token gak perlu diverifikasi sendiri
+type googleProvider struct {
+	oauthClient
+}
+
+func (p *googleProvider) Name() string {
+	return domain.ProviderGoogle
+}
+
+func (p *googleProvider) Exchange(ctx context.Context, code, codeVerifier string) (*domain.OAuthUserInfo, error) {
+	accessToken, err := p.exchangeCode(ctx, code, codeVerifier)
+	if err != nil {
+		return nil, err
+	}
+
+	var info struct {
+		Sub           string `json:"sub"`
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		Name          string `json:"name"`
+		Picture       string `json:"picture"`
+	}
+	if err := p.getJSON(ctx, p.config.UserInfoURL, accessToken, &info); err != nil {
+		return nil, err
+	}
+	if info.Sub == "" {
+		return nil, fmt.Errorf("userinfo without sub")
+	}
+
+	return &domain.OAuthUserInfo{
+		Provider:      domain.ProviderGoogle,
+		ProviderID:    info.Sub,
+		Email:         info.Email,
+		EmailVerified: info.EmailVerified,
+		Name:          info.Name,
+		ImageURL:      info.Picture,
+	}, nil
+}
@@ -0,0 +1,146 @@
+package oauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"booking/internal/domain"
+	"booking/pkg/config"
+)
+
+const callbackPath = "/api/v1/auth/oauth/%s/callback"
+
+// NewProviders cuma provider yang ClientID nya diisi yang didaftarkan
+func NewProviders(cfg *config.Config) map[string]domain.OAuthProvider {
+	client := &http.Client{Timeout: 10 * time.Second}
+	providers := make(map[string]domain.OAuthProvider)
+
+	if cfg.OAuth.Google.ClientID != "" {
+		providers[domain.ProviderGoogle] = &googleProvider{
+			oauthClient: newOAuthClient(client, &cfg.OAuth.Google, redirectURL(cfg, domain.ProviderGoogle), "openid email profile"),
+		}
+	}
+	if cfg.OAuth.GitHub.ClientID != "" {
+		providers[domain.ProviderGitHub] = &githubProvider{
+			oauthClient: newOAuthClient(client, &cfg.OAuth.GitHub, redirectURL(cfg, domain.ProviderGitHub), "read:user user:email"),
+		}
+	}
+
+	return providers
+}
+
+// CodeChallenge PKCE S256 = base64url(sha256(verifier)) tanpa padding
+func CodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func redirectURL(cfg *config.Config, provider string) string {
+	return strings.TrimRight(cfg.App.PublicURL, "/") + fmt.Sprintf(callbackPath, provider)
+}
+
+// =============================
+// OAUTH CLIENT (dipakai semua provider)
+// =============================
+
+type oauthClient struct {
+	http        *http.Client
+	config      *config.OAuthProviderConfig
+	redirectURL string
+	scope       string
+}
+
+func newOAuthClient(client *http.Client, cfg *config.OAuthProviderConfig, redirectURL, scope string) oauthClient {
+	return oauthClient{http: client, config: cfg, redirectURL: redirectURL, scope: scope}
+}
+
+func (c *oauthClient) AuthCodeURL(state, codeChallenge string) string {
+	q := url.Values{}
+	q.Set("response_type", "code")
+	q.Set("client_id", c.config.ClientID)
+	q.Set("redirect_uri", c.redirectURL)
+	q.Set("scope", c.scope)
+	q.Set("state", state)
+	q.Set("code_challenge", codeChallenge)
+	q.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(c.config.AuthURL, "?") {
+		sep = "&"
+	}
+	return c.config.AuthURL + sep + q.Encode()
+}
+
+// exchangeCode return access token
+func (c *oauthClient) exchangeCode(ctx context.Context, code, codeVerifier string) (string, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", c.redirectURL)
+	form.Set("client_id", c.config.ClientID)
+	form.Set("client_secret", c.config.ClientSecret)
+	form.Set("code_verifier", codeVerifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	// github default nya balas form-urlencoded
+	req.Header.Set("Accept", "application/json")
+
+	var res struct {
+		AccessToken      string `json:"access_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := c.do(req, &res); err != nil {
+		return "", err
+	}
+	// github balas 200 dengan field error
+	if res.Error != "" {
+		return "", fmt.Errorf("token exchange failed: %s %s", res.Error, res.ErrorDescription)
+	}
+	if res.AccessToken == "" {
+		return "", fmt.Errorf("token exchange failed: empty access token")
+	}
+
+	return res.AccessToken, nil
+}
+
+func (c *oauthClient) getJSON(ctx context.Context, endpoint, accessToken string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+
+	return c.do(req, out)
+}
+
+func (c *oauthClient) do(req *http.Request, out any) error {
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s: status %d: %s", req.Method, req.URL.Path, resp.StatusCode, body)
+	}
+
+	return json.Unmarshal(body, out)
+}
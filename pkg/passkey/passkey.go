@@ -0,0 +1,41 @@
+package passkey
+
+import (
+	"booking/pkg/config"
+	"booking/pkg/logger"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+// NewWebAuthn relying party untuk registrasi & login passkey. config salah = gagal start,
+// sama seperti MFA_ENCRYPTION_KEY
+func NewWebAuthn(cfg *config.WebAuthnConfig, log logger.Logger) *webauthn.WebAuthn {
+	w, err := webauthn.New(&webauthn.Config{
+		RPID:          cfg.RPID,
+		RPDisplayName: cfg.RPDisplayName,
+		RPOrigins:     cfg.RPOrigins,
+		// passkey = discoverable credential + verifikasi user (pin / biometrik) di authenticator
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			RequireResidentKey: protocol.ResidentKeyRequired(),
+			ResidentKey:        protocol.ResidentKeyRequirementRequired,
+			UserVerification:   protocol.VerificationRequired,
+		},
+		AttestationPreference: protocol.PreferNoAttestation,
+		Timeouts: webauthn.TimeoutsConfig{
+			Login: webauthn.TimeoutConfig{
+				Enforce: true,
+				Timeout: cfg.ChallengeTtl,
+			},
+			Registration: webauthn.TimeoutConfig{
+				Enforce: true,
+				Timeout: cfg.ChallengeTtl,
+			},
+		},
+	})
+	if err != nil {
+		log.Fatal(err, "invalid webauthn config")
+	}
+
+	return w
+}
@@ -0,0 +1,83 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+
+	"booking/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+var errInvalidSignature = errors.New("invalid webhook signature")
+
+// fakeProvider provider lokal untuk dev, gak ada dana yang bergerak.
+// pembayaran disimulasikan dengan kirim webhook yang di-sign pakai Sign
+type fakeProvider struct {
+	secret []byte
+}
+
+func NewFakeProvider(secret string) *fakeProvider {
+	return &fakeProvider{secret: []byte(secret)}
+}
+
+func (p *fakeProvider) Name() string {
+	return domain.PaymentProviderFake
+}
+
+func (p *fakeProvider) CreateIntent(ctx context.Context, amount int64, currency, reference, idempotencyKey string) (*domain.PaymentIntent, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+	secret, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.PaymentIntent{
+		ID:           "fake_pi_" + id.String(),
+		ClientSecret: "fake_secret_" + secret.String(),
+	}, nil
+}
+
+func (p *fakeProvider) Capture(ctx context.Context, intentID, idempotencyKey string) error {
+	return nil
+}
+
+func (p *fakeProvider) Refund(ctx context.Context, intentID string, amount int64, idempotencyKey string) error {
+	return nil
+}
+
+// VerifyWebhook signature = hex(HMAC-SHA256(payload, secret))
+func (p *fakeProvider) VerifyWebhook(payload []byte, signature string) (*domain.PaymentEvent, error) {
+	expected, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(expected, p.mac(payload)) {
+		return nil, errInvalidSignature
+	}
+
+	var event domain.PaymentEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, err
+	}
+	if event.ID == "" || event.IntentID == "" {
+		return nil, errors.New("invalid webhook payload")
+	}
+
+	return &event, nil
+}
+
+// Sign buat signature webhook, dipakai untuk simulasi pembayaran di dev
+func (p *fakeProvider) Sign(payload []byte) string {
+	return hex.EncodeToString(p.mac(payload))
+}
+
+func (p *fakeProvider) mac(payload []byte) []byte {
+	h := hmac.New(sha256.New, p.secret)
+	h.Write(payload)
+	return h.Sum(nil)
+}
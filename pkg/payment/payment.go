@@ -0,0 +1,24 @@
+package payment
+
+import (
+	"fmt"
+
+	"booking/internal/domain"
+	"booking/pkg/config"
+	"booking/pkg/logger"
+)
+
+// NewProvider pilih provider sesuai PAYMENT_PROVIDER
+func NewProvider(cfg *config.PaymentConfig, log logger.Logger) domain.PaymentProvider {
+	if cfg.WebhookSecret == "" {
+		log.Fatal(fmt.Errorf("PAYMENT_WEBHOOK_SECRET is empty"), "invalid payment config")
+	}
+
+	switch cfg.Provider {
+	case domain.PaymentProviderFake:
+		return NewFakeProvider(cfg.WebhookSecret)
+	default:
+		log.Fatal(fmt.Errorf("unknown payment provider %q", cfg.Provider), "invalid payment config")
+		return nil
+	}
+}
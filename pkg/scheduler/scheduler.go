@@ -0,0 +1,31 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"booking/pkg/logger"
+)
+
+// Every jalankan job tiap interval sampai ctx selesai. error cukup di log, job tetap jalan di interval berikutnya.
+// aman dijalankan di banyak instance sekaligus selama job nya pakai lock row (e.g: SELECT ... FOR UPDATE)
+func Every(ctx context.Context, interval time.Duration, log logger.Logger, name string, job func(ctx context.Context) error) {
+	if interval <= 0 {
+		log.Warnf("job %s disabled, interval is %s", name, interval)
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := job(ctx); err != nil {
+				log.Errorf(err, "job %s failed", name)
+			}
+		}
+	}
+}
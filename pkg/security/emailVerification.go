@@ -0,0 +1,129 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"booking/internal/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	emailVerifyKey         = "email_verify"          // hash token -> identity + email
+	identityEmailVerifyKey = "identity_email_verify" // identity -> hash token terakhir
+	emailVerifyResendKey   = "email_verify_resend"
+
+	emailChangeKey         = "email_change"          // hash token -> identity + email baru
+	identityEmailChangeKey = "identity_email_change" // identity -> hash token terakhir
+)
+
+// =============================
+// CREATE & CONSUME TOKEN
+// =============================
+
+// CreateEmailVerificationToken token sekali pakai untuk verifikasi email identity.
+// cuma token terakhir yang berlaku, token lama dihapus saat kirim ulang
+func (s *Security) CreateEmailVerificationToken(ctx context.Context, identityID, email string) (string, error) {
+	return s.createEmailToken(ctx, emailVerifyKey, identityEmailVerifyKey, identityID, email)
+}
+
+// ConsumeEmailVerificationToken return (identityID, email). token langsung dihapus, jadi cuma bisa dipakai sekali
+func (s *Security) ConsumeEmailVerificationToken(ctx context.Context, token string) (string, string, error) {
+	return s.consumeEmailToken(ctx, emailVerifyKey, identityEmailVerifyKey, token)
+}
+
+// CreateEmailChangeToken token untuk konfirmasi email baru, email identity baru diganti setelah token dipakai
+func (s *Security) CreateEmailChangeToken(ctx context.Context, identityID, newEmail string) (string, error) {
+	return s.createEmailToken(ctx, emailChangeKey, identityEmailChangeKey, identityID, newEmail)
+}
+
+// ConsumeEmailChangeToken return (identityID, email baru)
+func (s *Security) ConsumeEmailChangeToken(ctx context.Context, token string) (string, string, error) {
+	return s.consumeEmailToken(ctx, emailChangeKey, identityEmailChangeKey, token)
+}
+
+func (s *Security) createEmailToken(ctx context.Context, prefix, identityPrefix, identityID, email string) (string, error) {
+	token, err := generateOpaqueToken(32)
+	if err != nil {
+		s.log.Error(err, "failed to generate email token")
+		return "", err
+	}
+
+	ttl := s.config.App.EmailVerifyTtl
+	tokenKey := fmt.Sprintf("%s:%s", prefix, hashToken(token))
+	identityKey := fmt.Sprintf("%s:%s", identityPrefix, identityID)
+
+	previous, err := s.rdb.Get(ctx, identityKey).Result()
+	if err != nil && err != redis.Nil {
+		return "", err
+	}
+
+	pipe := s.rdb.TxPipeline()
+	if previous != "" {
+		pipe.Del(ctx, fmt.Sprintf("%s:%s", prefix, previous))
+	}
+	pipe.HSet(ctx, tokenKey, map[string]interface{}{
+		"identity_id": identityID,
+		"email":       email,
+	})
+	pipe.Expire(ctx, tokenKey, ttl)
+	pipe.Set(ctx, identityKey, hashToken(token), ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		s.log.Error(err, "failed to store email token pipeline")
+		return "", err
+	}
+
+	return token, nil
+}
+
+func (s *Security) consumeEmailToken(ctx context.Context, prefix, identityPrefix, token string) (string, string, error) {
+	tokenKey := fmt.Sprintf("%s:%s", prefix, hashToken(token))
+
+	pipe := s.rdb.TxPipeline()
+	hgetallCmd := pipe.HGetAll(ctx, tokenKey)
+	pipe.Del(ctx, tokenKey)
+	if _, err := pipe.Exec(ctx); err != nil {
+		s.log.Error(err, "failed to exec consume email token pipeline")
+		return "", "", err
+	}
+
+	data := hgetallCmd.Val()
+	if len(data) == 0 {
+		return "", "", domain.ErrInvalidVerificationToken
+	}
+	_ = s.rdb.Del(ctx, fmt.Sprintf("%s:%s", identityPrefix, data["identity_id"])).Err()
+
+	return data["identity_id"], data["email"], nil
+}
+
+// =============================
+// RESEND LIMITER
+// =============================
+
+// AllowVerificationResend satu email cuma bisa minta kirim ulang sekali per cooldown,
+// return sisa waktu tunggu kalau masih dalam cooldown
+func (s *Security) AllowVerificationResend(ctx context.Context, email string) (time.Duration, error) {
+	return s.allowSendCooldown(ctx, emailVerifyResendKey, email, s.config.App.EmailResendCooldown)
+}
+
+// allowSendCooldown SET NX dengan TTL cooldown, return sisa waktu tunggu kalau key nya masih ada
+func (s *Security) allowSendCooldown(ctx context.Context, prefix, target string, cooldown time.Duration) (time.Duration, error) {
+	key := fmt.Sprintf("%s:%s", prefix, target)
+
+	ok, err := s.rdb.SetNX(ctx, key, "1", cooldown).Result()
+	if err != nil {
+		s.log.Error(err, "failed to set resend cooldown in redis")
+		return 0, err
+	}
+	if ok {
+		return 0, nil
+	}
+
+	ttl, err := s.rdb.TTL(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	return ttl, nil
+}
@@ -0,0 +1,122 @@
+package security
+
+import (
+	"errors"
+	"time"
+
+	"booking/internal/domain"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+type accessTokenClaims struct {
+	Name       string `json:"name"`
+	Role       string `json:"role"`
+	ImageURL   string `json:"image_url,omitempty"`
+	Email      string `json:"email,omitempty"`
+	Provider   string `json:"provider"`
+	ProviderID string `json:"provider_id"`
+	Phone      string `json:"phone,omitempty"`
+	Verified   string `json:"verified"`
+	Device     string `json:"device,omitempty"`
+	FamilyID   string `json:"sid"` // refresh token family, dipakai untuk logout / revoke
+	OrgID      string `json:"org,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// =============================
+// ACCESS TOKEN (JWT)
+// =============================
+
+// GenerateAccessToken sign access token pakai key aktif (JWT_ACTIVE_KID),
+// kid ditaruh di header supaya token tetap bisa diverifikasi setelah key dirotasi. orgID = org aktif (boleh kosong)
+func (s *Security) GenerateAccessToken(data *domain.UserWithIdentity, device string, familyID string, orgID string) (*domain.AccessToken, error) {
+	kid := s.config.JWT.ActiveKid
+	secret, ok := s.config.JWT.Keys[kid]
+	if !ok {
+		return nil, errors.New("jwt active kid not found in keys")
+	}
+
+	jti, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	claims := accessTokenClaims{
+		Name:       data.User.Name,
+		Role:       data.User.Role,
+		ImageURL:   domain.NilStringHandler(data.User.ImageURL),
+		Email:      domain.NilStringHandler(data.UserIdentity.Email),
+		Provider:   data.UserIdentity.Provider,
+		ProviderID: data.UserIdentity.ProviderID,
+		Phone:      domain.NilStringHandler(data.UserIdentity.Phone),
+		Verified:   domain.BoolToString(data.UserIdentity.Verified),
+		Device:     device,
+		FamilyID:   familyID,
+		OrgID:      orgID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti.String(),
+			Issuer:    s.config.JWT.Issuer,
+			Subject:   data.User.ID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.config.JWT.AccessTokenTtl)),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = kid
+
+	signed, err := token.SignedString([]byte(secret))
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.AccessToken{
+		AccessToken: signed,
+		TokenType:   domain.TokenTypeBearer,
+		ExpiresIn:   int64(s.config.JWT.AccessTokenTtl.Seconds()),
+	}, nil
+}
+
+// ParseAccessToken verifikasi signature + expiry, lalu mapping claims ke domain.Session
+// supaya handler gak perlu tau user login pakai cookie atau jwt. return kedua adalah family id (claim sid)
+func (s *Security) ParseAccessToken(tokenString string) (*domain.Session, string, error) {
+	var claims accessTokenClaims
+	_, err := jwt.ParseWithClaims(tokenString, &claims, s.keyFunc,
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(s.config.JWT.Issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		s.log.Debugf("invalid access token: %v", err)
+		return nil, "", domain.ErrInvalidToken
+	}
+
+	return &domain.Session{
+		UserID:     claims.Subject,
+		Name:       claims.Name,
+		Role:       claims.Role,
+		ImageURL:   claims.ImageURL,
+		Email:      claims.Email,
+		Provider:   claims.Provider,
+		ProviderID: claims.ProviderID,
+		Phone:      claims.Phone,
+		Verified:   claims.Verified,
+		Device:     claims.Device,
+		OrgID:      claims.OrgID,
+	}, claims.FamilyID, nil
+}
+
+func (s *Security) keyFunc(token *jwt.Token) (any, error) {
+	kid, ok := token.Header["kid"].(string)
+	if !ok || kid == "" {
+		return nil, errors.New("missing kid header")
+	}
+	secret, ok := s.config.JWT.Keys[kid]
+	if !ok {
+		return nil, errors.New("unknown kid")
+	}
+	return []byte(secret), nil
+}
@@ -0,0 +1,95 @@
+package security
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"fmt"
+	"strings"
+
+	"booking/internal/domain"
+)
+
+const mfaChallengeKey = "mfa_challenge"
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// =============================
+// CHALLENGE
+// =============================
+
+// CreateMfaChallenge return token yang dikirim ke client sebagai mfa_token
+func (s *Security) CreateMfaChallenge(ctx context.Context, challenge *domain.MfaChallenge) (string, error) {
+	token, err := generateOpaqueToken(32)
+	if err != nil {
+		s.log.Error(err, "failed to generate mfa challenge token")
+		return "", err
+	}
+
+	key := generateMfaChallengeKey(token)
+	pipe := s.rdb.TxPipeline()
+	pipe.HSet(ctx, key, challenge.ToRedisMap())
+	pipe.Expire(ctx, key, s.config.Mfa.ChallengeTtl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		s.log.Error(err, "failed to store mfa challenge pipeline")
+		return "", err
+	}
+
+	return token, nil
+}
+
+// GetMfaChallenge challenge gak dihapus waktu kode salah, supaya salah ketik gak perlu login ulang
+func (s *Security) GetMfaChallenge(ctx context.Context, token string) (*domain.MfaChallenge, error) {
+	var challenge domain.MfaChallenge
+	cmd := s.rdb.HGetAll(ctx, generateMfaChallengeKey(token))
+	if err := cmd.Err(); err != nil {
+		s.log.Error(err, "failed to get mfa challenge")
+		return nil, err
+	}
+	if len(cmd.Val()) == 0 {
+		return nil, domain.ErrInvalidMfaChallenge
+	}
+	if err := cmd.Scan(&challenge); err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+// ConsumeMfaChallenge false kalau challenge nya sudah dipakai request lain
+func (s *Security) ConsumeMfaChallenge(ctx context.Context, token string) (bool, error) {
+	deleted, err := s.rdb.Del(ctx, generateMfaChallengeKey(token)).Result()
+	if err != nil {
+		return false, err
+	}
+	return deleted > 0, nil
+}
+
+// =============================
+// RECOVERY CODE
+// =============================
+
+// GenerateRecoveryCodes return (kode asli untuk user, hash untuk disimpan). format xxxxx-xxxxx
+func GenerateRecoveryCodes(n int) ([]string, []string, error) {
+	codes := make([]string, n)
+	hashes := make([]string, n)
+	for i := range codes {
+		b := make([]byte, 7)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		raw := strings.ToLower(recoveryCodeEncoding.EncodeToString(b))[:10]
+		codes[i] = raw[:5] + "-" + raw[5:]
+		hashes[i] = HashRecoveryCode(codes[i])
+	}
+	return codes, hashes, nil
+}
+
+// HashRecoveryCode kode dinormalisasi dulu, user boleh ketik tanpa strip / huruf besar
+func HashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	return hashToken(normalized)
+}
+
+func generateMfaChallengeKey(token string) string {
+	return fmt.Sprintf("%s:%s", mfaChallengeKey, hashToken(token))
+}
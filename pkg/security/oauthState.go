@@ -0,0 +1,83 @@
+package security
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+
+	"booking/internal/domain"
+)
+
+const oauthStateKey = "oauth_state" // state -> provider + code verifier PKCE + hash nonce browser (+ user yang menautkan)
+
+// CreateOAuthState return (state, codeVerifier, nonce). verifier gak pernah keluar dari server,
+// yang dikirim ke provider cuma challenge nya. nonce disimpan di cookie browser yang memulai flow,
+// jadi callback dengan state curian / state milik attacker (login CSRF) ditolak.
+// linkUserID diisi kalau flow nya tautkan akun, bukan login
+func (s *Security) CreateOAuthState(ctx context.Context, provider, linkUserID string) (string, string, string, error) {
+	state, err := generateOpaqueToken(32)
+	if err != nil {
+		s.log.Error(err, "failed to generate oauth state")
+		return "", "", "", err
+	}
+	verifier, err := generateOpaqueToken(32)
+	if err != nil {
+		s.log.Error(err, "failed to generate pkce code verifier")
+		return "", "", "", err
+	}
+	nonce, err := generateOpaqueToken(32)
+	if err != nil {
+		s.log.Error(err, "failed to generate oauth nonce")
+		return "", "", "", err
+	}
+
+	stateKey := generateOAuthStateKey(state)
+	pipe := s.rdb.TxPipeline()
+	pipe.HSet(ctx, stateKey, map[string]interface{}{
+		"provider":      provider,
+		"code_verifier": verifier,
+		"nonce_hash":    hashToken(nonce),
+		"link_user_id":  linkUserID,
+	})
+	pipe.Expire(ctx, stateKey, s.config.OAuth.StateTtl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		s.log.Error(err, "failed to store oauth state pipeline")
+		return "", "", "", err
+	}
+
+	return state, verifier, nonce, nil
+}
+
+// ConsumeOAuthState return (codeVerifier, linkUserID). state sekali pakai, harus untuk provider yang sama dengan callback nya
+// dan nonce dari cookie harus cocok. state baru dihapus setelah cocok, jadi request tanpa cookie gak bisa membuang state orang lain
+func (s *Security) ConsumeOAuthState(ctx context.Context, state, provider, nonce string) (string, string, error) {
+	stateKey := generateOAuthStateKey(state)
+
+	data, err := s.rdb.HGetAll(ctx, stateKey).Result()
+	if err != nil {
+		s.log.Error(err, "failed to get oauth state")
+		return "", "", err
+	}
+	if len(data) == 0 || data["provider"] != provider {
+		return "", "", domain.ErrInvalidOAuthState
+	}
+	if nonce == "" || subtle.ConstantTimeCompare([]byte(hashToken(nonce)), []byte(data["nonce_hash"])) != 1 {
+		return "", "", domain.ErrInvalidOAuthState
+	}
+
+	// callback yang sama bisa masuk bersamaan, cuma yang berhasil menghapus yang lanjut
+	deleted, err := s.rdb.Del(ctx, stateKey).Result()
+	if err != nil {
+		s.log.Error(err, "failed to delete oauth state")
+		return "", "", err
+	}
+	if deleted == 0 {
+		return "", "", domain.ErrInvalidOAuthState
+	}
+
+	return data["code_verifier"], data["link_user_id"], nil
+}
+
+func generateOAuthStateKey(state string) string {
+	return fmt.Sprintf("%s:%s", oauthStateKey, hashToken(state))
+}
@@ -0,0 +1,51 @@
+package security
+
+import (
+	"context"
+	"fmt"
+
+	"booking/internal/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const refreshFamilyOrgKey = "refresh_family_org" // org aktif client mobile, TTL ikut refresh token family
+
+// SetSessionOrg ganti org aktif session web. session yang sudah expired gak dihidupkan lagi
+func (s *Security) SetSessionOrg(ctx context.Context, token, orgID string) error {
+	sessionKey := generateSessionKey(token)
+
+	exists, err := s.rdb.Exists(ctx, sessionKey).Result()
+	if err != nil {
+		return err
+	}
+	if exists == 0 {
+		return domain.ErrUnauthorized
+	}
+	return s.rdb.HSet(ctx, sessionKey, "org_id", orgID).Err()
+}
+
+// SetFamilyOrg ganti org aktif client mobile. access token baru (dan hasil refresh berikutnya) membawa org ini
+func (s *Security) SetFamilyOrg(ctx context.Context, familyID, orgID string) error {
+	exists, err := s.rdb.Exists(ctx, generateRefreshFamilyKey(familyID)).Result()
+	if err != nil {
+		return err
+	}
+	if exists == 0 {
+		return domain.ErrInvalidToken
+	}
+	return s.rdb.Set(ctx, generateRefreshFamilyOrgKey(familyID), orgID, s.config.JWT.RefreshTokenTtl).Err()
+}
+
+// GetFamilyOrg kosong kalau client belum pernah pilih org
+func (s *Security) GetFamilyOrg(ctx context.Context, familyID string) (string, error) {
+	orgID, err := s.rdb.Get(ctx, generateRefreshFamilyOrgKey(familyID)).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return orgID, err
+}
+
+func generateRefreshFamilyOrgKey(familyID string) string {
+	return fmt.Sprintf("%s:%s", refreshFamilyOrgKey, familyID)
+}
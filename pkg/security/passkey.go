@@ -0,0 +1,58 @@
+package security
+
+import (
+	"context"
+	"fmt"
+
+	"booking/internal/domain"
+)
+
+const passkeyChallengeKey = "passkey_challenge" // challenge webauthn -> session data ceremony (+ user yang mendaftarkan)
+
+// CreatePasskeyChallenge sessionData = webauthn.SessionData dalam json, disimpan apa adanya sampai langkah finish
+func (s *Security) CreatePasskeyChallenge(ctx context.Context, ceremony, userID string, sessionData []byte) (string, error) {
+	token, err := generateOpaqueToken(32)
+	if err != nil {
+		s.log.Error(err, "failed to generate passkey challenge token")
+		return "", err
+	}
+
+	key := generatePasskeyChallengeKey(token)
+	pipe := s.rdb.TxPipeline()
+	pipe.HSet(ctx, key, map[string]interface{}{
+		"ceremony":     ceremony,
+		"user_id":      userID,
+		"session_data": sessionData,
+	})
+	pipe.Expire(ctx, key, s.config.WebAuthn.ChallengeTtl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		s.log.Error(err, "failed to store passkey challenge pipeline")
+		return "", err
+	}
+
+	return token, nil
+}
+
+// ConsumePasskeyChallenge return (userID, sessionData). sekali pakai, gagal atau berhasil challenge nya hangus
+func (s *Security) ConsumePasskeyChallenge(ctx context.Context, token, ceremony string) (string, []byte, error) {
+	key := generatePasskeyChallengeKey(token)
+
+	pipe := s.rdb.TxPipeline()
+	hgetallCmd := pipe.HGetAll(ctx, key)
+	delCmd := pipe.Del(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		s.log.Error(err, "failed to exec consume passkey challenge pipeline")
+		return "", nil, err
+	}
+
+	data := hgetallCmd.Val()
+	if delCmd.Val() == 0 || data["ceremony"] != ceremony {
+		return "", nil, domain.ErrInvalidPasskeyChallenge
+	}
+
+	return data["user_id"], []byte(data["session_data"]), nil
+}
+
+func generatePasskeyChallengeKey(token string) string {
+	return fmt.Sprintf("%s:%s", passkeyChallengeKey, hashToken(token))
+}
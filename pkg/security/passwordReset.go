@@ -0,0 +1,92 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"booking/internal/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	passwordResetKey         = "password_reset"          // hash token -> identity + email
+	identityPasswordResetKey = "identity_password_reset" // identity -> hash token terakhir
+	passwordResetRequestKey  = "password_reset_request"
+)
+
+// CreatePasswordResetToken token sekali pakai untuk reset password, cuma token terakhir yang berlaku
+func (s *Security) CreatePasswordResetToken(ctx context.Context, userID, identityID, email string) (string, error) {
+	token, err := generateOpaqueToken(32)
+	if err != nil {
+		s.log.Error(err, "failed to generate password reset token")
+		return "", err
+	}
+
+	ttl := s.config.App.PasswordResetTtl
+	tokenHash := hashToken(token)
+	identityKey := generateIdentityPasswordResetKey(identityID)
+
+	previous, err := s.rdb.Get(ctx, identityKey).Result()
+	if err != nil && err != redis.Nil {
+		return "", err
+	}
+
+	pipe := s.rdb.TxPipeline()
+	if previous != "" {
+		pipe.Del(ctx, generatePasswordResetKey(previous))
+	}
+	pipe.HSet(ctx, generatePasswordResetKey(tokenHash), map[string]interface{}{
+		"user_id":     userID,
+		"identity_id": identityID,
+		"email":       email,
+	})
+	pipe.Expire(ctx, generatePasswordResetKey(tokenHash), ttl)
+	pipe.Set(ctx, identityKey, tokenHash, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		s.log.Error(err, "failed to store password reset token pipeline")
+		return "", err
+	}
+
+	return token, nil
+}
+
+// ConsumePasswordResetToken return (userID, identityID). token harus milik email yang dikirim,
+// dan cuma request pertama yang berhasil menghapus token yang dianggap valid
+func (s *Security) ConsumePasswordResetToken(ctx context.Context, token, email string) (string, string, error) {
+	tokenKey := generatePasswordResetKey(hashToken(token))
+
+	data, err := s.rdb.HGetAll(ctx, tokenKey).Result()
+	if err != nil {
+		s.log.Error(err, "failed to get password reset token")
+		return "", "", err
+	}
+	if len(data) == 0 || data["email"] != email {
+		return "", "", domain.ErrInvalidResetToken
+	}
+
+	deleted, err := s.rdb.Del(ctx, tokenKey).Result()
+	if err != nil {
+		return "", "", err
+	}
+	if deleted == 0 {
+		return "", "", domain.ErrInvalidResetToken
+	}
+	_ = s.rdb.Del(ctx, generateIdentityPasswordResetKey(data["identity_id"])).Err()
+
+	return data["user_id"], data["identity_id"], nil
+}
+
+// AllowPasswordResetRequest cooldown kirim email reset per email, sama dengan kirim ulang verifikasi
+func (s *Security) AllowPasswordResetRequest(ctx context.Context, email string) (time.Duration, error) {
+	return s.allowSendCooldown(ctx, passwordResetRequestKey, email, s.config.App.EmailResendCooldown)
+}
+
+func generatePasswordResetKey(tokenHash string) string {
+	return fmt.Sprintf("%s:%s", passwordResetKey, tokenHash)
+}
+
+func generateIdentityPasswordResetKey(identityID string) string {
+	return fmt.Sprintf("%s:%s", identityPasswordResetKey, identityID)
+}
@@ -0,0 +1,69 @@
+package security
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	phoneOtpKey        = "phone_otp" // nomor -> hash kode
+	phoneOtpRequestKey = "phone_otp_request"
+	phoneOtpDigits     = 6
+)
+
+// CreatePhoneOtp kode 6 digit, yang disimpan cuma hash nya. kode baru menggantikan kode lama
+func (s *Security) CreatePhoneOtp(ctx context.Context, phone string) (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		s.log.Error(err, "failed to generate phone otp")
+		return "", err
+	}
+	code := fmt.Sprintf("%0*d", phoneOtpDigits, n.Int64())
+
+	if err := s.rdb.Set(ctx, generatePhoneOtpKey(phone), hashToken(code), s.config.App.PhoneOtpTtl).Err(); err != nil {
+		s.log.Error(err, "failed to store phone otp in redis")
+		return "", err
+	}
+
+	return code, nil
+}
+
+// VerifyPhoneOtp kode yang benar langsung dihapus (sekali pakai). percobaan yang salah
+// gak menghapus kode, pembatasnya ban login (IncrementAttempts) per nomor
+func (s *Security) VerifyPhoneOtp(ctx context.Context, phone, code string) (bool, error) {
+	key := generatePhoneOtpKey(phone)
+
+	stored, err := s.rdb.Get(ctx, key).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return false, nil
+		}
+		s.log.Error(err, "failed to get phone otp from redis")
+		return false, err
+	}
+	if subtle.ConstantTimeCompare([]byte(stored), []byte(hashToken(code))) != 1 {
+		return false, nil
+	}
+
+	// dua request dengan kode yang sama, cuma yang berhasil menghapus yang dianggap valid
+	deleted, err := s.rdb.Del(ctx, key).Result()
+	if err != nil {
+		return false, err
+	}
+	return deleted > 0, nil
+}
+
+// AllowPhoneOtpRequest cooldown kirim sms per nomor
+func (s *Security) AllowPhoneOtpRequest(ctx context.Context, phone string) (time.Duration, error) {
+	return s.allowSendCooldown(ctx, phoneOtpRequestKey, phone, s.config.App.PhoneOtpCooldown)
+}
+
+func generatePhoneOtpKey(phone string) string {
+	return fmt.Sprintf("%s:%s", phoneOtpKey, phone)
+}
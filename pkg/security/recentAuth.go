@@ -0,0 +1,32 @@
+package security
+
+import (
+	"context"
+	"fmt"
+)
+
+const recentAuthKey = "recent_auth" // hash auth ref -> 1, TTL = jendela re-autentikasi
+
+// MarkRecentAuth dipanggil setelah login / re-autentikasi berhasil.
+// ref = token session (web) atau "family:<id>" (mobile), lihat domain.AuthRefFromContext
+func (s *Security) MarkRecentAuth(ctx context.Context, ref string) error {
+	if err := s.rdb.Set(ctx, generateRecentAuthKey(ref), "1", s.config.App.ReauthTtl).Err(); err != nil {
+		s.log.Error(err, "failed to mark recent auth in redis")
+		return err
+	}
+	return nil
+}
+
+// HasRecentAuth aksi sensitif (tautkan / lepas login method) butuh login yang masih baru
+func (s *Security) HasRecentAuth(ctx context.Context, ref string) (bool, error) {
+	n, err := s.rdb.Exists(ctx, generateRecentAuthKey(ref)).Result()
+	if err != nil {
+		s.log.Error(err, "failed to check recent auth in redis")
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func generateRecentAuthKey(ref string) string {
+	return fmt.Sprintf("%s:%s", recentAuthKey, hashToken(ref))
+}
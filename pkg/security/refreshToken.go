@@ -0,0 +1,243 @@
+package security
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"booking/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	refreshTokenKey        = "refresh_token"
+	refreshFamilyKey       = "refresh_family"
+	userRefreshFamiliesKey = "user_refresh_families"
+)
+
+// Flow refresh token:
+// 1. login mobile → bikin family baru + refresh token pertama.
+// 2. setiap refresh, token lama ditandai rotated_at lalu diganti token baru di family yang sama.
+// 3. kalau token yang sudah rotated dipakai lagi → dianggap dicuri, satu family di-revoke + semua session user dimatikan.
+// Token lama sengaja gak dihapus sampai TTL habis, supaya reuse masih bisa dideteksi.
+
+// markRotatedScript cek token masih ada + HSETNX rotated_at dalam satu langkah. kalau dipisah, token yang
+// expired di antara keduanya dibuat ulang oleh HSETNX sebagai hash tanpa TTL yang gak pernah hilang.
+// return -1 token gak ada, 0 sudah pernah di-rotate (reuse), 1 berhasil
+var markRotatedScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return -1
+end
+return redis.call('HSETNX', KEYS[1], 'rotated_at', ARGV[1])
+`)
+
+// =============================
+// CREATE & ROTATE REFRESH TOKEN
+// =============================
+
+// CreateRefreshToken bikin family baru dan refresh token pertamanya, return (token, familyID)
+func (s *Security) CreateRefreshToken(
+	ctx context.Context,
+	data *domain.UserWithIdentity,
+	device string,
+	userAgent string,
+	ipAddress string,
+) (string, string, error) {
+	familyID, err := uuid.NewV7()
+	if err != nil {
+		s.log.Error(err, "failed to generate refresh token family id")
+		return "", "", err
+	}
+
+	rt := domain.RefreshToken{
+		UserID:     data.User.ID,
+		IdentityID: data.UserIdentity.ID,
+		FamilyID:   familyID.String(),
+		Device:     device,
+		UserAgent:  userAgent,
+		IpAddress:  ipAddress,
+	}
+
+	token, err := s.storeRefreshToken(ctx, &rt)
+	if err != nil {
+		return "", "", err
+	}
+	// login baru = autentikasi baru
+	if err := s.MarkRecentAuth(ctx, "family:"+rt.FamilyID); err != nil {
+		return "", "", err
+	}
+
+	return token, rt.FamilyID, nil
+}
+
+// RotateRefreshToken tukar refresh token lama dengan yang baru di family yang sama
+func (s *Security) RotateRefreshToken(ctx context.Context, token string) (*domain.RefreshToken, string, error) {
+	tokenKey := generateRefreshTokenKey(hashToken(token))
+
+	hgetallCmd := s.rdb.HGetAll(ctx, tokenKey)
+	if err := hgetallCmd.Err(); err != nil {
+		s.log.Error(err, "failed to get refresh token")
+		return nil, "", domain.ErrInternalServerError
+	}
+	if len(hgetallCmd.Val()) == 0 {
+		return nil, "", domain.ErrInvalidToken
+	}
+
+	var rt domain.RefreshToken
+	if err := hgetallCmd.Scan(&rt); err != nil {
+		s.log.Error(err, "failed to scan refresh token")
+		return nil, "", domain.ErrInternalServerError
+	}
+
+	// atomic, jadi kalau 2 request pakai token yang sama bersamaan cuma 1 yang menang
+	marked, err := markRotatedScript.Run(ctx, s.rdb, []string{tokenKey}, time.Now().Unix()).Int()
+	if err != nil {
+		s.log.Error(err, "failed to mark refresh token as rotated")
+		return nil, "", domain.ErrInternalServerError
+	}
+	if marked < 0 {
+		return nil, "", domain.ErrInvalidToken
+	}
+	if marked == 0 {
+		s.log.Warnf("refresh token reuse detected for user:%s family:%s", rt.UserID, rt.FamilyID)
+		if err := s.RevokeRefreshFamily(ctx, rt.UserID, rt.FamilyID); err != nil {
+			s.log.Error(err, "failed to revoke refresh token family")
+		}
+		if err := s.LogoutAllSessions(ctx, rt.UserID); err != nil {
+			s.log.Error(err, "failed to logout all sessions")
+		}
+		return nil, "", domain.ErrInvalidToken
+	}
+
+	// family sudah di-revoke (logout / reuse sebelumnya)
+	exists, err := s.rdb.Exists(ctx, generateRefreshFamilyKey(rt.FamilyID)).Result()
+	if err != nil {
+		s.log.Error(err, "failed to check refresh token family")
+		return nil, "", domain.ErrInternalServerError
+	}
+	if exists == 0 {
+		return nil, "", domain.ErrInvalidToken
+	}
+
+	newToken, err := s.storeRefreshToken(ctx, &rt)
+	if err != nil {
+		return nil, "", domain.ErrInternalServerError
+	}
+
+	return &rt, newToken, nil
+}
+
+// FamilyActive family yang sudah di-revoke (logout / reuse terdeteksi) gak boleh dipakai lagi,
+// termasuk access token yang terbit dari family tsb sebelum di-revoke
+func (s *Security) FamilyActive(ctx context.Context, familyID string) (bool, error) {
+	n, err := s.rdb.Exists(ctx, generateRefreshFamilyKey(familyID)).Result()
+	if err != nil {
+		s.log.Error(err, "failed to check refresh token family")
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// =============================
+// REVOKE
+// =============================
+
+func (s *Security) RevokeRefreshFamily(ctx context.Context, userID, familyID string) error {
+	pipe := s.rdb.Pipeline()
+	pipe.Del(ctx, generateRefreshFamilyKey(familyID))
+	pipe.Del(ctx, generateRefreshFamilyOrgKey(familyID))
+	pipe.SRem(ctx, generateUserRefreshFamiliesKey(userID), familyID)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (s *Security) RevokeAllRefreshFamilies(ctx context.Context, userID string) error {
+	userFamiliesKey := generateUserRefreshFamiliesKey(userID)
+	families, err := s.rdb.SMembers(ctx, userFamiliesKey).Result()
+	if err != nil {
+		return err
+	}
+
+	pipe := s.rdb.Pipeline()
+	for _, familyID := range families {
+		pipe.Del(ctx, generateRefreshFamilyKey(familyID))
+		pipe.Del(ctx, generateRefreshFamilyOrgKey(familyID))
+	}
+	pipe.Del(ctx, userFamiliesKey)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// RevokeOtherRefreshFamilies revoke semua family kecuali yang sedang dipakai (kosong = revoke semua)
+func (s *Security) RevokeOtherRefreshFamilies(ctx context.Context, userID, currentFamilyID string) error {
+	userFamiliesKey := generateUserRefreshFamiliesKey(userID)
+	families, err := s.rdb.SMembers(ctx, userFamiliesKey).Result()
+	if err != nil {
+		return err
+	}
+
+	pipe := s.rdb.Pipeline()
+	for _, familyID := range families {
+		if familyID == currentFamilyID {
+			continue
+		}
+		pipe.Del(ctx, generateRefreshFamilyKey(familyID))
+		pipe.Del(ctx, generateRefreshFamilyOrgKey(familyID))
+		pipe.SRem(ctx, userFamiliesKey, familyID)
+	}
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// =============================
+// HELPERS
+// =============================
+
+func (s *Security) storeRefreshToken(ctx context.Context, rt *domain.RefreshToken) (string, error) {
+	token, err := generateOpaqueToken(32)
+	if err != nil {
+		s.log.Error(err, "failed to generate refresh token")
+		return "", err
+	}
+
+	ttl := s.config.JWT.RefreshTokenTtl
+	tokenKey := generateRefreshTokenKey(hashToken(token))
+	familyKey := generateRefreshFamilyKey(rt.FamilyID)
+	userFamiliesKey := generateUserRefreshFamiliesKey(rt.UserID)
+
+	pipe := s.rdb.Pipeline()
+	pipe.HSet(ctx, tokenKey, rt.ToRedisMap())
+	pipe.Expire(ctx, tokenKey, ttl)
+	pipe.Set(ctx, familyKey, rt.UserID, ttl)
+	pipe.Expire(ctx, generateRefreshFamilyOrgKey(rt.FamilyID), ttl)
+	pipe.SAdd(ctx, userFamiliesKey, rt.FamilyID)
+	pipe.Expire(ctx, userFamiliesKey, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		s.log.Error(err, "failed to store refresh token pipeline")
+		return "", err
+	}
+
+	return token, nil
+}
+
+// token disimpan dalam bentuk hash, jadi kalau redis bocor token aslinya gak ikut bocor
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func generateRefreshTokenKey(tokenHash string) string {
+	return fmt.Sprintf("%s:%s", refreshTokenKey, tokenHash)
+}
+
+func generateRefreshFamilyKey(familyID string) string {
+	return fmt.Sprintf("%s:%s", refreshFamilyKey, familyID)
+}
+
+func generateUserRefreshFamiliesKey(userID string) string {
+	return fmt.Sprintf("%s:%s", userRefreshFamiliesKey, userID)
+}
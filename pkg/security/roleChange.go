@@ -0,0 +1,39 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const userRoleKey = "user_role" // user id -> role terbaru, TTL = umur access token
+
+// MarkRoleChanged role di session web langsung diganti, tapi access token jwt bawa role lama di claim nya.
+// role terbaru disimpan selama umur access token, token dengan role berbeda ditolak dan client harus refresh
+// (refresh selalu ambil role dari db)
+func (s *Security) MarkRoleChanged(ctx context.Context, userID, role string) error {
+	if err := s.rdb.Set(ctx, generateUserRoleKey(userID), role, s.config.JWT.AccessTokenTtl).Err(); err != nil {
+		s.log.Error(err, "failed to mark user role changed in redis")
+		return err
+	}
+	return nil
+}
+
+// ChangedRole role terbaru kalau berubah dalam umur access token, kosong kalau gak ada perubahan
+func (s *Security) ChangedRole(ctx context.Context, userID string) (string, error) {
+	role, err := s.rdb.Get(ctx, generateUserRoleKey(userID)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", nil
+		}
+		s.log.Error(err, "failed to get changed user role from redis")
+		return "", err
+	}
+	return role, nil
+}
+
+func generateUserRoleKey(userID string) string {
+	return fmt.Sprintf("%s:%s", userRoleKey, userID)
+}
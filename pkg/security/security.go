@@ -1,6 +1,11 @@
 package security
 
 import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"fmt"
+
 	"booking/pkg/config"
 	"booking/pkg/logger"
 
@@ -11,8 +16,28 @@ type Security struct {
 	config *config.Config
 	rdb    *redis.Client
 	log    logger.Logger
+	mfaKey cipher.AEAD // enkripsi secret totp
 }
 
 func NewSecurity(config *config.Config, rdb *redis.Client, log logger.Logger) *Security {
-	return &Security{config: config, rdb: rdb, log: log}
+	mfaKey, err := newAEAD(config.Mfa.EncryptionKey)
+	if err != nil {
+		log.Fatal(err, "invalid MFA_ENCRYPTION_KEY")
+	}
+	return &Security{config: config, rdb: rdb, log: log, mfaKey: mfaKey}
+}
+
+func newAEAD(encodedKey string) (cipher.AEAD, error) {
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("key must be 32 bytes, got %d", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
 }
@@ -57,6 +57,8 @@ func (s *Security) CreateSession(
 		Member: token,
 	})
 	pipe.Expire(ctx, userSessionsKey, s.config.App.AuthSessionsTtl)
+	// login baru = autentikasi baru
+	pipe.Set(ctx, generateRecentAuthKey(token), "1", s.config.App.ReauthTtl)
 	_, err = pipe.Exec(ctx)
 	if err != nil {
 		s.log.Error(err, "failed to create session pipeline")
@@ -186,6 +188,70 @@ func (s *Security) GetUserActiveSessionsWithDetails(ctx context.Context, userID
 	return sessionsWithExpiry, nil
 }
 
+// =============================
+// UPDATE SESSIONS
+// =============================
+
+// UpdateUserSessions update field level user di semua session aktif user (e.g: name, role),
+// supaya perubahan data user langsung kebaca tanpa login ulang
+func (s *Security) UpdateUserSessions(ctx context.Context, userID string, values map[string]interface{}) error {
+	return s.updateSessions(ctx, userID, "", values)
+}
+
+// UpdateIdentitySessions sama dengan UpdateUserSessions tapi cuma session yang login lewat provider tsb,
+// dipakai untuk field milik identity (email, provider_id, verified)
+func (s *Security) UpdateIdentitySessions(ctx context.Context, userID, provider string, values map[string]interface{}) error {
+	return s.updateSessions(ctx, userID, provider, values)
+}
+
+func (s *Security) updateSessions(ctx context.Context, userID, provider string, values map[string]interface{}) error {
+	tokens, err := s.activeSessionTokens(ctx, userID, provider)
+	if err != nil || len(tokens) == 0 {
+		return err
+	}
+
+	pipe := s.rdb.Pipeline()
+	for _, token := range tokens {
+		pipe.HSet(ctx, generateSessionKey(token), values)
+	}
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// activeSessionTokens token session user yang masih hidup, provider kosong = semua provider.
+// HSET ke key yang sudah expired bakal bikin session baru tanpa TTL, jadi harus dicek dulu
+func (s *Security) activeSessionTokens(ctx context.Context, userID, provider string) ([]string, error) {
+	tokens, err := s.rdb.ZRange(ctx, generateUserSessionsKey(userID), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	pipe := s.rdb.Pipeline()
+	providerCmds := make([]*redis.StringCmd, len(tokens))
+	for i, token := range tokens {
+		providerCmds[i] = pipe.HGet(ctx, generateSessionKey(token), "provider")
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	active := make([]string, 0, len(tokens))
+	for i, token := range tokens {
+		current, err := providerCmds[i].Result()
+		if err != nil {
+			continue
+		}
+		if provider != "" && current != provider {
+			continue
+		}
+		active = append(active, token)
+	}
+	return active, nil
+}
+
 // =============================
 // LOGOUT
 // =============================
@@ -202,6 +268,11 @@ func (s *Security) LogoutSession(ctx context.Context, userID, token string) erro
 }
 
 func (s *Security) LogoutAllSessions(ctx context.Context, userID string) error {
+	// client mobile (refresh token) juga ikut di-logout
+	if err := s.RevokeAllRefreshFamilies(ctx, userID); err != nil {
+		return err
+	}
+
 	userSessionsKey := generateUserSessionsKey(userID)
 	tokens, err := s.rdb.ZRange(ctx, userSessionsKey, 0, -1).Result()
 	if err != nil {
@@ -220,6 +291,23 @@ func (s *Security) LogoutAllSessions(ctx context.Context, userID string) error {
 	return err
 }
 
+// LogoutProviderSessions matikan session yang login lewat provider tsb, dipakai setelah login method dilepas
+func (s *Security) LogoutProviderSessions(ctx context.Context, userID, provider string) error {
+	tokens, err := s.activeSessionTokens(ctx, userID, provider)
+	if err != nil || len(tokens) == 0 {
+		return err
+	}
+
+	userSessionsKey := generateUserSessionsKey(userID)
+	pipe := s.rdb.Pipeline()
+	for _, token := range tokens {
+		pipe.Del(ctx, generateSessionKey(token))
+		pipe.ZRem(ctx, userSessionsKey, token)
+	}
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
 func (s *Security) LogoutOtherSessions(ctx context.Context, userID, currentToken string) error {
 	userSessionsKey := generateUserSessionsKey(userID)
 	tokens, err := s.rdb.ZRange(ctx, userSessionsKey, 0, -1).Result()
@@ -253,6 +341,7 @@ func mapToSession(data map[string]string, session *domain.Session) error {
 	session.ProviderID = data["provider_id"]
 	session.Phone = data["phone"]
 	session.Verified = data["verified"]
+	session.OrgID = data["org_id"]
 	session.Device = data["device"]
 	session.UserAgent = data["user_agent"]
 	session.IpAddress = data["ip_address"]
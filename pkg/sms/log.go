@@ -0,0 +1,21 @@
+package sms
+
+import (
+	"context"
+
+	"booking/pkg/logger"
+)
+
+// logSender untuk dev, sms gak dikirim tapi ditulis ke log (termasuk kode otp nya)
+type logSender struct {
+	log logger.Logger
+}
+
+func NewLogSender(log logger.Logger) *logSender {
+	return &logSender{log: log}
+}
+
+func (s *logSender) Send(ctx context.Context, to, message string) error {
+	s.log.Infof("sms to=%s\n%s", to, message)
+	return nil
+}
@@ -0,0 +1,22 @@
+package sms
+
+import (
+	"fmt"
+
+	"booking/internal/domain"
+	"booking/pkg/config"
+	"booking/pkg/logger"
+)
+
+const DriverLog = "log"
+
+// NewSender pilih implementasi sesuai SMS_DRIVER
+func NewSender(cfg *config.SmsConfig, log logger.Logger) domain.SmsSender {
+	switch cfg.Driver {
+	case DriverLog:
+		return NewLogSender(log)
+	default:
+		log.Fatal(fmt.Errorf("unknown sms driver %q", cfg.Driver), "invalid sms config")
+		return nil
+	}
+}
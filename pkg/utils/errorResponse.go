@@ -39,6 +39,115 @@ func ErrorResponse(c fiber.Ctx, err error, data any) error {
 	case errors.Is(err, domain.ErrUserAlreadyExists):
 		response.Message = domain.ErrUserAlreadyExists.Error()
 		statusCode = fiber.StatusConflict
+	case errors.Is(err, domain.ErrAccountDisabled):
+		response.Message = domain.ErrAccountDisabled.Error()
+		statusCode = fiber.StatusForbidden
+	// booking error
+	case errors.Is(err, domain.ErrResourceNotFound):
+		response.Message = domain.ErrResourceNotFound.Error()
+		statusCode = fiber.StatusNotFound
+	case errors.Is(err, domain.ErrBookingNotFound):
+		response.Message = domain.ErrBookingNotFound.Error()
+		statusCode = fiber.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidBookingTime):
+		response.Message = domain.ErrInvalidBookingTime.Error()
+		statusCode = fiber.StatusBadRequest
+	case errors.Is(err, domain.ErrOutsideOpeningHours):
+		response.Message = domain.ErrOutsideOpeningHours.Error()
+		statusCode = fiber.StatusUnprocessableEntity
+	case errors.Is(err, domain.ErrSlotUnavailable):
+		response.Message = domain.ErrSlotUnavailable.Error()
+		statusCode = fiber.StatusConflict
+		// kirim window yang bentrok supaya client bisa pilih waktu lain
+		var slotErr *domain.SlotUnavailableError
+		if errors.As(err, &slotErr) {
+			response.Data = slotErr
+		}
+	case errors.Is(err, domain.ErrInvalidTransition):
+		response.Message = domain.ErrInvalidTransition.Error()
+		statusCode = fiber.StatusConflict
+		var transitionErr *domain.InvalidTransitionError
+		if errors.As(err, &transitionErr) {
+			response.Data = transitionErr
+		}
+	case errors.Is(err, domain.ErrBookingNotCancellable):
+		response.Message = domain.ErrBookingNotCancellable.Error()
+		statusCode = fiber.StatusConflict
+	case errors.Is(err, domain.ErrBookingNotModifiable):
+		response.Message = domain.ErrBookingNotModifiable.Error()
+		statusCode = fiber.StatusConflict
+	case errors.Is(err, domain.ErrHoldNotFound):
+		response.Message = domain.ErrHoldNotFound.Error()
+		statusCode = fiber.StatusNotFound
+	case errors.Is(err, domain.ErrSeriesNotFound):
+		response.Message = domain.ErrSeriesNotFound.Error()
+		statusCode = fiber.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidRecurrence):
+		response.Message = domain.ErrInvalidRecurrence.Error()
+		statusCode = fiber.StatusBadRequest
+	case errors.Is(err, domain.ErrWaitlistNotFound):
+		response.Message = domain.ErrWaitlistNotFound.Error()
+		statusCode = fiber.StatusNotFound
+	case errors.Is(err, domain.ErrAlreadyWaitlisted):
+		response.Message = domain.ErrAlreadyWaitlisted.Error()
+		statusCode = fiber.StatusConflict
+	case errors.Is(err, domain.ErrSlotStillAvailable):
+		response.Message = domain.ErrSlotStillAvailable.Error()
+		statusCode = fiber.StatusConflict
+	case errors.Is(err, domain.ErrWaitlistOfferExpired):
+		response.Message = domain.ErrWaitlistOfferExpired.Error()
+		statusCode = fiber.StatusGone
+	case errors.Is(err, domain.ErrPartySizeTooLarge):
+		response.Message = domain.ErrPartySizeTooLarge.Error()
+		statusCode = fiber.StatusUnprocessableEntity
+	case errors.Is(err, domain.ErrRatePlanNotFound):
+		response.Message = domain.ErrRatePlanNotFound.Error()
+		statusCode = fiber.StatusNotFound
+	case errors.Is(err, domain.ErrOrgRequired):
+		response.Message = domain.ErrOrgRequired.Error()
+		statusCode = fiber.StatusBadRequest
+	case errors.Is(err, domain.ErrOrgNotFound):
+		response.Message = domain.ErrOrgNotFound.Error()
+		statusCode = fiber.StatusNotFound
+	case errors.Is(err, domain.ErrOrgSlugExists):
+		response.Message = domain.ErrOrgSlugExists.Error()
+		statusCode = fiber.StatusConflict
+	case errors.Is(err, domain.ErrOrgMemberNotFound):
+		response.Message = domain.ErrOrgMemberNotFound.Error()
+		statusCode = fiber.StatusNotFound
+	case errors.Is(err, domain.ErrLastOrgAdmin):
+		response.Message = domain.ErrLastOrgAdmin.Error()
+		statusCode = fiber.StatusConflict
+	case errors.Is(err, domain.ErrCouponNotFound):
+		response.Message = domain.ErrCouponNotFound.Error()
+		statusCode = fiber.StatusNotFound
+	case errors.Is(err, domain.ErrCouponCodeExists):
+		response.Message = domain.ErrCouponCodeExists.Error()
+		statusCode = fiber.StatusConflict
+	case errors.Is(err, domain.ErrCouponExpired):
+		response.Message = domain.ErrCouponExpired.Error()
+		statusCode = fiber.StatusUnprocessableEntity
+	case errors.Is(err, domain.ErrCouponExhausted):
+		response.Message = domain.ErrCouponExhausted.Error()
+		statusCode = fiber.StatusUnprocessableEntity
+	case errors.Is(err, domain.ErrCouponNotApplicable):
+		response.Message = domain.ErrCouponNotApplicable.Error()
+		statusCode = fiber.StatusUnprocessableEntity
+	// payment error
+	case errors.Is(err, domain.ErrPaymentNotFound):
+		response.Message = domain.ErrPaymentNotFound.Error()
+		statusCode = fiber.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidWebhookSignature):
+		response.Message = domain.ErrInvalidWebhookSignature.Error()
+		statusCode = fiber.StatusBadRequest
+	case errors.Is(err, domain.ErrSeriesConflict):
+		response.Message = domain.ErrSeriesConflict.Error()
+		statusCode = fiber.StatusConflict
+		// detail bentrok per tanggal
+		var seriesErr *domain.SeriesConflictError
+		if errors.As(err, &seriesErr) {
+			response.Data = seriesErr
+		}
 	// jwt error
 	case errors.Is(err, domain.ErrInvalidToken):
 		response.Message = domain.ErrInvalidToken.Error()
@@ -47,6 +156,57 @@ func ErrorResponse(c fiber.Ctx, err error, data any) error {
 	case errors.Is(err, domain.ErrInvalidCredentials):
 		response.Message = domain.ErrInvalidCredentials.Error()
 		statusCode = fiber.StatusUnauthorized
+	case errors.Is(err, domain.ErrInvalidVerificationToken):
+		response.Message = domain.ErrInvalidVerificationToken.Error()
+		statusCode = fiber.StatusBadRequest
+	case errors.Is(err, domain.ErrInvalidResetToken):
+		response.Message = domain.ErrInvalidResetToken.Error()
+		statusCode = fiber.StatusBadRequest
+	case errors.Is(err, domain.ErrOAuthProviderNotFound):
+		response.Message = domain.ErrOAuthProviderNotFound.Error()
+		statusCode = fiber.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidOAuthState):
+		response.Message = domain.ErrInvalidOAuthState.Error()
+		statusCode = fiber.StatusBadRequest
+	case errors.Is(err, domain.ErrOAuthFailed):
+		response.Message = domain.ErrOAuthFailed.Error()
+		statusCode = fiber.StatusBadGateway
+	case errors.Is(err, domain.ErrReauthRequired):
+		response.Message = domain.ErrReauthRequired.Error()
+		statusCode = fiber.StatusForbidden
+	case errors.Is(err, domain.ErrIdentityAlreadyLinked):
+		response.Message = domain.ErrIdentityAlreadyLinked.Error()
+		statusCode = fiber.StatusConflict
+	case errors.Is(err, domain.ErrLastIdentity):
+		response.Message = domain.ErrLastIdentity.Error()
+		statusCode = fiber.StatusConflict
+	case errors.Is(err, domain.ErrIdentityNotFound):
+		response.Message = domain.ErrIdentityNotFound.Error()
+		statusCode = fiber.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidOtp):
+		response.Message = domain.ErrInvalidOtp.Error()
+		statusCode = fiber.StatusUnauthorized
+	case errors.Is(err, domain.ErrInvalidMfaChallenge):
+		response.Message = domain.ErrInvalidMfaChallenge.Error()
+		statusCode = fiber.StatusUnauthorized
+	case errors.Is(err, domain.ErrInvalidMfaCode):
+		response.Message = domain.ErrInvalidMfaCode.Error()
+		statusCode = fiber.StatusUnauthorized
+	case errors.Is(err, domain.ErrMfaAlreadyEnabled):
+		response.Message = domain.ErrMfaAlreadyEnabled.Error()
+		statusCode = fiber.StatusConflict
+	case errors.Is(err, domain.ErrMfaNotEnabled):
+		response.Message = domain.ErrMfaNotEnabled.Error()
+		statusCode = fiber.StatusBadRequest
+	case errors.Is(err, domain.ErrInvalidPasskeyChallenge):
+		response.Message = domain.ErrInvalidPasskeyChallenge.Error()
+		statusCode = fiber.StatusBadRequest
+	case errors.Is(err, domain.ErrPasskeyFailed):
+		response.Message = domain.ErrPasskeyFailed.Error()
+		statusCode = fiber.StatusUnauthorized
+	case errors.Is(err, domain.ErrPasskeyAlreadyRegistered):
+		response.Message = domain.ErrPasskeyAlreadyRegistered.Error()
+		statusCode = fiber.StatusConflict
 	case errors.Is(err, domain.ErrUnauthorized):
 		response.Message = domain.ErrUnauthorized.Error()
 		statusCode = fiber.StatusUnauthorized